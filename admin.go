@@ -0,0 +1,289 @@
+package rtree
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultAdminPrefix = "/__routes"
+
+	adminStatsPath = "/stats"
+	adminTestParam = "test"
+)
+
+// AdminOption configures the handler returned by AdminHandler.
+type AdminOption[T storeValue] func(*adminHandler[T])
+
+// ValueEncoder converts a stored value to its JSON representation.
+type ValueEncoder[T storeValue] func(value T) any
+
+type adminHandler[T storeValue] struct {
+	tree    *Tree[T]
+	prefix  string
+	encoder ValueEncoder[T]
+
+	// listing is the encoded route listing of the tree, which is
+	// only built again, once the generation of the tree changes.
+	mu      sync.Mutex
+	listing *adminListing
+}
+
+type adminListing struct {
+	generation uint64
+	body       []byte
+	etag       string
+}
+
+type adminRoute struct {
+	Pattern string      `json:"pattern"`
+	Params  []ParamInfo `json:"params"`
+	Source  string      `json:"source,omitempty"`
+	Value   any         `json:"value,omitempty"`
+}
+
+type adminRoutes struct {
+	Routes []adminRoute `json:"routes"`
+}
+
+type adminStats struct {
+	Routes     int    `json:"routes"`
+	Nodes      int    `json:"nodes"`
+	Generation uint64 `json:"generation"`
+	Hash       string `json:"hash"`
+}
+
+type adminTest struct {
+	Key     string            `json:"key"`
+	Matched bool              `json:"matched"`
+	Pattern string            `json:"pattern,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+	Value   any               `json:"value,omitempty"`
+}
+
+// WithAdminPrefix sets the path the admin handler is served on.
+// By default it is „/__routes”.
+func WithAdminPrefix[T storeValue](prefix string) AdminOption[T] {
+	return func(ah *adminHandler[T]) {
+		ah.prefix = prefix
+	}
+}
+
+// WithAdminValueEncoder makes the admin handler include the stored values
+// in its responses, in the form returned by the encoder.
+func WithAdminValueEncoder[T storeValue](enc ValueEncoder[T]) AdminOption[T] {
+	return func(ah *adminHandler[T]) {
+		ah.encoder = enc
+	}
+}
+
+// AdminHandler returns a debug handler of the given tree, which serves:
+//
+//	GET /__routes                       -> JSON listing of all the routes
+//	GET /__routes/stats                 -> JSON stats of the tree
+//	GET /__routes?test=/api/users/5     -> JSON result of matching the given key
+//
+// The listing is built once per generation of the tree, and it is served
+// with an ETag – the hash of its content –, so the dashboards polling it
+// with If-None-Match only get a 304 Not Modified, until the table changes.
+// The key of a test is matched by Resolve, so the test does not consume
+// the tokens of the rate-limits, and the trailing slash policy and the
+// default route are not applied.
+func AdminHandler[T storeValue](t *Tree[T], opts ...AdminOption[T]) http.Handler {
+	ah := &adminHandler[T]{
+		tree:   t,
+		prefix: defaultAdminPrefix,
+	}
+
+	for _, o := range opts {
+		o(ah)
+	}
+
+	return ah
+}
+
+func (ah *adminHandler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch strings.TrimPrefix(r.URL.Path, ah.prefix) {
+	case "", "/":
+		if r.URL.Query().Has(adminTestParam) {
+			ah.writeJSON(w, ah.test(r.URL.Query().Get(adminTestParam)))
+			return
+		}
+
+		ah.writeListing(w, r)
+	case adminStatsPath:
+		ah.writeJSON(w, ah.stats())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (ah *adminHandler[T]) encode(value T) any {
+	if ah.encoder == nil {
+		return nil
+	}
+
+	return ah.encoder(value)
+}
+
+// routes returns the listing of the routes. The leaves are read under
+// the read lock of the tree, since a mutation replaces their values.
+func (ah *adminHandler[T]) routes() adminRoutes {
+	var (
+		t      = ah.tree
+		routes = make([]adminRoute, 0)
+		values = make([]T, 0)
+	)
+
+	if checkTree(t) == nil {
+		t.mu.RLock()
+
+		for _, l := range getAllLeafRec(t.root) {
+			routes = append(routes, adminRoute{
+				Pattern: l.value.Pattern(),
+				Params:  l.value.ParamInfos(),
+				Source:  l.value.Source(),
+			})
+
+			values = append(values, t.resolve(l.value).value)
+		}
+
+		t.mu.RUnlock()
+	}
+
+	// The values are encoded without holding the lock.
+	for i := range routes {
+		routes[i].Value = ah.encode(values[i])
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Pattern < routes[j].Pattern
+	})
+
+	return adminRoutes{Routes: routes}
+}
+
+// writeListing writes the route listing, or 304 Not Modified,
+// if the client already has the current one.
+func (ah *adminHandler[T]) writeListing(w http.ResponseWriter, r *http.Request) {
+	listing, err := ah.currentListing()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", listing.etag)
+	// The clients must always check, whether their copy is still current.
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), listing.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(listing.body)
+}
+
+// currentListing returns the listing of the current generation of the tree.
+func (ah *adminHandler[T]) currentListing() (*adminListing, error) {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+
+	// The generation is read before the listing is built, so a
+	// mutation running at the same time could only make it stale.
+	generation := ah.tree.Generation()
+
+	if ah.listing != nil && ah.listing.generation == generation {
+		return ah.listing, nil
+	}
+
+	body, err := json.Marshal(ah.routes())
+	if err != nil {
+		return nil, err
+	}
+
+	// The same as the output of json.Encoder.
+	body = append(body, '\n')
+
+	h := fnv.New64a()
+	h.Write(body)
+
+	ah.listing = &adminListing{
+		generation: generation,
+		body:       body,
+		etag:       strconv.Quote(strconv.FormatUint(h.Sum64(), 16)),
+	}
+
+	return ah.listing, nil
+}
+
+// etagMatches reports whether the If-None-Match header
+// holds the given ETag – compared weakly, as RFC 9110 says.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ah *adminHandler[T]) stats() adminStats {
+	nodes := 0
+
+	// The error is deliberately ignored, a nil tree simply has no nodes.
+	_ = ah.tree.Walk(func(n *Node[T]) WalkVerdict {
+		nodes++
+		return Continue
+	})
+
+	return adminStats{
+		Routes:     len(ah.tree.GetAllLeaf()),
+		Nodes:      nodes,
+		Generation: ah.tree.Generation(),
+		Hash:       strconv.FormatUint(ah.tree.Hash(), 16),
+	}
+}
+
+func (ah *adminHandler[T]) test(key string) adminTest {
+	res := adminTest{
+		Key: key,
+	}
+
+	// The key is resolved, instead of being found, so testing a route
+	// neither consumes the tokens of its rate-limit, nor is it observed.
+	h := ah.tree.Resolve(key)
+	if h == nil {
+		return res
+	}
+
+	res.Matched = true
+	res.Pattern = h.Pattern()
+	res.Params = h.Params(key)
+	res.Value = ah.encode(h.Value())
+
+	return res
+}
+
+func (ah *adminHandler[T]) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,208 @@
+package rtree
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminHandler(t *testing.T) {
+	type testCase struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+		expectedBody   string
+	}
+
+	tree := New[*Route]()
+
+	for _, r := range []string{"/api/users/{id}", "/api/products"} {
+		if err := tree.Insert(r, &Route{name: r}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	handler := AdminHandler(tree, WithAdminValueEncoder(func(r *Route) any {
+		return r.name
+	}))
+
+	tt := []testCase{
+		{
+			name:           "only GET is allowed",
+			method:         http.MethodPost,
+			url:            "/__routes",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "",
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			url:            "/__routes/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "",
+		},
+		{
+			name:           "listing of the routes",
+			method:         http.MethodGet,
+			url:            "/__routes",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"routes":[{"pattern":"/api/products","params":[],"value":"/api/products"},{"pattern":"/api/users/{id}","params":[{"name":"id","position":3}],"value":"/api/users/{id}"}]}`,
+		},
+		{
+			name:           "stats of the tree",
+			method:         http.MethodGet,
+			url:            "/__routes/stats",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"routes":2,"nodes":3,"generation":2`,
+		},
+		{
+			name:           "matching test",
+			method:         http.MethodGet,
+			url:            "/__routes?test=/api/users/5",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"key":"/api/users/5","matched":true,"pattern":"/api/users/{id}","params":{"id":"5"},"value":"/api/users/{id}"}`,
+		},
+		{
+			name:           "matching test without match",
+			method:         http.MethodGet,
+			url:            "/__routes?test=/api/foo",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"key":"/api/foo","matched":false}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.url, nil))
+
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("expected status: %d; got: %d\n", tc.expectedStatus, rec.Code)
+			}
+
+			if body := rec.Body.String(); !strings.Contains(body, tc.expectedBody) {
+				t.Errorf("expected body to contain: %s; got: %s\n", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestAdminHandlerTestTokens(t *testing.T) {
+	tree := New(WithRateLimiting[*Route]())
+
+	if err := tree.Insert("/api/users/{id}", getRoute(), WithRateLimit(0.001, 1)); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	handler := AdminHandler(tree)
+
+	// The tests of the route do not use up its only token.
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/__routes?test=/api/users/5", nil))
+
+		if body := rec.Body.String(); !strings.Contains(body, `"matched":true`) {
+			t.Fatalf("expected match; got: %s\n", body)
+		}
+	}
+
+	if fn := tree.Find("/api/users/5"); fn == nil || !fn.Allowed() {
+		t.Errorf("expected the request to be allowed after the tests\n")
+	}
+}
+
+func TestAdminHandlerETag(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/users/{id}", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	handler := AdminHandler(tree)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/__routes", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag; got: %d %q\n", first.Code, etag)
+	}
+
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		if rec := get(header); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("%s: expected 304 without body; got: %d %q\n", header, rec.Code, rec.Body.String())
+		}
+	}
+
+	if rec := get(`"other"`); rec.Code != http.StatusOK || rec.Body.String() != first.Body.String() {
+		t.Errorf("expected the same listing; got: %d %q\n", rec.Code, rec.Body.String())
+	}
+
+	if err := tree.Insert("/api/products", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	rec := get(etag)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("expected 200 with a new ETag; got: %d %q\n", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	if !strings.Contains(rec.Body.String(), "/api/products") {
+		t.Errorf("expected the new route in the listing; got: %s\n", rec.Body.String())
+	}
+
+	// An other handler of the same table gives the same ETag.
+	other := httptest.NewRecorder()
+	AdminHandler(tree).ServeHTTP(other, httptest.NewRequest(http.MethodGet, "/__routes", nil))
+
+	if other.Header().Get("ETag") != rec.Header().Get("ETag") {
+		t.Errorf("expected ETag: %s; got: %s\n", rec.Header().Get("ETag"), other.Header().Get("ETag"))
+	}
+}
+
+func TestAdminHandlerConcurrent(t *testing.T) {
+	tree := New[*Route]()
+
+	for i := 0; i < 100; i++ {
+		if err := tree.Insert(fmt.Sprintf("/routes/%d", i), &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	var (
+		handler = AdminHandler(tree)
+		done    = make(chan struct{})
+	)
+
+	// The values replaced by the mutations must not be read by the listing.
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 5000; i++ {
+			_ = tree.Upsert(fmt.Sprintf("/routes/%d", i%100), &Route{})
+		}
+	}()
+
+	for i := 0; i < 500; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/__routes", nil))
+	}
+
+	<-done
+}
@@ -0,0 +1,183 @@
+package rtree
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// Alias stores the alias key, so it resolves to the value of the existing
+// key. The value is not duplicated: replacing the value of the existing
+// key is immediately visible through the alias as well. Aliasing an
+// alias resolves to the original route.
+func (t *Tree[T]) Alias(existingKey, aliasKey string) error {
+	var zero T
+
+	nv, err := t.newNodeValue(aliasKey, zero, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := findExactRec(t.root, t.normalizePattern(existingKey))
+	if n == nil {
+		return errKeyNotFound
+	}
+
+	target := t.resolve(n.value)
+
+	nv.aliasOf = target.id
+	nv.cell = target.cell
+
+	if err := t.store(nv); err != nil {
+		return err
+	}
+
+	return t.persist(nv)
+}
+
+// AliasOf returns the ID of the route this route is an alias of,
+// or 0 if it is not an alias.
+func (nv *NodeValue[T]) AliasOf() uint64 {
+	return nv.aliasOf
+}
+
+// DeleteCascade removes the given key from the tree alongside with all
+// of its aliases. Delete refuses to remove a route that has aliases.
+// It fails, if any of the removed routes is protected.
+func (t *Tree[T]) DeleteCascade(key string) error {
+	if err := checkTree(t); err != nil {
+		return err
+	}
+
+	if key == "" {
+		return ErrKeyIsEmpty
+	}
+
+	key = t.normalizePattern(key)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	path := findExactPath(t.root, key)
+	if path == nil {
+		return errKeyNotFound
+	}
+
+	var (
+		nv      = path[len(path)-1].value
+		aliases = t.aliasesOf(nv.id)
+	)
+
+	if nv.meta.protected {
+		return errRouteIsProtected
+	}
+
+	for _, a := range aliases {
+		if a.meta.protected {
+			return errRouteIsProtected
+		}
+	}
+
+	removed := make([]string, 0, len(aliases)+1)
+
+	// The aliases are removed first, so they still resolve to the value
+	// of the route. Their removal could alter the path of the route.
+	for _, a := range aliases {
+		if aliasPath := findExactPath(t.root, a.pattern); aliasPath != nil {
+			t.remove(aliasPath, ChangeDelete)
+			removed = append(removed, a.pattern)
+		}
+	}
+
+	t.remove(findExactPath(t.root, key), ChangeDelete)
+
+	removed = append(removed, key)
+
+	for _, pattern := range removed {
+		if err := t.unpersist(pattern); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// routeCell holds the current version of a route. The versions are
+// replaced – not altered – by the mutations, so the aliases resolve to
+// the current one by the cell, without reading the routes of the tree,
+// which the lookups could not do without the lock.
+type routeCell[T storeValue] struct {
+	current atomic.Pointer[NodeValue[T]]
+}
+
+// resolve returns the stored value, that the given value refers to.
+func (t *Tree[T]) resolve(nv *NodeValue[T]) *NodeValue[T] {
+	if nv.aliasOf == 0 || nv.cell == nil {
+		return nv
+	}
+
+	if target := nv.cell.current.Load(); target != nil {
+		return target
+	}
+
+	return nv
+}
+
+// publish makes the given version of the route the current one, for
+// the lookups by ID and by its aliases. The caller must hold the write lock.
+func (t *Tree[T]) publish(nv *NodeValue[T]) {
+	t.routes[nv.id] = nv
+
+	if nv.aliasOf == 0 && nv.cell != nil {
+		nv.cell.current.Store(nv)
+	}
+}
+
+// indexAlias records the alias by the ID of its route.
+// The caller must hold the write lock.
+func (t *Tree[T]) indexAlias(nv *NodeValue[T]) {
+	if t.aliases == nil {
+		t.aliases = make(map[uint64]map[uint64]struct{})
+	}
+
+	if t.aliases[nv.aliasOf] == nil {
+		t.aliases[nv.aliasOf] = make(map[uint64]struct{})
+	}
+
+	t.aliases[nv.aliasOf][nv.id] = struct{}{}
+}
+
+// unindexAlias removes the alias from the index, if it is an alias.
+// The caller must hold the write lock.
+func (t *Tree[T]) unindexAlias(nv *NodeValue[T]) {
+	ids, exists := t.aliases[nv.aliasOf]
+	if nv.aliasOf == 0 || !exists {
+		return
+	}
+
+	delete(ids, nv.id)
+
+	if len(ids) == 0 {
+		delete(t.aliases, nv.aliasOf)
+	}
+}
+
+// aliasesOf returns the aliases of the route with the given ID,
+// in the order of their IDs. The caller must hold the lock.
+func (t *Tree[T]) aliasesOf(id uint64) []*NodeValue[T] {
+	aliases := make([]*NodeValue[T], 0, len(t.aliases[id]))
+
+	for aliasID := range t.aliases[id] {
+		if nv, exists := t.routes[aliasID]; exists {
+			aliases = append(aliases, nv)
+		}
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].id < aliases[j].id
+	})
+
+	return aliases
+}
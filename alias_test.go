@@ -0,0 +1,216 @@
+package rtree
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	type testCase struct {
+		name     string
+		existing string
+		alias    string
+		err      error
+	}
+
+	tt := []testCase{
+		{
+			name:     "error if the existing key is not stored",
+			existing: "/old/{id}",
+			alias:    "/legacy/{id}",
+			err:      errKeyNotFound,
+		},
+		{
+			name:     "error if the alias is already stored",
+			existing: "/new/{id}",
+			alias:    "/new/{id}",
+			err:      errKeyIsAlreadyStored,
+		},
+		{
+			name:     "error if the alias has bad syntax",
+			existing: "/new/{id}",
+			alias:    "legacy",
+			err:      errMissingSlashPrefix,
+		},
+		{
+			name:     "no error on successful aliasing",
+			existing: "/new/{id}",
+			alias:    "/legacy/{id}",
+			err:      nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			if err := tree.Insert("/new/{id}", &Route{name: "new"}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if err := tree.Alias(tc.existing, tc.alias); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestAliasResolvesValue(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/new/{id}", &Route{name: "v1"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Alias("/new/{id}", "/legacy/{legacyId}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Alias("/legacy/{legacyId}", "/older/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for _, version := range []string{"v1", "v2"} {
+		if err := tree.Upsert("/new/{id}", &Route{name: version}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		for _, key := range []string{"/new/1", "/legacy/1", "/older/1"} {
+			fn := tree.Find(key)
+
+			if fn == nil {
+				t.Fatalf("expected to find %s, but got <nil>", key)
+			}
+
+			if fn.GetValue().name != version {
+				t.Errorf("%s: expected value: %s; got: %s\n", key, version, fn.GetValue().name)
+			}
+		}
+	}
+
+	fn := tree.Find("/legacy/5")
+
+	if fn.GetPattern() != "/legacy/{legacyId}" || fn.GetParams()["legacyId"] != "5" {
+		t.Errorf("expected own pattern and params of the alias; got: %s %v\n", fn.GetPattern(), fn.GetParams())
+	}
+
+	older := tree.Find("/older/1").RouteID()
+
+	if got := tree.RouteByID(older).AliasOf(); got != tree.Find("/new/1").RouteID() {
+		t.Errorf("expected alias of alias to resolve to the original; got: %d\n", got)
+	}
+}
+
+func TestDeleteWithAliases(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/new/{id}", &Route{name: "new"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Alias("/new/{id}", "/legacy/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Delete("/new/{id}"); !errors.Is(err, errRouteHasAliases) {
+		t.Errorf("expected error: %v; got: %v\n", errRouteHasAliases, err)
+	}
+
+	if err := tree.DeleteCascade("/new/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for _, key := range []string{"/new/1", "/legacy/1"} {
+		if tree.Find(key) != nil {
+			t.Errorf("expected not to find %s, but got route", key)
+		}
+	}
+}
+
+func TestConcurrentAliasLookups(t *testing.T) {
+	tree := New[*Route]()
+
+	for _, pattern := range []string{"/new/{id}", "/other"} {
+		if err := tree.Insert(pattern, &Route{name: "new"}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := tree.Alias("/new/{id}", "/legacy/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		started = make(chan struct{})
+		done    = make(chan struct{})
+	)
+
+	wg.Add(1)
+
+	// The lookups of the alias are not locked, and the insertions
+	// never touch their nodes, so resolving the alias must not
+	// read anything written by the insertions.
+	go func() {
+		defer wg.Done()
+
+		close(started)
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if fn := tree.Find("/legacy/1"); fn == nil || fn.GetValue() == nil {
+				t.Error("expected to find the alias, but got <nil>")
+				return
+			}
+		}
+	}()
+
+	<-started
+
+	for i := 0; i < 1000; i++ {
+		if err := tree.Insert(fmt.Sprintf("/other/%d", i), &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestAliasIndex(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/new/{id}", &Route{name: "new"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for _, alias := range []string{"/legacy/{id}", "/old/{id}"} {
+		if err := tree.Alias("/new/{id}", alias); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// The alias replaced by a value is not an alias anymore.
+	if err := tree.Upsert("/old/{id}", &Route{name: "old"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := tree.Find("/old/1").GetValue().name; got != "old" {
+		t.Errorf("expected value: %s; got: %s\n", "old", got)
+	}
+
+	if err := tree.Delete("/legacy/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Delete("/new/{id}"); err != nil {
+		t.Errorf("expected the route without aliases to be deleted, but got: %v\n", err)
+	}
+}
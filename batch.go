@@ -0,0 +1,132 @@
+package rtree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InsertStatus is the outcome of inserting a single entry of a batch.
+type InsertStatus int
+
+const (
+	StatusInserted InsertStatus = iota
+	StatusSkippedDuplicate
+	StatusConflict
+	StatusSyntaxError
+	StatusFailed
+)
+
+var insertStatusNames = map[InsertStatus]string{
+	StatusInserted:         "inserted",
+	StatusSkippedDuplicate: "skipped-duplicate",
+	StatusConflict:         "conflict",
+	StatusSyntaxError:      "syntax-error",
+	StatusFailed:           "failed",
+}
+
+func (s InsertStatus) String() string {
+	if name, exists := insertStatusNames[s]; exists {
+		return name
+	}
+
+	return "unknown"
+}
+
+// Entry is a single key-value pair of a batch insert.
+type Entry[T storeValue] struct {
+	Key     string
+	Value   T
+	Options []RouteOption
+}
+
+// InsertResult is the outcome of a single entry of a batch insert.
+type InsertResult struct {
+	Key    string
+	Status InsertStatus
+	Err    error
+}
+
+// InsertReport holds the outcome of every entry of a batch insert,
+// in the order of the entries.
+type InsertReport []InsertResult
+
+// OK reports whether all the entries were inserted.
+func (r InsertReport) OK() bool {
+	return len(r.Failed()) == 0
+}
+
+// Err returns the MultiError of the failed entries – each of them
+// prefixed by its key –, or nil if there was no failure. The skipped
+// duplicates of the batch are not failures, so they have no error.
+func (r InsertReport) Err() error {
+	errs := make([]error, 0)
+
+	for _, res := range r {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.Key, res.Err))
+		}
+	}
+
+	return newMultiError(errs)
+}
+
+// Failed returns the results of the entries that were not inserted.
+func (r InsertReport) Failed() []InsertResult {
+	failed := make([]InsertResult, 0)
+
+	for _, res := range r {
+		if res.Status != StatusInserted {
+			failed = append(failed, res)
+		}
+	}
+
+	return failed
+}
+
+// InsertAll inserts all the given entries, and does not stop at the
+// first failure, so the report gives a complete picture of the batch.
+// Repeated keys within the batch are skipped after their first
+// occurrence, while keys already stored in the tree are conflicts.
+func (t *Tree[T]) InsertAll(entries []Entry[T]) InsertReport {
+	var (
+		report = make(InsertReport, 0, len(entries))
+		seen   = make(map[string]struct{}, len(entries))
+	)
+
+	for _, e := range entries {
+		res := InsertResult{
+			Key: e.Key,
+		}
+
+		if _, exists := seen[e.Key]; exists {
+			res.Status = StatusSkippedDuplicate
+			report = append(report, res)
+			continue
+		}
+
+		seen[e.Key] = struct{}{}
+
+		res.Err = t.Insert(e.Key, e.Value, e.Options...)
+		res.Status = insertStatusOf(res.Err)
+
+		report = append(report, res)
+	}
+
+	return report
+}
+
+func insertStatusOf(err error) InsertStatus {
+	switch {
+	case err == nil:
+		return StatusInserted
+	case errors.Is(err, errKeyIsAlreadyStored):
+		return StatusConflict
+	case errors.Is(err, errBadPathParamSyntax),
+		errors.Is(err, errMissingSlashPrefix),
+		errors.Is(err, errPresentSlashSuffix),
+		errors.Is(err, ErrKeyIsEmpty):
+		return StatusSyntaxError
+	default:
+		return StatusFailed
+	}
+}
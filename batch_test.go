@@ -0,0 +1,61 @@
+package rtree
+
+import "testing"
+
+func TestInsertAll(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/stored", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	entries := []Entry[*Route]{
+		{Key: "/api/users", Value: getRoute()},
+		{Key: "/api/users", Value: getRoute()},
+		{Key: "/api/stored", Value: getRoute()},
+		{Key: "api/no-slash", Value: getRoute()},
+		{Key: "/api/{id", Value: getRoute()},
+		{Key: "/api/products/{id}", Value: getRoute()},
+	}
+
+	expected := []InsertStatus{
+		StatusInserted,
+		StatusSkippedDuplicate,
+		StatusConflict,
+		StatusSyntaxError,
+		StatusSyntaxError,
+		StatusInserted,
+	}
+
+	report := tree.InsertAll(entries)
+
+	if len(report) != len(expected) {
+		t.Fatalf("expected results: %d; got: %d\n", len(expected), len(report))
+	}
+
+	for i, res := range report {
+		if res.Key != entries[i].Key {
+			t.Errorf("expected key: %s; got: %s\n", entries[i].Key, res.Key)
+		}
+
+		if res.Status != expected[i] {
+			t.Errorf("%s: expected status: %s; got: %s\n", res.Key, expected[i], res.Status)
+		}
+
+		if (res.Status == StatusInserted || res.Status == StatusSkippedDuplicate) != (res.Err == nil) {
+			t.Errorf("%s: unexpected error: %v\n", res.Key, res.Err)
+		}
+	}
+
+	if report.OK() {
+		t.Error("expected report not to be ok")
+	}
+
+	if got := len(report.Failed()); got != 4 {
+		t.Errorf("expected failed count: %d; got: %d\n", 4, got)
+	}
+
+	if tree.Find("/api/products/1") == nil {
+		t.Error("expected entries after the failures to be inserted")
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/balazskvancz/rtree/bboltstore
+
+go 1.20
+
+require (
+	github.com/balazskvancz/rtree v1.0.2
+	go.etcd.io/bbolt v1.3.9
+)
+
+require golang.org/x/sys v0.4.0 // indirect
+
+replace github.com/balazskvancz/rtree => ../
@@ -0,0 +1,196 @@
+// Package bboltstore is a bbolt backed implementation of rtree.Store,
+// so the dynamically registered routes survive the restarts, without
+// standing up an external registry.
+package bboltstore
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/balazskvancz/rtree"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultBucket is the bucket the routes are written into by default.
+const DefaultBucket = "routes"
+
+// compactTxMaxSize is the maximum size of a single transaction of the compaction.
+const compactTxMaxSize = 64 * 1024
+
+var errClosed = errors.New("bboltstore: store is closed")
+
+// Store writes the routes into a bolt bucket, keyed by their patterns.
+type Store struct {
+	mu     sync.RWMutex
+	db     *bolt.DB
+	path   string
+	bucket []byte
+}
+
+var _ rtree.Store = (*Store)(nil)
+
+// Option configures the store.
+type Option func(*Store)
+
+// WithBucket sets the name of the bucket. By default it is „routes”.
+func WithBucket(name string) Option {
+	return func(s *Store) {
+		s.bucket = []byte(name)
+	}
+}
+
+// Open opens – or creates – the bolt database at the given path.
+func Open(path string, opts ...Option) (*Store, error) {
+	s := &Store{
+		path:   path,
+		bucket: []byte(DefaultBucket),
+	}
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) open() error {
+	db, err := bolt.Open(s.path, 0o600, nil)
+	if err != nil {
+		return err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(s.bucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	s.db = db
+
+	return nil
+}
+
+// Save writes the blob of the key in a transaction of its own.
+func (s *Store) Save(key string, blob []byte) error {
+	return s.update(func(b *bolt.Bucket) error {
+		return b.Put([]byte(key), blob)
+	})
+}
+
+// Delete removes the key. Deleting a missing key is not an error.
+func (s *Store) Delete(key string) error {
+	return s.update(func(b *bolt.Bucket) error {
+		return b.Delete([]byte(key))
+	})
+}
+
+// LoadAll reads all the stored blobs.
+func (s *Store) LoadAll() (map[string][]byte, error) {
+	blobs := make(map[string][]byte)
+
+	err := s.Stream(func(key string, blob []byte) error {
+		blobs[key] = blob
+		return nil
+	})
+
+	return blobs, err
+}
+
+// Stream calls fn with every stored entry in the order of their keys,
+// without loading all of them into memory at once. The blob is a copy,
+// so it could be retained after fn returns. If fn returns an error,
+// the streaming stops and the error is returned.
+func (s *Store) Stream(fn func(key string, blob []byte) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return errClosed
+	}
+
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).ForEach(func(k, v []byte) error {
+			blob := make([]byte, len(v))
+			copy(blob, v)
+
+			return fn(string(k), blob)
+		})
+	})
+}
+
+// Compact rewrites the database into a new file, releasing the space
+// of the deleted and overwritten entries, then replaces the old file.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return errClosed
+	}
+
+	tmpPath := s.path + ".compact"
+
+	dst, err := bolt.Open(tmpPath, 0o600, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := bolt.Compact(dst, s.db, compactTxMaxSize); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+
+	s.db = nil
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return nil
+	}
+
+	err := s.db.Close()
+	s.db = nil
+
+	return err
+}
+
+func (s *Store) update(fn func(b *bolt.Bucket) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.db == nil {
+		return errClosed
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(s.bucket))
+	})
+}
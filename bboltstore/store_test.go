@@ -0,0 +1,149 @@
+package bboltstore
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/balazskvancz/rtree"
+)
+
+func openStore(t *testing.T, path string) *Store {
+	t.Helper()
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func TestStore(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "routes.db"))
+
+	for k, v := range map[string]string{
+		"/api/users/{id}":  "users",
+		"/files/{path...}": "files",
+		"/api/products":    "products",
+	} {
+		if err := s.Save(k, []byte(v)); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := s.Save("/api/products", []byte("products-v2")); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := s.Delete("/api/users/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	expected := map[string][]byte{
+		"/files/{path...}": []byte("files"),
+		"/api/products":    []byte("products-v2"),
+	}
+
+	got, err := s.LoadAll()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected blobs: %v; got: %v\n", expected, got)
+	}
+
+	if err := s.Compact(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	got, err = s.LoadAll()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected blobs after compaction: %v; got: %v\n", expected, got)
+	}
+}
+
+func TestStreamOrder(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "routes.db"))
+
+	for _, k := range []string{"/c", "/a", "/b"} {
+		if err := s.Save(k, []byte(k)); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	keys := make([]string, 0)
+
+	err := s.Stream(func(key string, _ []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if expected := []string{"/a", "/b", "/c"}; !reflect.DeepEqual(expected, keys) {
+		t.Errorf("expected keys: %v; got: %v\n", expected, keys)
+	}
+}
+
+func TestClosedStore(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "routes.db"))
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := s.Save("/a", nil); err != errClosed {
+		t.Errorf("expected error: %v; got: %v\n", errClosed, err)
+	}
+
+	if _, err := s.LoadAll(); err != errClosed {
+		t.Errorf("expected error: %v; got: %v\n", errClosed, err)
+	}
+}
+
+func TestTreeRestart(t *testing.T) {
+	var (
+		path                      = filepath.Join(t.TempDir(), "routes.db")
+		codec rtree.Codec[string] = rtree.JSONCodec[string]{}
+	)
+
+	s := openStore(t, path)
+
+	tree := rtree.New(rtree.WithStore[string](s, codec))
+
+	for _, k := range []string{"/api/users/{id}", "/api/products", "/api/orders"} {
+		if err := tree.Insert(k, k); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := tree.Delete("/api/orders"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	restarted := rtree.New(rtree.WithStore[string](openStore(t, path), codec))
+
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	expected, _ := tree.Snapshot(codec)
+	got, _ := restarted.Snapshot(codec)
+
+	if !reflect.DeepEqual(expected.Entries, got.Entries) {
+		t.Errorf("expected entries: %v; got: %v\n", expected.Entries, got.Entries)
+	}
+}
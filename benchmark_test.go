@@ -48,6 +48,8 @@ func BenchmarkTree(b *testing.B) {
 		name := fmt.Sprintf("testing with %d routes", tc)
 
 		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
 			for i := 0; i < b.N; i++ {
 				if toBeFound {
 					var (
@@ -109,3 +111,156 @@ func testNormalizeRoute(str string) string {
 
 	return strings.ReplaceAll(str, "{id}", "1")
 }
+
+func BenchmarkFindWildcard(b *testing.B) {
+	tree := New[*Route]()
+
+	routes := []string{
+		"/api/{resource}",
+		"/api/{resource}/{id}",
+		"/api/{resource}/{id}/items",
+		"/api/{resource}/{id}/items/{item}",
+		"/api/{resource}/{id}/owners/{owner}",
+		"/api/users/{id}/posts/{post}/comments/{comment}",
+		"/files/{path...}",
+	}
+
+	for _, r := range routes {
+		if err := tree.Insert(r, &Route{}); err != nil {
+			b.Fatalf("expected no error; got: %v\n", err)
+		}
+	}
+
+	keys := []string{
+		"/api/products/42/items/7",
+		"/api/users/5/posts/6/comments/7",
+		"/api/groups/admins/owners/alice",
+		"/files/docs/2024/report.pdf",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if tree.Find(keys[i%len(keys)]) == nil {
+			b.Fatal("not found node; supposed to")
+		}
+	}
+}
+
+// benchmarkScenario is a shape of the route table, with the keys
+// that are looked up in it – every key matches one of the routes.
+type benchmarkScenario struct {
+	name     string
+	patterns []string
+	keys     []string
+}
+
+func benchmarkScenarios() []benchmarkScenario {
+	var (
+		static     = benchmarkScenario{name: "static-only"}
+		paramHeavy = benchmarkScenario{name: "param-heavy"}
+		deep       = benchmarkScenario{name: "deep"}
+		wide       = benchmarkScenario{name: "wide"}
+	)
+
+	for i := 0; i < 100; i++ {
+		p := fmt.Sprintf("/api/service%d/resource%d/list", i%10, i)
+
+		static.patterns = append(static.patterns, p)
+		static.keys = append(static.keys, p)
+	}
+
+	for i := 0; i < 100; i++ {
+		paramHeavy.patterns = append(paramHeavy.patterns, fmt.Sprintf("/api/{tenant}/resource%d/{id}/{action}/{format}", i))
+		paramHeavy.keys = append(paramHeavy.keys, fmt.Sprintf("/api/acme/resource%d/%d/export/json", i, i*7))
+	}
+
+	// The routes share a long common prefix, and branch at its end.
+	prefix := strings.Repeat("/level", 16)
+
+	for i := 0; i < 100; i++ {
+		p := fmt.Sprintf("%s/branch%d/{id}/leaf", prefix, i)
+
+		deep.patterns = append(deep.patterns, p)
+		deep.keys = append(deep.keys, strings.Replace(p, "{id}", "42", 1))
+	}
+
+	// The routes are the children of the same node.
+	for i := 0; i < 1000; i++ {
+		p := fmt.Sprintf("/api/%c%d", 'a'+rune(i%26), i)
+
+		wide.patterns = append(wide.patterns, p)
+		wide.keys = append(wide.keys, p)
+	}
+
+	return []benchmarkScenario{static, paramHeavy, deep, wide}
+}
+
+func BenchmarkFindScenarios(b *testing.B) {
+	for _, sc := range benchmarkScenarios() {
+		tree := New[*Route]()
+
+		for _, p := range sc.patterns {
+			if err := tree.Insert(p, &Route{}); err != nil {
+				b.Fatalf("expected no error; got: %v\n", err)
+			}
+		}
+
+		b.Run(sc.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if tree.Find(sc.keys[i%len(sc.keys)]) == nil {
+					b.Fatal("not found node; supposed to")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFindReuse is the same as BenchmarkFindScenarios,
+// but the results are written to a single FoundNode.
+func BenchmarkFindReuse(b *testing.B) {
+	for _, sc := range benchmarkScenarios() {
+		tree := New[*Route]()
+
+		for _, p := range sc.patterns {
+			if err := tree.Insert(p, &Route{}); err != nil {
+				b.Fatalf("expected no error; got: %v\n", err)
+			}
+		}
+
+		b.Run(sc.name, func(b *testing.B) {
+			var out FoundNode[*Route]
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if !tree.FindReuse(sc.keys[i%len(sc.keys)], &out) {
+					b.Fatal("not found node; supposed to")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkInsert measures the bulk-build of the trees, so a
+// single iteration inserts every route of the scenario.
+func BenchmarkInsert(b *testing.B) {
+	for _, sc := range benchmarkScenarios() {
+		b.Run(sc.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				tree := New[*Route]()
+
+				for _, p := range sc.patterns {
+					if err := tree.Insert(p, &Route{}); err != nil {
+						b.Fatalf("expected no error; got: %v\n", err)
+					}
+				}
+			}
+		})
+	}
+}
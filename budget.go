@@ -0,0 +1,76 @@
+package rtree
+
+import "fmt"
+
+// ErrBudgetExceeded is returned by TryFind, if the lookup was aborted,
+// since it visited more nodes than the backtrack budget of the tree.
+var ErrBudgetExceeded = fmt.Errorf("[rtree %s]: backtrack budget exceeded", version)
+
+// budget counts the visited nodes of a single lookup.
+type budget struct {
+	limit    int
+	visits   int
+	exceeded bool
+}
+
+// spend records a visit, and reports whether it fits in the budget.
+func (b *budget) spend() bool {
+	b.visits++
+
+	if b.limit > 0 && b.visits > b.limit {
+		b.exceeded = true
+	}
+
+	return !b.exceeded
+}
+
+func (b *budget) err() error {
+	if b.exceeded {
+		return ErrBudgetExceeded
+	}
+
+	return nil
+}
+
+// WithBacktrackBudget limits the number of the nodes visited by a single
+// lookup. Without limit, a lookup visits every node at most once – twice
+// with CatchAllFirst –, but heavy wildcard fan-out could make it visit
+// most of them. An aborted lookup results in no match; TryFind tells it
+// apart from a real miss.
+func WithBacktrackBudget[T storeValue](n int) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if n < 0 {
+			t.invalidOption("backtrack budget", n)
+			return
+		}
+
+		t.setOption("backtrack budget", n)
+		t.backtrackBudget = n
+	}
+}
+
+// TryFind is the same as Find without the default route, but it returns
+// ErrBudgetExceeded, if the lookup was aborted. A miss is nil without error.
+func (t *Tree[T]) TryFind(key string) (*FoundNode[T], error) {
+	if err := checkTree(t); err != nil {
+		return nil, nil
+	}
+
+	var err error
+
+	fn := t.observe(key, func(key string) *FoundNode[T] {
+		var (
+			n      *Node[T]
+			params matchedParams
+		)
+
+		n, params, err = t.lookup(key, nil, t.resolution)
+		if n == nil {
+			return nil
+		}
+
+		return t.newFoundNode(n, params)
+	})
+
+	return fn, err
+}
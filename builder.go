@@ -0,0 +1,160 @@
+package rtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+var errUnknownConstraintParam = fmt.Errorf("[rtree %s]: constraint of unknown param", version)
+
+// Built-in constraints of the route builder.
+var (
+	// Int accepts segments of decimal digits only.
+	Int SegmentMatcher = SegmentMatcherFunc(func(segment string) (bool, string) {
+		return segment != "" && strings.Trim(segment, "0123456789") == "", segment
+	})
+	// Alpha accepts segments of ASCII letters only.
+	Alpha SegmentMatcher = SegmentMatcherFunc(func(segment string) (bool, string) {
+		return segment != "" && strings.Trim(segment, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") == "", segment
+	})
+	// UUID accepts segments in the canonical 8-4-4-4-12 hexadecimal form.
+	UUID SegmentMatcher = SegmentMatcherFunc(func(segment string) (bool, string) {
+		return isUUID(segment), segment
+	})
+)
+
+// RouteBuilder builds a validated route step by step, eg.
+//
+//	rtree.NewRoute("/api/users/{id}").Constraint("id", rtree.Int).Meta("team", "users").Build()
+type RouteBuilder struct {
+	pattern     string
+	constraints map[string]SegmentMatcher
+	labels      map[string]string
+}
+
+// RouteSpec is a validated route built by a RouteBuilder,
+// which could be stored by InsertRoute.
+type RouteSpec struct {
+	pattern     string
+	constraints map[string]SegmentMatcher
+	labels      map[string]string
+}
+
+// NewRoute starts building a route with the given pattern.
+func NewRoute(pattern string) *RouteBuilder {
+	return &RouteBuilder{
+		pattern:     pattern,
+		constraints: make(map[string]SegmentMatcher),
+		labels:      make(map[string]string),
+	}
+}
+
+// Constraint sets the matcher the value of the given param must satisfy.
+// Unlike the matchers referenced by the patterns, it is not registered on
+// the tree, it only belongs to this route.
+func (b *RouteBuilder) Constraint(param string, m SegmentMatcher) *RouteBuilder {
+	b.constraints[param] = m
+	return b
+}
+
+// Meta sets a metadata label of the route.
+func (b *RouteBuilder) Meta(key, value string) *RouteBuilder {
+	b.labels[key] = value
+	return b
+}
+
+// Build validates the pattern, and that every constraint belongs to
+// a param of the pattern.
+func (b *RouteBuilder) Build() (RouteSpec, error) {
+	if b.pattern == "" {
+		return RouteSpec{}, ErrKeyIsEmpty
+	}
+
+	if err := checkUrl(b.pattern); err != nil {
+		return RouteSpec{}, err
+	}
+
+	names := make(map[string]struct{})
+
+	for _, pi := range getPathParams(b.pattern) {
+		names[pi.key] = struct{}{}
+	}
+
+	for param := range b.constraints {
+		if _, exists := names[param]; !exists {
+			return RouteSpec{}, fmt.Errorf("%w: %s", errUnknownConstraintParam, param)
+		}
+	}
+
+	spec := RouteSpec{
+		pattern:     b.pattern,
+		constraints: make(map[string]SegmentMatcher, len(b.constraints)),
+		labels:      make(map[string]string, len(b.labels)),
+	}
+
+	// The spec is detached from the builder, so it could be reused.
+	for k, v := range b.constraints {
+		spec.constraints[k] = v
+	}
+
+	for k, v := range b.labels {
+		spec.labels[k] = v
+	}
+
+	return spec, nil
+}
+
+// Pattern returns the pattern of the route.
+func (s RouteSpec) Pattern() string {
+	return s.pattern
+}
+
+// InsertRoute stores the built route with the given value.
+func (t *Tree[T]) InsertRoute(spec RouteSpec, value T, opts ...RouteOption) error {
+	return t.insert(spec.pattern, value, opts, func(nv *NodeValue[T]) {
+		if len(spec.constraints) > 0 {
+			nv.meta.constraints = spec.constraints
+		}
+
+		if len(spec.labels) > 0 {
+			nv.meta.labels = spec.labels
+		}
+	})
+}
+
+// Meta returns the metadata label of the route with the given key.
+func (nv *NodeValue[T]) Meta(key string) string {
+	return nv.meta.labels[key]
+}
+
+// Meta returns the metadata label of the matched route with the given key.
+func (fn *FoundNode[T]) Meta(key string) string {
+	if fn.meta == nil {
+		return ""
+	}
+
+	return fn.meta.labels[key]
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
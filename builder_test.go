@@ -0,0 +1,141 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouteBuilder(t *testing.T) {
+	type testCase struct {
+		name    string
+		builder *RouteBuilder
+		err     error
+	}
+
+	tt := []testCase{
+		{
+			name:    "error on empty pattern",
+			builder: NewRoute(""),
+			err:     ErrKeyIsEmpty,
+		},
+		{
+			name:    "error on bad pattern",
+			builder: NewRoute("/api/users/{id"),
+			err:     errBadPathParamSyntax,
+		},
+		{
+			name:    "error on constraint of unknown param",
+			builder: NewRoute("/api/users/{id}").Constraint("slug", Int),
+			err:     errUnknownConstraintParam,
+		},
+		{
+			name:    "no error on proper route",
+			builder: NewRoute("/api/users/{id}").Constraint("id", Int).Meta("team", "users"),
+			err:     nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.builder.Build(); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestInsertRoute(t *testing.T) {
+	type testCase struct {
+		name           string
+		key            string
+		expectedName   string
+		expectedParams matchedParams
+	}
+
+	tree := New[*Route]()
+
+	specs := []struct {
+		builder *RouteBuilder
+		name    string
+	}{
+		{builder: NewRoute("/api/users/{id}").Constraint("id", Int).Meta("team", "users"), name: "user-by-id"},
+		{builder: NewRoute("/api/users/{name}").Constraint("name", Alpha), name: "user-by-name"},
+		{builder: NewRoute("/api/orders/{id}").Constraint("id", UUID), name: "order"},
+	}
+
+	for _, s := range specs {
+		spec, err := s.builder.Build()
+		if err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		if err := tree.InsertRoute(spec, &Route{name: s.name}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:           "int constraint",
+			key:            "/api/users/42",
+			expectedName:   "user-by-id",
+			expectedParams: matchedParams{"id": "42"},
+		},
+		{
+			name:           "falls back to the other branch on rejected constraint",
+			key:            "/api/users/john",
+			expectedName:   "user-by-name",
+			expectedParams: matchedParams{"name": "john"},
+		},
+		{
+			name:         "no match if all the constraints reject",
+			key:          "/api/users/john42",
+			expectedName: "",
+		},
+		{
+			name:           "uuid constraint",
+			key:            "/api/orders/123e4567-e89b-12d3-a456-426614174000",
+			expectedName:   "order",
+			expectedParams: matchedParams{"id": "123e4567-e89b-12d3-a456-426614174000"},
+		},
+		{
+			name:         "no match on bad uuid",
+			key:          "/api/orders/123e4567",
+			expectedName: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.Find(tc.key)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Errorf("expected not to find, but got: %s\n", fn.GetValue().name)
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+
+			if got := fn.GetParams(); !reflect.DeepEqual(tc.expectedParams, got) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, got)
+			}
+		})
+	}
+
+	if got := tree.Find("/api/users/42").Meta("team"); got != "users" {
+		t.Errorf("expected meta: %s; got: %s\n", "users", got)
+	}
+
+	if got := tree.Find("/api/users/john").Meta("team"); got != "" {
+		t.Errorf("expected no meta; got: %s\n", got)
+	}
+}
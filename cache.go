@@ -0,0 +1,157 @@
+package rtree
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// lookupCache caches the results of the lookups by their keys. The
+// entries are only valid in the generation of the tree they were
+// stored in, so every mutation invalidates the whole cache.
+type lookupCache[T storeValue] struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]cacheEntry[T]
+}
+
+type cacheEntry[T storeValue] struct {
+	generation uint64
+	node       *Node[T]
+	params     matchedParams
+	// deeper is set by the prefix cache, if there are routes
+	// below the prefix, so the entry is not a match of its own.
+	deeper bool
+}
+
+// WithLookupCache caches the results – the misses included – of the
+// lookups of the last size distinct keys. It pays off, if the same keys
+// are looked up over and over again, eg. behind a gateway. Once the cache
+// is full, it is emptied. The size must be positive.
+func WithLookupCache[T storeValue](size int) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if size <= 0 {
+			t.invalidOption("lookup cache size", size)
+			return
+		}
+
+		t.setOption("lookup cache size", size)
+		t.cache = &lookupCache[T]{
+			size:    size,
+			entries: make(map[string]cacheEntry[T], size),
+		}
+	}
+}
+
+func (c *lookupCache[T]) get(key string, generation uint64) (cacheEntry[T], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, exists := c.entries[key]
+	if !exists || e.generation != generation {
+		return e, false
+	}
+
+	return e, true
+}
+
+func (c *lookupCache[T]) put(key string, e cacheEntry[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.size {
+		c.entries = make(map[string]cacheEntry[T], c.size)
+	}
+
+	c.entries[key] = e
+}
+
+// cachedLookup is the same as lookup with the default resolution
+// order, but its results are cached, if the tree has a lookup cache.
+// The aborted lookups are never cached.
+func (t *Tree[T]) cachedLookup(key string, order ResolutionOrder) (*Node[T], matchedParams) {
+	if t.cache == nil || order != t.resolution || t.windowed.Load() {
+		n, params, _ := t.lookup(key, nil, order)
+		return n, params
+	}
+
+	// The generation is read before the lookup, so a mutation
+	// running at the same time could only make the entry stale.
+	generation := t.Generation()
+
+	if e, ok := t.cache.get(key, generation); ok {
+		t.metrics.cacheHit()
+		return e.node, copyParams(e.params)
+	}
+
+	n, params, err := t.lookup(key, nil, order)
+	if err != nil {
+		return n, params
+	}
+
+	t.cache.put(key, cacheEntry[T]{
+		generation: generation,
+		node:       n,
+		params:     copyParams(params),
+	})
+
+	return n, params
+}
+
+// Warm populates the lookup caches of the tree – see WithLookupCache
+// and WithPrefixCache – by looking up the given keys, eg. the hot paths
+// of the previous deployment, so the first requests after a startup do
+// not pay for the misses of the caches. Once a cache is full, it is
+// emptied, so only the last keys of a list longer than its size are kept.
+// The lookups are not observed. It returns the number of the keys, that
+// matched a route – the prefix routes, if the tree only has a prefix
+// cache –, while without caches the keys are not even looked up.
+func (t *Tree[T]) Warm(keys []string) int {
+	if checkTree(t) != nil || t.cache == nil && t.prefixCache == nil || t.windowed.Load() {
+		return 0
+	}
+
+	if t.readLocking {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+	}
+
+	matched := 0
+
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		found := false
+
+		if t.cache != nil {
+			n, _ := t.cachedLookup(key, t.resolution)
+			found = n != nil
+		}
+
+		if t.prefixCache != nil && (!t.runeMatching || utf8.ValidString(key)) {
+			n := t.cachedLongestMatch(t.searchKey(key))
+			found = found || t.cache == nil && n != nil && n.value != nil
+		}
+
+		if found {
+			matched++
+		}
+	}
+
+	return matched
+}
+
+func copyParams(params matchedParams) matchedParams {
+	if params == nil {
+		return nil
+	}
+
+	cp := make(matchedParams, len(params))
+
+	for k, v := range params {
+		cp[k] = v
+	}
+
+	return cp
+}
@@ -0,0 +1,80 @@
+package rtree
+
+import "testing"
+
+func TestWarm(t *testing.T) {
+	keys := []string{"/api/users/1", "/api/users/2", "/api/posts/1", ""}
+
+	tt := []struct {
+		name            string
+		opts            []OptionFunc[*Route]
+		expectedMatched int
+		// expectedHits are the cache hits of looking up
+		// the keys again, in the reverse order.
+		expectedHits uint64
+	}{
+		{
+			name:            "without caches",
+			expectedMatched: 0,
+			expectedHits:    0,
+		},
+		{
+			name:            "lookup cache",
+			opts:            []OptionFunc[*Route]{WithLookupCache[*Route](16)},
+			expectedMatched: 2,
+			expectedHits:    3,
+		},
+		{
+			name:            "lookup cache smaller than the keys",
+			opts:            []OptionFunc[*Route]{WithLookupCache[*Route](2)},
+			expectedMatched: 2,
+			expectedHits:    1,
+		},
+		{
+			name:            "prefix cache",
+			opts:            []OptionFunc[*Route]{WithPrefixCache[*Route](16)},
+			expectedMatched: 2,
+			// The prefix of the users has routes below it, so only
+			// the prefix of the posts is cached as a result.
+			expectedHits: 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics := &Metrics{}
+
+			tree := New(append(tc.opts, WithMetrics[*Route](metrics))...)
+
+			if err := tree.Insert("/api/users", getRoute()); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if err := tree.Insert("/api/users/{id}", getRoute()); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if matched := tree.Warm(keys); matched != tc.expectedMatched {
+				t.Errorf("expected matched keys: %d; got: %d\n", tc.expectedMatched, matched)
+			}
+
+			if metrics.Lookups() != 0 || metrics.CacheHits() != 0 {
+				t.Errorf("expected the warming not to be observed; got: %d lookups\n", metrics.Lookups())
+			}
+
+			for i := 2; i >= 0; i-- {
+				key := keys[i]
+
+				if tree.prefixCache != nil {
+					tree.FindLongestMatch(key)
+				} else {
+					tree.Find(key)
+				}
+			}
+
+			if hits := metrics.CacheHits(); hits != tc.expectedHits {
+				t.Errorf("expected cache hits: %d; got: %d\n", tc.expectedHits, hits)
+			}
+		})
+	}
+}
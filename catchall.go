@@ -0,0 +1,152 @@
+package rtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// catchAllSuffix marks a catch-all param, eg. /files/{path...}.
+const catchAllSuffix = "..."
+
+var (
+	errBadCatchAll = fmt.Errorf("%w: catch-all param must be the last segment", errBadPathParamSyntax)
+	errDotInParam  = fmt.Errorf("%w: ambiguous dot in param", errBadPathParamSyntax)
+)
+
+// ResolutionOrder decides between a catch-all route and
+// the other routes overlapping with it.
+type ResolutionOrder uint8
+
+const (
+	// StaticFirst resolves to the most specific route, so the
+	// catch-all routes only get the keys nothing else matches.
+	StaticFirst ResolutionOrder = iota
+	// CatchAllFirst resolves to a matching catch-all route – the
+	// most specific one –, even if there is a more specific route,
+	// eg. /files/{path...} wins over /files/config.json.
+	CatchAllFirst
+)
+
+// WithResolutionOrder sets the default resolution order of the lookups.
+// By default it is StaticFirst.
+func WithResolutionOrder[T storeValue](order ResolutionOrder) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if order > CatchAllFirst {
+			t.invalidOption("resolution order", order)
+			return
+		}
+
+		t.setOption("resolution order", order)
+		t.resolution = order
+	}
+}
+
+// FindWithOrder is the same as Find, but it resolves the key
+// with the given order, instead of the default one of the tree.
+func (t *Tree[T]) FindWithOrder(key string, order ResolutionOrder) *FoundNode[T] {
+	if err := checkTree(t); err != nil {
+		return nil
+	}
+
+	return t.observe(key, func(key string) *FoundNode[T] {
+		return t.findWithDefault(key, order)
+	})
+}
+
+// parseParam parses the content of a path param – without the
+// curly brackets – into its name, its matcher and whether it is a
+// catch-all param.
+func parseParam(param string) (string, string, bool) {
+	catchAll := strings.HasSuffix(param, catchAllSuffix)
+
+	if catchAll {
+		param = param[:len(param)-len(catchAllSuffix)]
+	}
+
+	name, matcher := splitParam(param)
+
+	return name, matcher, catchAll
+}
+
+// checkParam checks the param of the url between the given indices.
+// A catch-all param must be the whole last segment. The names and the
+// matchers could have dots, but they could not end with one, since the
+// dots before the closing bracket are the suffix of a catch-all.
+func checkParam(url string, start, end int) error {
+	name, matcher, catchAll := parseParam(url[start:end])
+
+	// Params must be named, even if they have a matcher.
+	if name == "" {
+		return errEmptyParamName
+	}
+
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(matcher, ".") {
+		return errDotInParam
+	}
+
+	if !catchAll {
+		return nil
+	}
+
+	// The catch-all param starts a segment – or the whole key of an object.
+	if (start != 1 && (start < 2 || url[start-2] != slash)) || end != len(url)-1 {
+		return errBadCatchAll
+	}
+
+	return nil
+}
+
+// hasCatchAll reports whether the route ends with a catch-all param.
+func (nv *NodeValue[T]) hasCatchAll() bool {
+	l := len(nv.params)
+
+	return l > 0 && nv.params[l-1].catchAll
+}
+
+// Segments returns the segments of the value of the param, eg. the ones
+// captured by a catch-all param – which are joined by slashes in the
+// value –, or nil if there is no such param. The value of an ordinary
+// param is a single segment. Use the Segments method of the match for
+// the trees with custom delimiters.
+func (mp matchedParams) Segments(name string) []string {
+	return paramSegments(mp, name, slash)
+}
+
+// Segments returns the segments of the value of the param, split by the
+// segment delimiter of the tree, or nil if there is no such param.
+func (fn *FoundNode[T]) Segments(name string) []string {
+	delimiter := fn.delimiter
+	if delimiter == 0 {
+		delimiter = slash
+	}
+
+	return paramSegments(fn.params, name, delimiter)
+}
+
+func paramSegments(mp matchedParams, name string, delimiter byte) []string {
+	value, exists := mp[name]
+	if !exists {
+		return nil
+	}
+
+	return strings.Split(value, string(delimiter))
+}
+
+// splitsCatchAll reports whether splitting the node keys at their common
+// prefix would part the suffix of a catch-all param – eg. of {path...}
+// next to {path.ext} –, so the child would not close a catch-all any more.
+func splitsCatchAll(a, b string, lcp int) bool {
+	if lcp == 0 || a[lcp-1] != '.' {
+		return false
+	}
+
+	return closesAfterDots(a[lcp:]) || closesAfterDots(b[lcp:])
+}
+
+// closesAfterDots reports whether the rest of a node key
+// closes a param after some dots – or right away.
+func closesAfterDots(rest string) bool {
+	rest = strings.TrimLeft(rest, ".")
+
+	return rest != "" && rest[0] == curlyEnd
+}
@@ -0,0 +1,332 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckPathParamsCatchAll(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		err   error
+	}
+
+	tt := []testCase{
+		{
+			name:  "no error if the catch-all is the last segment",
+			input: "/files/{path...}",
+			err:   nil,
+		},
+		{
+			name:  "no error if the catch-all has a matcher",
+			input: "/files/{path:ext...}",
+			err:   nil,
+		},
+		{
+			name:  "error if the catch-all is not the last segment",
+			input: "/files/{path...}/meta",
+			err:   errBadCatchAll,
+		},
+		{
+			name:  "error if the catch-all is not a whole segment",
+			input: "/files/v{path...}",
+			err:   errBadCatchAll,
+		},
+		{
+			name:  "error if the catch-all has no name",
+			input: "/files/{...}",
+			err:   errEmptyParamName,
+		},
+		{
+			name:  "dot in the name of the param",
+			input: "/files/{file.ext}",
+		},
+		{
+			name:  "dot in the name of the matcher",
+			input: "/files/{file:a.b}",
+		},
+		{
+			name:  "dots in the name of the catch-all",
+			input: "/files/{file.ext...}",
+		},
+		{
+			name:  "error if the name of the param ends with a dot",
+			input: "/files/{file.}",
+			err:   errDotInParam,
+		},
+		{
+			name:  "error if the name of the matcher ends with a dot",
+			input: "/files/{file:a.}",
+			err:   errDotInParam,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := checkPathParams(tc.input); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+
+			if tc.err != nil && !errors.Is(checkPathParams(tc.input), errBadPathParamSyntax) {
+				t.Error("expected to be a syntax error")
+			}
+		})
+	}
+}
+
+func TestFindCatchAll(t *testing.T) {
+	type testCase struct {
+		name            string
+		key             string
+		order           ResolutionOrder
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	tree := New[*Route]()
+
+	for _, k := range []string{
+		"/files/{path...}",
+		"/files/config.json",
+		"/files/img/{rest...}",
+		"/files/{name}/meta",
+	} {
+		if err := tree.Insert(k, &Route{name: k}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:            "catch-all captures multiple segments",
+			key:             "/files/a/b/c.txt",
+			order:           StaticFirst,
+			expectedPattern: "/files/{path...}",
+			expectedParams:  matchedParams{"path": "a/b/c.txt"},
+		},
+		{
+			name:            "static route wins over the catch-all",
+			key:             "/files/config.json",
+			order:           StaticFirst,
+			expectedPattern: "/files/config.json",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "param route wins over the catch-all",
+			key:             "/files/doc/meta",
+			order:           StaticFirst,
+			expectedPattern: "/files/{name}/meta",
+			expectedParams:  matchedParams{"name": "doc"},
+		},
+		{
+			name:            "more specific catch-all wins",
+			key:             "/files/img/a/b.png",
+			order:           StaticFirst,
+			expectedPattern: "/files/img/{rest...}",
+			expectedParams:  matchedParams{"rest": "a/b.png"},
+		},
+		{
+			name:            "catch-all wins over the static route",
+			key:             "/files/config.json",
+			order:           CatchAllFirst,
+			expectedPattern: "/files/{path...}",
+			expectedParams:  matchedParams{"path": "config.json"},
+		},
+		{
+			name:            "catch-all wins over the param route",
+			key:             "/files/doc/meta",
+			order:           CatchAllFirst,
+			expectedPattern: "/files/{path...}",
+			expectedParams:  matchedParams{"path": "doc/meta"},
+		},
+		{
+			name:            "more specific catch-all wins, when catch-alls are first",
+			key:             "/files/img/a/b.png",
+			order:           CatchAllFirst,
+			expectedPattern: "/files/img/{rest...}",
+			expectedParams:  matchedParams{"rest": "a/b.png"},
+		},
+		{
+			name:            "no match above the catch-all",
+			key:             "/files",
+			order:           CatchAllFirst,
+			expectedPattern: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.FindWithOrder(tc.key, tc.order)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Errorf("expected not to find, but got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetPattern(); got != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, got)
+			}
+
+			if got := fn.GetParams(); !reflect.DeepEqual(got, tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, got)
+			}
+		})
+	}
+}
+
+func TestWithResolutionOrder(t *testing.T) {
+	tree := New(WithResolutionOrder[*Route](CatchAllFirst))
+
+	for _, k := range []string{"/files/config.json", "/files/{path...}"} {
+		if err := tree.Insert(k, &Route{name: k}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if got := tree.Find("/files/config.json").GetPattern(); got != "/files/{path...}" {
+		t.Errorf("expected pattern: %s; got: %s\n", "/files/{path...}", got)
+	}
+
+	if got := tree.FindWithOrder("/files/config.json", StaticFirst).GetPattern(); got != "/files/config.json" {
+		t.Errorf("expected pattern: %s; got: %s\n", "/files/config.json", got)
+	}
+}
+
+func TestExpandCatchAll(t *testing.T) {
+	got, err := Expand("/files/{path...}", map[string]string{"path": "a/b/c.txt"})
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got != "/files/a/b/c.txt" {
+		t.Errorf("expected: %s; got: %s\n", "/files/a/b/c.txt", got)
+	}
+}
+
+func TestParamSegments(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/files/{dir}/{path...}", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	dotted := New(WithDelimiters[*Route](Delimiters{Segment: '.', ParamStart: '{', ParamEnd: '}'}))
+
+	if err := dotted.Insert("logs.{rest...}", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tt := []struct {
+		name     string
+		tree     *Tree[*Route]
+		key      string
+		param    string
+		expected []string
+	}{
+		{name: "catch-all", tree: tree, key: "/files/docs/2024/q1/report.pdf", param: "path", expected: []string{"2024", "q1", "report.pdf"}},
+		{name: "single segment catch-all", tree: tree, key: "/files/docs/report.pdf", param: "path", expected: []string{"report.pdf"}},
+		{name: "ordinary param", tree: tree, key: "/files/docs/report.pdf", param: "dir", expected: []string{"docs"}},
+		{name: "unknown param", tree: tree, key: "/files/docs/report.pdf", param: "name", expected: nil},
+		{name: "custom delimiter", tree: dotted, key: "logs.app.error.db", param: "rest", expected: []string{"app", "error", "db"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tc.tree.Find(tc.key)
+			if fn == nil {
+				t.Fatalf("expected match of %s; got nil\n", tc.key)
+			}
+
+			if got := fn.Segments(tc.param); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected segments: %q; got: %q\n", tc.expected, got)
+			}
+
+			// The params split on slashes, regardless of the delimiter.
+			if tc.tree != tree {
+				return
+			}
+
+			if got := fn.GetParams().Segments(tc.param); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected segments of the params: %q; got: %q\n", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDottedParams(t *testing.T) {
+	tree := New[*Route]()
+
+	for _, key := range []string{"/files/{file.name}", "/files/{file.name}/{part.id}", "/b/{path...}", "/b/{pathx}"} {
+		if err := tree.Insert(key, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []struct {
+		name            string
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}{
+		{
+			name:            "dot in the name",
+			key:             "/files/a.txt",
+			expectedPattern: "/files/{file.name}",
+			expectedParams:  matchedParams{"file.name": "a.txt"},
+		},
+		{
+			name:            "dots in the names",
+			key:             "/files/a.txt/1",
+			expectedPattern: "/files/{file.name}/{part.id}",
+			expectedParams:  matchedParams{"file.name": "a.txt", "part.id": "1"},
+		},
+		{
+			name:            "param next to a catch-all",
+			key:             "/b/x",
+			expectedPattern: "/b/{pathx}",
+			expectedParams:  matchedParams{"pathx": "x"},
+		},
+		{
+			name:            "catch-all next to a param",
+			key:             "/b/x/y",
+			expectedPattern: "/b/{path...}",
+			expectedParams:  matchedParams{"path": "x/y"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.Find(tc.key)
+			if fn == nil {
+				t.Fatalf("expected match: %s; got none\n", tc.expectedPattern)
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+			}
+		})
+	}
+
+	// The dots of a name could not be told apart from the suffix of a catch-all.
+	for _, key := range []string{"/files/{file...}", "/b/{path.ext}", "/b/{path...x}"} {
+		if err := tree.Insert(key, getRoute()); !errors.Is(err, errDotInParam) {
+			t.Errorf("expected error of %s: %v; got: %v\n", key, errDotInParam, err)
+		}
+	}
+
+	if fn := tree.Find("/b/x/y"); fn == nil || fn.GetPattern() != "/b/{path...}" {
+		t.Errorf("expected the catch-all to be intact after the failed inserts\n")
+	}
+}
@@ -0,0 +1,45 @@
+package rtree
+
+// ChainedTrees is a read-only view over multiple trees, which are searched
+// in the given order, eg. an „overrides” tree layered over a „base” tree.
+// The trees are not merged, so later changes of them are visible in the view.
+type ChainedTrees[T storeValue] struct {
+	trees []*Tree[T]
+}
+
+// Chain creates a read-only view over the given trees.
+// Nil trees are skipped.
+func Chain[T storeValue](trees ...*Tree[T]) *ChainedTrees[T] {
+	c := &ChainedTrees[T]{
+		trees: make([]*Tree[T], 0, len(trees)),
+	}
+
+	for _, t := range trees {
+		if t != nil {
+			c.trees = append(c.trees, t)
+		}
+	}
+
+	return c
+}
+
+// Find returns the first match of the key, trying the trees in order.
+func (c *ChainedTrees[T]) Find(key string) *FoundNode[T] {
+	return c.first(key, (*Tree[T]).Find)
+}
+
+// FindLongestMatch returns the first longest match of the key,
+// trying the trees in order.
+func (c *ChainedTrees[T]) FindLongestMatch(key string) *FoundNode[T] {
+	return c.first(key, (*Tree[T]).FindLongestMatch)
+}
+
+func (c *ChainedTrees[T]) first(key string, find func(*Tree[T], string) *FoundNode[T]) *FoundNode[T] {
+	for _, t := range c.trees {
+		if fn := find(t, key); fn != nil {
+			return fn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package rtree
+
+import "testing"
+
+func TestChain(t *testing.T) {
+	base := New[*Route]()
+	overrides := New[*Route]()
+
+	for _, r := range []struct {
+		tree *Tree[*Route]
+		key  string
+		name string
+	}{
+		{tree: base, key: "/api/users/{id}", name: "base-user"},
+		{tree: base, key: "/api/products", name: "base-products"},
+		{tree: base, key: "/static", name: "base-static"},
+		{tree: overrides, key: "/api/users/{id}", name: "override-user"},
+		{tree: overrides, key: "/static/admin", name: "override-static"},
+	} {
+		if err := r.tree.Insert(r.key, &Route{name: r.name}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	chain := Chain(overrides, nil, base)
+
+	type testCase struct {
+		name         string
+		find         func(string) *FoundNode[*Route]
+		key          string
+		expectedName string
+	}
+
+	tt := []testCase{
+		{
+			name:         "the first tree wins",
+			find:         chain.Find,
+			key:          "/api/users/1",
+			expectedName: "override-user",
+		},
+		{
+			name:         "falls through to the next tree",
+			find:         chain.Find,
+			key:          "/api/products",
+			expectedName: "base-products",
+		},
+		{
+			name:         "no match in any tree",
+			find:         chain.Find,
+			key:          "/api/orders",
+			expectedName: "",
+		},
+		{
+			name:         "longest match of the first tree",
+			find:         chain.FindLongestMatch,
+			key:          "/static/admin/index.html",
+			expectedName: "override-static",
+		},
+		{
+			name:         "longest match falls through to the next tree",
+			find:         chain.FindLongestMatch,
+			key:          "/static/index.html",
+			expectedName: "base-static",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tc.find(tc.key)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Errorf("expected not to find, but got: %s\n", fn.GetValue().name)
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+		})
+	}
+}
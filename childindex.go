@@ -0,0 +1,85 @@
+package rtree
+
+// childIndexThreshold is the number of the children, from which
+// on the lookups find the candidate children by the child index,
+// instead of trying all of them one by one.
+const childIndexThreshold = 16
+
+// childIndex maps the first byte of the key of every child to its
+// position – plus one, so zero means there is no such child. Since the
+// siblings never start with the same byte – not even the params –, the
+// first byte is a perfect hash of the children. An index is never
+// modified, it is built again, once the children change.
+type childIndex [256]uint16
+
+// position returns the position of the child starting with
+// the given byte, or -1 if there is no such child.
+func (idx *childIndex) position(b byte) int {
+	return int(idx[b]) - 1
+}
+
+// reindex builds the child index of the node again,
+// if it is wide enough, otherwise it drops the index.
+func (n *Node[T]) reindex() {
+	if len(n.children) < childIndexThreshold {
+		n.index = nil
+		return
+	}
+
+	idx := new(childIndex)
+
+	for i, ch := range n.children {
+		// Neither could happen, but the linear search is always right.
+		if ch.key == "" || idx[ch.key[0]] != 0 {
+			n.index = nil
+			return
+		}
+
+		idx[ch.key[0]] = uint16(i + 1)
+	}
+
+	n.index = idx
+}
+
+// findChildRec searches the children of the node for the rest of the key.
+// Outside of the params only two children could match at all: the one
+// starting with the first byte of the key, and the one starting with a
+// param. A wide node finds them by its index – trying them in the same
+// order as the linear search would –, so the lookup does not degrade
+// with the number of the children.
+func findChildRec[T storeValue](n *Node[T], key string, isWildcard bool, s *search[T]) *Node[T] {
+	if n.index == nil || isWildcard {
+		for _, c := range n.children {
+			if found := findRec(c, key, isWildcard, s); found != nil || s.budget.exceeded {
+				return found
+			}
+		}
+
+		return nil
+	}
+
+	var (
+		first  = n.index.position(key[0])
+		second = n.index.position(curlyStart)
+	)
+
+	if first == second {
+		second = -1
+	}
+
+	if second != -1 && (first == -1 || second < first) {
+		first, second = second, first
+	}
+
+	for _, i := range [2]int{first, second} {
+		if i == -1 {
+			continue
+		}
+
+		if found := findRec(n.children[i], key, isWildcard, s); found != nil || s.budget.exceeded {
+			return found
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package rtree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// nodeAt returns the node of the tree with the given full key, or nil.
+func nodeAt[T storeValue](n *Node[T], key string) *Node[T] {
+	for n != nil {
+		if len(key) < len(n.key) || key[:len(n.key)] != n.key {
+			return nil
+		}
+
+		key = key[len(n.key):]
+
+		if key == "" {
+			return n
+		}
+
+		var next *Node[T]
+
+		for _, ch := range n.children {
+			if ch.key[0] == key[0] {
+				next = ch
+				break
+			}
+		}
+
+		n = next
+	}
+
+	return nil
+}
+
+func TestChildIndex(t *testing.T) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	var (
+		rnd     = rand.New(rand.NewSource(1))
+		tree    = New[*Route]()
+		tenants = make([]string, 0)
+		seen    = make(map[string]struct{})
+	)
+
+	for len(tenants) < 2000 {
+		id := randomString(rnd, alphabet, 6)
+
+		if _, exists := seen[id]; exists {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		tenants = append(tenants, id)
+	}
+
+	for _, id := range tenants {
+		if err := tree.Insert(fmt.Sprintf("/tenants/%s/config", id), getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	for _, p := range []string{"/tenants/{id}/config", "/tenants/{id}/users/{user}", "/tenants/{id}/files/{path...}"} {
+		if err := tree.Insert(p, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	wide := nodeAt(tree.root, "/tenants/")
+	if wide == nil || wide.index == nil {
+		t.Fatalf("expected the child index of the wide node\n")
+	}
+
+	tt := []struct {
+		key      string
+		expected string
+	}{
+		{key: "/tenants/" + tenants[0] + "/config", expected: "/tenants/" + tenants[0] + "/config"},
+		{key: "/tenants/" + tenants[1999] + "/config", expected: "/tenants/" + tenants[1999] + "/config"},
+		{key: "/tenants/unknown/config", expected: "/tenants/{id}/config"},
+		{key: "/tenants/" + tenants[5] + "/users/7", expected: "/tenants/{id}/users/{user}"},
+		{key: "/tenants/" + tenants[5] + "/files/a/b", expected: "/tenants/{id}/files/{path...}"},
+		{key: "/tenants/-/config", expected: "/tenants/{id}/config"},
+		{key: "/tenants/" + tenants[5] + "/nothing", expected: ""},
+	}
+
+	for _, tc := range tt {
+		fn := tree.Find(tc.key)
+
+		if tc.expected == "" {
+			if fn != nil {
+				t.Errorf("%s: expected no match; got: %s\n", tc.key, fn.GetPattern())
+			}
+			continue
+		}
+
+		if fn == nil || fn.GetPattern() != tc.expected {
+			t.Errorf("%s: expected pattern: %s; got: %v\n", tc.key, tc.expected, fn)
+		}
+	}
+
+	// Only the candidates are visited, instead of every child.
+	if b := tree.Explain("/tenants/unknown/config").Backtracks(); b > 4 {
+		t.Errorf("expected at most 4 backtracks; got: %d\n", b)
+	}
+
+	// The index follows the deletes, and it is dropped, once the node is narrow.
+	for _, id := range tenants {
+		if err := tree.Delete(fmt.Sprintf("/tenants/%s/config", id)); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		if rnd.Intn(50) == 0 {
+			if fn := tree.Find("/tenants/" + id + "/config"); fn == nil || fn.GetPattern() != "/tenants/{id}/config" {
+				t.Fatalf("expected the wildcard route after deleting %s; got: %v\n", id, fn)
+			}
+		}
+	}
+
+	if err := tree.Walk(func(n *Node[*Route]) WalkVerdict {
+		if n.index != nil {
+			t.Errorf("expected no child index of the narrow node %q\n", n.key)
+		}
+
+		return Continue
+	}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+}
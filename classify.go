@@ -0,0 +1,125 @@
+package rtree
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Unmatched is the label of the URLs, that match no route of a Classifier.
+const Unmatched = "unmatched"
+
+// minParallelBatch is the smallest batch, that is classified in parallel.
+const minParallelBatch = 1024
+
+// Classifier maps raw URLs to low-cardinality route labels, eg. for
+// analytics: /users/42 and /users/43 are both labeled /users/{id}. It is
+// safe for concurrent use, as long as the routes are not added in the
+// meantime – or the tree is read-locked, see WithReadLocking.
+type Classifier struct {
+	tree *Tree[string]
+}
+
+// NewClassifier creates an empty classifier, whose
+// tree is configured by the given options.
+func NewClassifier(opts ...OptionFunc[string]) *Classifier {
+	return &Classifier{
+		tree: New(opts...),
+	}
+}
+
+// Add adds the route with the given label. If the label is
+// empty, the route is labeled with its pattern.
+func (c *Classifier) Add(pattern, label string) error {
+	if label == "" {
+		label = pattern
+	}
+
+	return c.tree.Insert(pattern, label)
+}
+
+// Tree returns the tree of the classifier.
+func (c *Classifier) Tree() *Tree[string] {
+	return c.tree
+}
+
+// Classify returns the label of the route matching the path of the URL,
+// or Unmatched. The URL could be absolute, while its query and fragment
+// are ignored.
+func (c *Classifier) Classify(url string) string {
+	fn := c.tree.Find(urlPath(url))
+	if fn == nil {
+		return Unmatched
+	}
+
+	return fn.GetValue()
+}
+
+// ClassifyBatch returns the labels of the URLs, in the same order, as
+// Classify does one by one. The large batches are split amongst as many
+// workers as many CPUs could be used, eg. for processing logs. Besides
+// the lookups, a batch only allocates the returned slice and its workers.
+func (c *Classifier) ClassifyBatch(urls []string) []string {
+	labels := make([]string, len(urls))
+
+	workers := runtime.GOMAXPROCS(0)
+
+	if len(urls) < minParallelBatch || workers < 2 {
+		c.classifyRange(urls, labels)
+		return labels
+	}
+
+	var (
+		wg    sync.WaitGroup
+		chunk = (len(urls) + workers - 1) / workers
+	)
+
+	// Every worker gets a contiguous range, so they
+	// rarely write the same cache lines of the labels.
+	for start := 0; start < len(urls); start += chunk {
+		end := start + chunk
+		if end > len(urls) {
+			end = len(urls)
+		}
+
+		wg.Add(1)
+
+		go func(start, end int) {
+			defer wg.Done()
+
+			c.classifyRange(urls[start:end], labels[start:end])
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	return labels
+}
+
+// classifyRange classifies the URLs into the labels of the same length.
+func (c *Classifier) classifyRange(urls, labels []string) {
+	for i, url := range urls {
+		labels[i] = c.Classify(url)
+	}
+}
+
+// urlPath returns the path of the raw URL, without parsing it.
+func urlPath(url string) string {
+	if i := strings.IndexAny(url, "?#"); i != -1 {
+		url = url[:i]
+	}
+
+	// The path of an absolute URL starts after its host.
+	if i := strings.Index(url, "://"); i != -1 {
+		rest := url[i+len("://"):]
+
+		j := strings.IndexByte(rest, slash)
+		if j == -1 {
+			return string(slash)
+		}
+
+		return rest[j:]
+	}
+
+	return url
+}
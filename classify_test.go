@@ -0,0 +1,91 @@
+package rtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	type testCase struct {
+		name          string
+		url           string
+		expectedLabel string
+	}
+
+	c := NewClassifier()
+
+	routes := []struct {
+		pattern string
+		label   string
+	}{
+		{pattern: "/users/{id}"},
+		{pattern: "/users/{id}/posts/{post}"},
+		{pattern: "/static/{path...}", label: "static"},
+		{pattern: "/"},
+	}
+
+	for _, r := range routes {
+		if err := c.Add(r.pattern, r.label); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{name: "param route", url: "/users/42", expectedLabel: "/users/{id}"},
+		{name: "nested params", url: "/users/42/posts/7", expectedLabel: "/users/{id}/posts/{post}"},
+		{name: "custom label", url: "/static/css/site.css", expectedLabel: "static"},
+		{name: "query is ignored", url: "/users/42?page=2", expectedLabel: "/users/{id}"},
+		{name: "fragment is ignored", url: "/users/42#top", expectedLabel: "/users/{id}"},
+		{name: "absolute url", url: "https://example.com/users/42?x=1", expectedLabel: "/users/{id}"},
+		{name: "absolute url without path", url: "https://example.com", expectedLabel: "/"},
+		{name: "unmatched", url: "/groups/1", expectedLabel: Unmatched},
+		{name: "empty url", url: "", expectedLabel: Unmatched},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := c.Classify(tc.url); got != tc.expectedLabel {
+				t.Errorf("expected label: %s; got: %s\n", tc.expectedLabel, got)
+			}
+		})
+	}
+}
+
+func TestClassifyBatch(t *testing.T) {
+	c := NewClassifier()
+
+	for _, pattern := range []string{"/users/{id}", "/users/{id}/posts", "/static/{path...}"} {
+		if err := c.Add(pattern, ""); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	for _, size := range []int{0, 10, 3 * minParallelBatch} {
+		urls := make([]string, size)
+
+		for i := range urls {
+			switch i % 4 {
+			case 0:
+				urls[i] = fmt.Sprintf("/users/%d", i)
+			case 1:
+				urls[i] = fmt.Sprintf("/users/%d/posts?page=%d", i, i)
+			case 2:
+				urls[i] = fmt.Sprintf("/static/%d/app.js", i)
+			default:
+				urls[i] = fmt.Sprintf("/groups/%d", i)
+			}
+		}
+
+		labels := c.ClassifyBatch(urls)
+
+		if len(labels) != len(urls) {
+			t.Fatalf("expected %d labels; got: %d\n", len(urls), len(labels))
+		}
+
+		for i, url := range urls {
+			if expected := c.Classify(url); labels[i] != expected {
+				t.Fatalf("expected the label of %s: %s; got: %s\n", url, expected, labels[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package rtree
+
+// ValueComparer reports whether two stored values are considered equal.
+type ValueComparer[T storeValue] func(a, b T) bool
+
+// WithValueComparer makes Insert idempotent: inserting an already stored
+// key with a value equal to the stored one – according to the comparer –
+// is a no-op instead of an error. Reconciliation loops could simply
+// re-apply the same configuration this way.
+func WithValueComparer[T storeValue](cmp ValueComparer[T]) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.comparer = cmp
+	}
+}
+
+// isSameValue reports whether the given key is stored with
+// an equal value. It is always false without a comparer.
+func (t *Tree[T]) isSameValue(key string, value T) bool {
+	if t.comparer == nil {
+		return false
+	}
+
+	n := findExactRec(t.root, key)
+	if n == nil {
+		return false
+	}
+
+	return t.comparer(n.value.value, value)
+}
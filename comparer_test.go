@@ -0,0 +1,68 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithValueComparer(t *testing.T) {
+	type testCase struct {
+		name     string
+		opts     []OptionFunc[*Route]
+		value    *Route
+		err      error
+		expected string
+	}
+
+	byName := func(a, b *Route) bool { return a.name == b.name }
+
+	tt := []testCase{
+		{
+			name:     "error without comparer",
+			opts:     nil,
+			value:    &Route{name: "users"},
+			err:      errKeyIsAlreadyStored,
+			expected: "users",
+		},
+		{
+			name:     "no-op with comparer and equal value",
+			opts:     []OptionFunc[*Route]{WithValueComparer(byName)},
+			value:    &Route{name: "users"},
+			err:      nil,
+			expected: "users",
+		},
+		{
+			name:     "error with comparer and different value",
+			opts:     []OptionFunc[*Route]{WithValueComparer(byName)},
+			value:    &Route{name: "other"},
+			err:      errKeyIsAlreadyStored,
+			expected: "users",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			for _, r := range []string{"/api/users/{id}", "/api/users"} {
+				if err := tree.Insert(r, &Route{name: "users"}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			gen := tree.Generation()
+
+			if err := tree.Insert("/api/users/{id}", tc.value); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+
+			if tree.Generation() != gen {
+				t.Error("expected generation not to change")
+			}
+
+			if got := tree.Find("/api/users/1").GetValue().name; got != tc.expected {
+				t.Errorf("expected value: %s; got: %s\n", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package rtree
+
+import "strings"
+
+type keyOpKind uint8
+
+const (
+	// opText is a run of bytes, which is compared with the search key
+	// outside of a param, and skipped inside of one – as a param name.
+	opText keyOpKind = iota
+	// opOpen starts a param.
+	opOpen
+	// opClose ends a param, skipping the search key until the next slash.
+	opClose
+	// opCloseCatchAll ends a catch-all param, skipping the rest of the search key.
+	opCloseCatchAll
+)
+
+// keyOp is a single step of the compiled form of a node key.
+type keyOp struct {
+	kind keyOpKind
+	text string
+}
+
+// compileKey compiles the given node key, so the lookups do not have to
+// interpret it byte by byte – as getOffsets does. The escaped chars stay
+// in the text runs, since the search key is escaped as well. It returns
+// nil for the keys without curly brackets, which are a single text run.
+func compileKey(key string) []keyOp {
+	if !strings.ContainsAny(key, `{}`) {
+		return nil
+	}
+
+	var (
+		ops   = make([]keyOp, 0, 4)
+		start = 0
+	)
+
+	flush := func(end int) {
+		if end > start {
+			ops = append(ops, keyOp{kind: opText, text: key[start:end]})
+		}
+	}
+
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case escapeChar:
+			i++
+		case curlyStart:
+			flush(i)
+			ops = append(ops, keyOp{kind: opOpen})
+			start = i + 1
+		case curlyEnd:
+			kind := opClose
+
+			if strings.HasSuffix(key[start:i], catchAllSuffix) {
+				kind = opCloseCatchAll
+			}
+
+			flush(i)
+			ops = append(ops, keyOp{kind: kind})
+			start = i + 1
+		}
+	}
+
+	flush(len(key))
+
+	return ops
+}
+
+// matchKey matches the node key against the start of the search key. The
+// isWildcard tells whether the search is inside of a param, that was
+// opened by an ancestor. It returns the number of the consumed bytes of
+// the search key, whether the search is still inside of a param and
+// whether the whole node key matched.
+func (n *Node[T]) matchKey(searchKey string, isWildcard bool) (int, bool, bool) {
+	if n.ops == nil {
+		if isWildcard {
+			return 0, true, true
+		}
+
+		if !strings.HasPrefix(searchKey, n.key) {
+			return 0, false, false
+		}
+
+		return len(n.key), false, true
+	}
+
+	j := 0
+
+	for _, op := range n.ops {
+		switch op.kind {
+		case opText:
+			if isWildcard {
+				continue
+			}
+
+			if !strings.HasPrefix(searchKey[j:], op.text) {
+				return j, false, false
+			}
+
+			j += len(op.text)
+		case opOpen:
+			// A param never matches an empty segment at the end of the key.
+			if j >= len(searchKey) {
+				return j, false, false
+			}
+
+			isWildcard = true
+		case opClose:
+			isWildcard = false
+
+			if idx := strings.IndexByte(searchKey[j:], slash); idx != -1 {
+				j += idx
+			} else {
+				j = len(searchKey)
+			}
+		case opCloseCatchAll:
+			isWildcard = false
+			j = len(searchKey)
+		}
+	}
+
+	return j, isWildcard, true
+}
+
+// paramCheck is the compiled form of the checks of a single param.
+type paramCheck struct {
+	paramInfo
+
+	policy   *ParamPolicy
+	matchers []SegmentMatcher
+	// unknown is true, if the param references an unregistered matcher.
+	unknown bool
+	// nonEmpty is true, if the param must not capture an empty value.
+	nonEmpty bool
+}
+
+// compileParams resolves the param policies, the constraints of the route
+// and the registered matchers of every param, so the lookups do not have
+// to look them up in maps on every match.
+func (t *Tree[T]) compileParams(nv *NodeValue[T]) []paramCheck {
+	checks := make([]paramCheck, len(nv.params))
+
+	for i, pi := range nv.params {
+		pc := paramCheck{
+			paramInfo: pi,
+			nonEmpty:  t.nonEmptyParams && !pi.catchAll,
+		}
+
+		if policy, exists := t.paramPolicies[pi.key]; exists {
+			pc.policy = &policy
+		} else {
+			pc.policy = t.paramPolicy
+		}
+
+		if c, exists := nv.meta.constraints[pi.key]; exists {
+			pc.matchers = append(pc.matchers, c)
+		}
+
+		if pi.matcher != "" {
+			m, exists := t.matchers[pi.matcher]
+
+			pc.unknown = !exists
+			pc.matchers = append(pc.matchers, m)
+		}
+
+		checks[i] = pc
+	}
+
+	return checks
+}
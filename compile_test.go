@@ -0,0 +1,150 @@
+package rtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileKey(t *testing.T) {
+	type testCase struct {
+		name     string
+		key      string
+		expected []keyOp
+	}
+
+	tt := []testCase{
+		{
+			name:     "static key",
+			key:      "/api/users",
+			expected: nil,
+		},
+		{
+			name: "param between static parts",
+			key:  "/api/{id}/get",
+			expected: []keyOp{
+				{kind: opText, text: "/api/"},
+				{kind: opOpen},
+				{kind: opText, text: "id"},
+				{kind: opClose},
+				{kind: opText, text: "/get"},
+			},
+		},
+		{
+			name: "continuation of a param",
+			key:  "d}/{x}",
+			expected: []keyOp{
+				{kind: opText, text: "d"},
+				{kind: opClose},
+				{kind: opText, text: "/"},
+				{kind: opOpen},
+				{kind: opText, text: "x"},
+				{kind: opClose},
+			},
+		},
+		{
+			name: "catch-all",
+			key:  "/files/{path...}",
+			expected: []keyOp{
+				{kind: opText, text: "/files/"},
+				{kind: opOpen},
+				{kind: opText, text: "path..."},
+				{kind: opCloseCatchAll},
+			},
+		},
+		{
+			name: "escaped brackets",
+			key:  "/\\{a\\}/{b}",
+			expected: []keyOp{
+				{kind: opText, text: "/\\{a\\}/"},
+				{kind: opOpen},
+				{kind: opText, text: "b"},
+				{kind: opClose},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := compileKey(tc.key); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected: %v; got: %v\n", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestMatchKeyAgreesWithGetOffsets checks the compiled keys
+// against the byte by byte interpretation of them.
+func TestMatchKeyAgreesWithGetOffsets(t *testing.T) {
+	nodeKeys := []string{
+		"/api/users",
+		"/api/{id}",
+		"/api/{id}/get",
+		"{id}",
+		"{id}/",
+		"d}",
+		"d}/list",
+		"id",
+		"/{a}/{b}",
+		"/files/{path...}",
+		"{path...}",
+		"/\\{raw\\}",
+		"/\\{raw\\}/{id}",
+		"x/{id}",
+	}
+
+	searchKeys := []string{
+		"",
+		"/",
+		"/api/users",
+		"/api/5",
+		"/api/5/get",
+		"/api/5/list",
+		"5",
+		"5/",
+		"5/list",
+		"/a/b",
+		"/files/a/b/c",
+		"/\\{raw\\}",
+		"/\\{raw\\}/7",
+		"x/",
+		"x/1",
+	}
+
+	for _, key := range nodeKeys {
+		n := createNewNode[*Route](key, nil)
+
+		for _, search := range searchKeys {
+			for _, wildcard := range []bool{false, true} {
+				if wildcard && !continuesParam(key) {
+					continue
+				}
+
+				i, j, stillWildcard := getOffsets(key, search, wildcard)
+
+				offset, gotWildcard, ok := n.matchKey(search, wildcard)
+
+				if ok != (i == len(key)) {
+					t.Errorf("%q on %q (wildcard: %v): expected match: %v; got: %v\n", search, key, wildcard, i == len(key), ok)
+					continue
+				}
+
+				if ok && (offset != j || gotWildcard != stillWildcard) {
+					t.Errorf("%q on %q (wildcard: %v): expected: %d, %v; got: %d, %v\n", search, key, wildcard, j, stillWildcard, offset, gotWildcard)
+				}
+			}
+		}
+	}
+}
+
+// continuesParam reports whether the node key could be entered inside of
+// a param: its part before the closing bracket must be a valid param name.
+func continuesParam(key string) bool {
+	name := key
+
+	if idx := strings.IndexByte(key, curlyEnd); idx != -1 {
+		name = key[:idx]
+	}
+
+	return !strings.ContainsAny(name, "/\\{")
+}
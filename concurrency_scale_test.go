@@ -0,0 +1,12 @@
+//go:build !stress
+
+package rtree
+
+// The scale of the concurrency tests. The stress build tag
+// runs them on a much larger scale: go test -race -tags stress.
+const (
+	concurrencyWriters  = 4
+	concurrencyReaders  = 4
+	concurrencyPatterns = 16
+	concurrencyRounds   = 200
+)
@@ -0,0 +1,11 @@
+//go:build stress
+
+package rtree
+
+// The scale of the concurrency tests in the stress mode.
+const (
+	concurrencyWriters  = 16
+	concurrencyReaders  = 16
+	concurrencyPatterns = 256
+	concurrencyRounds   = 20000
+)
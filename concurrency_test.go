@@ -0,0 +1,382 @@
+package rtree
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+var concurrencySeed = flag.Int64("concurrency.seed", 0, "the seed of the randomized schedules of the concurrency tests, 0 means a random one")
+
+// The routes, that are present during the whole concurrency test,
+// and a key matching each of them, with the expected params.
+var stableConcurrencyRoutes = []struct {
+	pattern string
+	key     string
+	params  map[string]string
+}{
+	{pattern: "/health", key: "/health", params: map[string]string{}},
+	{pattern: "/vol", key: "/vol", params: map[string]string{}},
+	{pattern: "/api/users/{id}", key: "/api/users/1", params: map[string]string{"id": "1"}},
+	{pattern: "/api/users/{id}/posts", key: "/api/users/2/posts", params: map[string]string{"id": "2"}},
+	{pattern: "/static/{path...}", key: "/static/css/site.css", params: map[string]string{"path": "css/site.css"}},
+}
+
+// concurrencySchedule returns the random source of the given goroutine.
+// Every run logs its base seed, so a failing schedule could be replayed
+// by the -concurrency.seed flag.
+func concurrencySchedule(t *testing.T) func(worker int) *rand.Rand {
+	t.Helper()
+
+	seed := *concurrencySeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	t.Logf("seed: %d\n", seed)
+
+	return func(worker int) *rand.Rand {
+		return rand.New(rand.NewSource(seed + int64(worker)))
+	}
+}
+
+// maybeYield yields the processor at random, so the
+// goroutines interleave differently on every run.
+func maybeYield(r *rand.Rand) {
+	if r.Intn(4) == 0 {
+		runtime.Gosched()
+	}
+}
+
+func newConcurrencyTree(t *testing.T, opts ...OptionFunc[*Route]) *Tree[*Route] {
+	t.Helper()
+
+	tree := New(opts...)
+
+	for _, r := range stableConcurrencyRoutes {
+		if err := tree.Insert(r.pattern, &Route{name: r.pattern}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	return tree
+}
+
+// volatilePattern returns the i-th pattern owned by the given writer.
+// Every other pattern has a param, so the writers split and merge
+// the static and the param nodes as well.
+func volatilePattern(writer, i int) string {
+	if i%2 == 0 {
+		return fmt.Sprintf("/vol/w%d/k%d", writer, i)
+	}
+
+	return fmt.Sprintf("/vol/w%d/k%d/{id}", writer, i)
+}
+
+func volatileKey(writer, i int) string {
+	if i%2 == 0 {
+		return fmt.Sprintf("/vol/w%d/k%d", writer, i)
+	}
+
+	return fmt.Sprintf("/vol/w%d/k%d/x", writer, i)
+}
+
+// checkStableRoutes checks, that all the stable routes are found with their params.
+func checkStableRoutes(t *testing.T, tree *Tree[*Route]) bool {
+	for _, r := range stableConcurrencyRoutes {
+		fn := tree.Find(r.key)
+
+		if fn == nil {
+			t.Errorf("%s: expected match; got <nil>\n", r.key)
+			return false
+		}
+
+		if fn.GetPattern() != r.pattern {
+			t.Errorf("%s: expected pattern: %s; got: %s\n", r.key, r.pattern, fn.GetPattern())
+			return false
+		}
+
+		for k, v := range r.params {
+			if got := fn.GetParams()[k]; got != v {
+				t.Errorf("%s: expected param %s: %s; got: %s\n", r.key, k, v, got)
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// TestConcurrentMutations runs writers inserting, upserting and deleting
+// their own routes, while the readers look up both the stable and the
+// volatile routes. Every writer owns its patterns, so it knows whether
+// each of its mutations must succeed, and the final state of the tree.
+// The walks run over snapshots, so they are either complete, or stopped
+// by a mutation, but they never see a torn tree.
+func TestConcurrentMutations(t *testing.T) {
+	var (
+		schedule = concurrencySchedule(t)
+		tree     = newConcurrencyTree(t, WithReadLocking[*Route]())
+
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+
+		present = make([][]bool, concurrencyWriters)
+	)
+
+	for w := 0; w < concurrencyWriters; w++ {
+		wg.Add(1)
+
+		go func(w int) {
+			defer wg.Done()
+
+			var (
+				r     = schedule(w)
+				owned = make([]bool, concurrencyPatterns)
+			)
+
+			defer func() { present[w] = owned }()
+
+			for round := 0; round < concurrencyRounds; round++ {
+				var (
+					i       = r.Intn(concurrencyPatterns)
+					pattern = volatilePattern(w, i)
+				)
+
+				switch op := r.Intn(3); {
+				case op == 0:
+					err := tree.Insert(pattern, &Route{name: pattern})
+
+					if (err == nil) == owned[i] {
+						t.Errorf("%s: unexpected result of insert: %v\n", pattern, err)
+						return
+					}
+
+					owned[i] = true
+				case op == 1:
+					if err := tree.Upsert(pattern, &Route{name: pattern}); err != nil {
+						t.Errorf("%s: not expected error, but got: %v\n", pattern, err)
+						return
+					}
+
+					owned[i] = true
+				default:
+					err := tree.Delete(pattern)
+
+					if (err == nil) != owned[i] {
+						t.Errorf("%s: unexpected result of delete: %v\n", pattern, err)
+						return
+					}
+
+					owned[i] = false
+				}
+
+				maybeYield(r)
+			}
+		}(w)
+	}
+
+	var readers sync.WaitGroup
+
+	for rd := 0; rd < concurrencyReaders; rd++ {
+		readers.Add(1)
+
+		go func(rd int) {
+			defer readers.Done()
+
+			r := schedule(concurrencyWriters + rd)
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if !checkStableRoutes(t, tree) {
+					return
+				}
+
+				var (
+					w   = r.Intn(concurrencyWriters)
+					i   = r.Intn(concurrencyPatterns)
+					key = volatileKey(w, i)
+				)
+
+				// A volatile route may or may not be there, but
+				// the key must never match an other route.
+				if fn := tree.Find(key); fn != nil && fn.GetPattern() != volatilePattern(w, i) {
+					t.Errorf("%s: expected pattern: %s; got: %s\n", key, volatilePattern(w, i), fn.GetPattern())
+					return
+				}
+
+				if fn := tree.FindLongestMatch(key); fn == nil {
+					t.Errorf("%s: expected longest match; got <nil>\n", key)
+					return
+				}
+
+				if st := tree.Stats(); st.Routes < len(stableConcurrencyRoutes) {
+					t.Errorf("expected at least %d routes; got: %d\n", len(stableConcurrencyRoutes), st.Routes)
+					return
+				}
+
+				leaves := 0
+
+				err := tree.Walk(func(n *Node[*Route]) WalkVerdict {
+					if n.IsLeaf() {
+						leaves++
+					}
+
+					return Continue
+				})
+
+				if err == nil && leaves < len(stableConcurrencyRoutes) {
+					t.Errorf("expected at least %d leaves; got: %d\n", len(stableConcurrencyRoutes), leaves)
+					return
+				}
+
+				if err != nil && !errors.Is(err, ErrConcurrentModification) {
+					t.Errorf("not expected error, but got: %v\n", err)
+					return
+				}
+
+				maybeYield(r)
+			}
+		}(rd)
+	}
+
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	if t.Failed() {
+		return
+	}
+
+	checkStableRoutes(t, tree)
+
+	for w, owned := range present {
+		for i, exists := range owned {
+			fn := tree.Find(volatileKey(w, i))
+
+			if (fn != nil) != exists {
+				t.Errorf("%s: expected to exist: %t; got: %v\n", volatilePattern(w, i), exists, fn)
+			}
+		}
+	}
+}
+
+// TestConcurrentReaders runs all kinds of readers on the same tree – without
+// any mutation, so they do not need the read locking of the lookups.
+func TestConcurrentReaders(t *testing.T) {
+	var (
+		schedule = concurrencySchedule(t)
+		tree     = newConcurrencyTree(t)
+
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < concurrencyPatterns; i++ {
+		if err := tree.Insert(volatilePattern(0, i), &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	routes := len(stableConcurrencyRoutes) + concurrencyPatterns
+
+	for rd := 0; rd < concurrencyReaders; rd++ {
+		wg.Add(1)
+
+		go func(rd int) {
+			defer wg.Done()
+
+			r := schedule(rd)
+
+			for round := 0; round < concurrencyRounds; round++ {
+				switch r.Intn(4) {
+				case 0:
+					if !checkStableRoutes(t, tree) {
+						return
+					}
+				case 1:
+					leaves := 0
+
+					err := tree.Walk(func(n *Node[*Route]) WalkVerdict {
+						if n.IsLeaf() {
+							leaves++
+						}
+
+						return Continue
+					})
+
+					if err != nil || leaves != routes {
+						t.Errorf("expected %d leaves; got: %d (%v)\n", routes, leaves, err)
+						return
+					}
+				case 2:
+					i := r.Intn(concurrencyPatterns)
+
+					if fn := tree.Find(volatileKey(0, i)); fn == nil || fn.GetPattern() != volatilePattern(0, i) {
+						t.Errorf("%s: expected pattern: %s; got: %v\n", volatileKey(0, i), volatilePattern(0, i), fn)
+						return
+					}
+				default:
+					snap, err := tree.Snapshot(JSONCodec[*Route]{})
+
+					if err != nil || len(snap.Entries) != routes {
+						t.Errorf("expected %d entries; got: %d (%v)\n", routes, len(snap.Entries), err)
+						return
+					}
+				}
+
+				maybeYield(r)
+			}
+		}(rd)
+	}
+
+	wg.Wait()
+}
+
+// TestConcurrentLazyRoot checks, that the root of a zero value tree
+// created by the first insertion is never read without the lock.
+func TestConcurrentLazyRoot(t *testing.T) {
+	var (
+		tree Tree[*Route]
+		wg   sync.WaitGroup
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			if err := tree.Insert(volatilePattern(i, 0), &Route{}); err != nil {
+				t.Errorf("not expected error, but got: %v\n", err)
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			err := tree.Walk(func(*Node[*Route]) WalkVerdict { return Continue })
+			if err != nil && !errors.Is(err, ErrConcurrentModification) {
+				t.Errorf("not expected error, but got: %v\n", err)
+			}
+
+			_ = tree.GetAllLeaf()
+			_ = tree.Delete("/not/stored")
+		}()
+	}
+
+	wg.Wait()
+
+	if leaves := tree.GetAllLeaf(); len(leaves) != 4 {
+		t.Errorf("expected 4 leaves; got: %d\n", len(leaves))
+	}
+}
@@ -0,0 +1,131 @@
+package rtree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultConsolidationMin is the least number of the near-identical
+// routes worth reporting, if the caller gives a smaller one.
+const defaultConsolidationMin = 2
+
+// consolidationParam is the name of the param of the suggested patterns.
+const consolidationParam = "id"
+
+// ConsolidationReport describes a group of static routes, that differ in
+// a single segment only, eg. /tenants/acme/config and /tenants/globex/config,
+// so they could be replaced by the single route of Pattern.
+type ConsolidationReport struct {
+	// Pattern is the suggested route with a param in place of the segment.
+	Pattern string `json:"pattern"`
+	// Routes are the stored patterns, that the suggested one would replace.
+	Routes []string `json:"routes"`
+	// Existing is the stored pattern – if any –, that already matches
+	// every key of the suggested one, eg. /tenants/{tenant}/config. The
+	// routes of the group are only overriding it then.
+	Existing string `json:"existing,omitempty"`
+}
+
+// FindConsolidations reports the groups of at least minRoutes stored
+// routes, that only differ in a single static segment – typically the
+// tables generated by configuration tooling –, with the wildcard route,
+// that could replace each group. Only the segment differs, the params and
+// the rest of the segments are the same in the whole group. A route could
+// be the part of more groups, if it differs from others in more positions.
+// The reports are sorted by the number of their routes – the largest
+// first –, then by their patterns. Whether the values of the routes could
+// be merged, is up to the caller.
+func (t *Tree[T]) FindConsolidations(minRoutes int) []ConsolidationReport {
+	reports := make([]ConsolidationReport, 0)
+
+	if checkTree(t) != nil {
+		return reports
+	}
+
+	if minRoutes < defaultConsolidationMin {
+		minRoutes = defaultConsolidationMin
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var (
+		leaves = getAllLeafRec(t.root)
+		groups = make(map[string][]string)
+	)
+
+	for _, l := range leaves {
+		segments := strings.Split(l.value.pattern, string(slash))
+
+		for i, seg := range segments {
+			if seg == "" || strings.IndexByte(seg, curlyStart) != -1 {
+				continue
+			}
+
+			key := consolidationKey(segments, i)
+
+			groups[key] = append(groups[key], l.value.Pattern())
+		}
+	}
+
+	for key, routes := range groups {
+		if len(routes) < minRoutes {
+			continue
+		}
+
+		sort.Strings(routes)
+
+		pattern := consolidatedPattern(key)
+
+		report := ConsolidationReport{
+			Pattern: t.externalPattern(pattern),
+			Routes:  routes,
+		}
+
+		for _, l := range leaves {
+			if len(l.value.params) > 0 && patternCovers(l.value.pattern, pattern) {
+				report.Existing = l.value.Pattern()
+				break
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if len(reports[i].Routes) != len(reports[j].Routes) {
+			return len(reports[i].Routes) > len(reports[j].Routes)
+		}
+
+		return reports[i].Pattern < reports[j].Pattern
+	})
+
+	return reports
+}
+
+// consolidationKey returns the segments joined, with the one at the
+// given position replaced by the shadow sample, which could not be
+// the part of any static segment.
+func consolidationKey(segments []string, i int) string {
+	parts := make([]string, len(segments))
+	copy(parts, segments)
+
+	parts[i] = shadowSample
+
+	return strings.Join(parts, string(slash))
+}
+
+// consolidatedPattern replaces the sample of the key with a param,
+// whose name does not collide with the other params of the pattern.
+func consolidatedPattern(key string) string {
+	name := consolidationParam
+
+	for i := 2; strings.Contains(key, string(curlyStart)+name+string(curlyEnd)) ||
+		strings.Contains(key, string(curlyStart)+name+string(matcherSeparator)) ||
+		strings.Contains(key, string(curlyStart)+name+catchAllSuffix); i++ {
+		name = fmt.Sprintf("%s%d", consolidationParam, i)
+	}
+
+	return strings.Replace(key, shadowSample, string(curlyStart)+name+string(curlyEnd), 1)
+}
@@ -0,0 +1,89 @@
+package rtree
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFindConsolidations(t *testing.T) {
+	tree := New[*Route]()
+
+	patterns := []string{
+		"/tenants/{tenant}/users",
+		"/api/v1/orders/{id}/items",
+		"/api/v2/orders/{id}/items",
+		"/health",
+	}
+
+	for i := 0; i < 5; i++ {
+		patterns = append(patterns, fmt.Sprintf("/tenants/t%d/config", i))
+	}
+
+	for _, p := range patterns {
+		if err := tree.Insert(p, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []struct {
+		name      string
+		minRoutes int
+		expected  []ConsolidationReport
+	}{
+		{
+			name:      "default minimum",
+			minRoutes: 0,
+			expected: []ConsolidationReport{
+				{
+					Pattern: "/tenants/{id}/config",
+					Routes:  []string{"/tenants/t0/config", "/tenants/t1/config", "/tenants/t2/config", "/tenants/t3/config", "/tenants/t4/config"},
+				},
+				{
+					Pattern: "/api/{id2}/orders/{id}/items",
+					Routes:  []string{"/api/v1/orders/{id}/items", "/api/v2/orders/{id}/items"},
+				},
+			},
+		},
+		{
+			name:      "larger minimum",
+			minRoutes: 3,
+			expected: []ConsolidationReport{
+				{
+					Pattern: "/tenants/{id}/config",
+					Routes:  []string{"/tenants/t0/config", "/tenants/t1/config", "/tenants/t2/config", "/tenants/t3/config", "/tenants/t4/config"},
+				},
+			},
+		},
+		{
+			name:      "too large minimum",
+			minRoutes: 6,
+			expected:  []ConsolidationReport{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tree.FindConsolidations(tc.minRoutes); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected reports: %+v; got: %+v\n", tc.expected, got)
+			}
+		})
+	}
+
+	// The existing wildcard route is reported, that the group only overrides.
+	if err := tree.Insert("/tenants/{tenant}/config", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	got := tree.FindConsolidations(3)
+
+	if len(got) != 1 || got[0].Existing != "/tenants/{tenant}/config" {
+		t.Errorf("expected the existing route: /tenants/{tenant}/config; got: %+v\n", got)
+	}
+
+	var nilTree *Tree[*Route]
+
+	if got := nilTree.FindConsolidations(2); len(got) != 0 {
+		t.Errorf("expected no reports of a nil tree; got: %+v\n", got)
+	}
+}
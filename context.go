@@ -0,0 +1,21 @@
+package rtree
+
+import "context"
+
+// paramsKey is the context key of the matched params.
+type paramsKey struct{}
+
+// NewContext returns a copy of ctx carrying the matched params, eg. the
+// GetParams of a FoundNode, so the handlers and the middleware down the
+// call chain could read them by ParamsFromContext.
+func NewContext(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
+
+// ParamsFromContext returns the matched params carried by ctx,
+// and whether there were any params set by NewContext.
+func ParamsFromContext(ctx context.Context) (map[string]string, bool) {
+	params, ok := ctx.Value(paramsKey{}).(map[string]string)
+
+	return params, ok
+}
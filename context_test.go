@@ -0,0 +1,35 @@
+package rtree
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParamsContext(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/users/{id}/posts/{post}", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	fn := tree.Find("/api/users/5/posts/7")
+	if fn == nil {
+		t.Fatal("expected match; got nil")
+	}
+
+	ctx := NewContext(context.Background(), fn.GetParams())
+
+	params, ok := ParamsFromContext(ctx)
+	if !ok {
+		t.Fatal("expected params in the context")
+	}
+
+	if expected := map[string]string{"id": "5", "post": "7"}; !reflect.DeepEqual(expected, params) {
+		t.Errorf("expected params: %v; got: %v\n", expected, params)
+	}
+
+	if params, ok := ParamsFromContext(context.Background()); ok || params != nil {
+		t.Errorf("expected no params; got: %v\n", params)
+	}
+}
@@ -0,0 +1,104 @@
+package rtree
+
+import (
+	"sort"
+	"sync"
+)
+
+// Coverage wraps a tree, and records which routes are matched through
+// it, eg. by the test suite of an API, so the routes without any test
+// could be reported. It is safe for concurrent use.
+type Coverage[T storeValue] struct {
+	tree *Tree[T]
+
+	mu  sync.Mutex
+	hit map[string]int
+}
+
+// CoverageReport is the result of the coverage tracking.
+type CoverageReport struct {
+	// Total is the number of the stored routes.
+	Total int
+	// Hits is the number of matches by the stored patterns.
+	Hits map[string]int
+	// Unhit holds the sorted patterns of the routes never matched.
+	Unhit []string
+}
+
+// Covered returns the ratio of the matched routes, or 1 without routes.
+func (r CoverageReport) Covered() float64 {
+	if r.Total == 0 {
+		return 1
+	}
+
+	return float64(r.Total-len(r.Unhit)) / float64(r.Total)
+}
+
+// NewCoverage starts the coverage tracking of the given tree.
+func NewCoverage[T storeValue](t *Tree[T]) *Coverage[T] {
+	return &Coverage[T]{
+		tree: t,
+		hit:  make(map[string]int),
+	}
+}
+
+// Tree returns the tracked tree.
+func (c *Coverage[T]) Tree() *Tree[T] {
+	return c.tree
+}
+
+// Find is the same as the Find of the tree, but it records the match.
+func (c *Coverage[T]) Find(key string) *FoundNode[T] {
+	return c.Record(c.tree.Find(key))
+}
+
+// FindLongestMatch is the same as the FindLongestMatch
+// of the tree, but it records the match.
+func (c *Coverage[T]) FindLongestMatch(key string) *FoundNode[T] {
+	return c.Record(c.tree.FindLongestMatch(key))
+}
+
+// Record records the result of a lookup done on the tree directly,
+// eg. by FindWithOrder. It returns the given result, and it is a no-op
+// without match.
+func (c *Coverage[T]) Record(fn *FoundNode[T]) *FoundNode[T] {
+	if fn == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hit[fn.pattern]++
+
+	return fn
+}
+
+// Report returns the coverage of the routes stored at the time of the
+// call. The matches of the removed routes are not reported.
+func (c *Coverage[T]) Report() CoverageReport {
+	report := CoverageReport{
+		Hits:  make(map[string]int),
+		Unhit: make([]string, 0),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, l := range c.tree.GetAllLeaf() {
+		report.Total++
+
+		pattern := l.value.Pattern()
+
+		if hits := c.hit[pattern]; hits > 0 {
+			report.Hits[pattern] = hits
+			continue
+		}
+
+		report.Unhit = append(report.Unhit, pattern)
+	}
+
+	sort.Strings(report.Unhit)
+
+	return report
+}
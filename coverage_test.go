@@ -0,0 +1,69 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	tree := New[*Route]()
+
+	for _, key := range []string{"/users", "/users/{id}", "/groups", "/groups/{id}"} {
+		if err := tree.Insert(key, &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	cov := NewCoverage(tree)
+
+	if report := cov.Report(); report.Covered() != 0 || len(report.Unhit) != 4 {
+		t.Fatalf("expected no coverage; got: %+v\n", report)
+	}
+
+	cov.Find("/users/1")
+	cov.Find("/users/2")
+	cov.Find("/missing")
+	cov.FindLongestMatch("/groups/1")
+	cov.Record(tree.FindWithOrder("/users", CatchAllFirst))
+
+	report := cov.Report()
+
+	expectedHits := map[string]int{
+		"/users/{id}": 2,
+		"/users":      1,
+		"/groups":     1,
+	}
+
+	if !reflect.DeepEqual(report.Hits, expectedHits) {
+		t.Errorf("expected hits: %v; got: %v\n", expectedHits, report.Hits)
+	}
+
+	if !reflect.DeepEqual(report.Unhit, []string{"/groups/{id}"}) {
+		t.Errorf("expected unhit: [/groups/{id}]; got: %v\n", report.Unhit)
+	}
+
+	if report.Covered() != 0.75 {
+		t.Errorf("expected coverage: 0.75; got: %v\n", report.Covered())
+	}
+
+	// The matches of the removed routes are not reported.
+	if err := tree.Delete("/users"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if report := cov.Report(); report.Total != 3 || report.Hits["/users"] != 0 {
+		t.Errorf("expected the removed route to be gone; got: %+v\n", report)
+	}
+}
+
+func TestCoverageEmptyTree(t *testing.T) {
+	cov := NewCoverage(New[*Route]())
+
+	if report := cov.Report(); report.Total != 0 || report.Covered() != 1 {
+		t.Fatalf("expected full coverage of an empty tree; got: %+v\n", report)
+	}
+
+	if fn := cov.Find("/a"); fn != nil {
+		t.Fatalf("expected no match; got: %v\n", fn)
+	}
+}
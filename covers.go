@@ -0,0 +1,73 @@
+package rtree
+
+import "sort"
+
+// Covers reports the patterns of tree b, whose keys would not all be
+// matched by tree a – eg. the routes that would be dropped by replacing
+// the configuration b with a. A static pattern is covered, if a matches
+// it. A pattern with params is covered, if a has a pattern at least as
+// general as it, eg. /users/{id} is covered by /users/{id} or by
+// /{path...}, but not by the pair of /users/{id:int} and /users/me.
+// The segment matchers are compared by their names, and the param
+// policies and route constraints of a are not taken into account.
+// The reported patterns are sorted.
+func Covers[T storeValue](a, b *Tree[T]) []string {
+	uncovered := make([]string, 0)
+
+	if checkTree(b) != nil {
+		return uncovered
+	}
+
+	b.mu.RLock()
+	leaves := getAllLeafRec(b.root)
+	b.mu.RUnlock()
+
+	if checkTree(a) != nil {
+		for _, l := range leaves {
+			uncovered = append(uncovered, l.value.Pattern())
+		}
+
+		sort.Strings(uncovered)
+
+		return uncovered
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	general := getAllLeafRec(a.root)
+
+	for _, l := range leaves {
+		if !a.covers(general, l.value) {
+			uncovered = append(uncovered, l.value.Pattern())
+		}
+	}
+
+	sort.Strings(uncovered)
+
+	return uncovered
+}
+
+// covers reports whether every key of the given route is matched by one
+// of the leaves of the tree. The caller must hold the read lock.
+func (t *Tree[T]) covers(leaves []*Node[T], nv *NodeValue[T]) bool {
+	if len(nv.params) == 0 {
+		n, _ := t.findNode(unescape(nv.pattern))
+
+		return n != nil
+	}
+
+	pattern := nv.pattern
+
+	if t.caseInsensitive {
+		pattern = foldPattern(pattern)
+	}
+
+	for _, l := range leaves {
+		if patternCovers(l.value.pattern, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCovers(t *testing.T) {
+	type testCase struct {
+		name     string
+		a        []string
+		b        []string
+		expected []string
+	}
+
+	tt := []testCase{
+		{
+			name:     "identical trees",
+			a:        []string{"/users", "/users/{id}"},
+			b:        []string{"/users", "/users/{id}"},
+			expected: []string{},
+		},
+		{
+			name:     "dropped static route",
+			a:        []string{"/users"},
+			b:        []string{"/users", "/groups"},
+			expected: []string{"/groups"},
+		},
+		{
+			name:     "static route matched by a param",
+			a:        []string{"/users/{id}"},
+			b:        []string{"/users/me"},
+			expected: []string{},
+		},
+		{
+			name:     "param route narrowed to a static one",
+			a:        []string{"/users/me"},
+			b:        []string{"/users/{id}"},
+			expected: []string{"/users/{id}"},
+		},
+		{
+			name:     "renamed param",
+			a:        []string{"/users/{userID}"},
+			b:        []string{"/users/{id}"},
+			expected: []string{},
+		},
+		{
+			name:     "matcher added",
+			a:        []string{"/users/{id:int}"},
+			b:        []string{"/users/{id}"},
+			expected: []string{"/users/{id}"},
+		},
+		{
+			name:     "matcher removed",
+			a:        []string{"/users/{id}"},
+			b:        []string{"/users/{id:int}"},
+			expected: []string{},
+		},
+		{
+			name:     "catch-all covers everything below",
+			a:        []string{"/{path...}"},
+			b:        []string{"/users", "/users/{id}/posts/{post}", "/files/{path...}"},
+			expected: []string{},
+		},
+		{
+			name:     "catch-all is not covered by a single param",
+			a:        []string{"/files/{name}"},
+			b:        []string{"/files/{path...}"},
+			expected: []string{"/files/{path...}"},
+		},
+		{
+			name:     "escaped static route",
+			a:        []string{"/legacy/\\{id\\}"},
+			b:        []string{"/legacy/\\{id\\}", "/legacy/{id}"},
+			expected: []string{"/legacy/{id}"},
+		},
+	}
+
+	matcher := WithSegmentMatcher[*Route]("int", Int)
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := New(matcher), New(matcher)
+
+			for _, key := range tc.a {
+				if err := a.Insert(key, &Route{}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			for _, key := range tc.b {
+				if err := b.Insert(key, &Route{}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			if got := Covers(a, b); !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected: %v; got: %v\n", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCoversEmptyTrees(t *testing.T) {
+	b := New[*Route]()
+
+	if err := b.Insert("/users/{id}", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := Covers(New[*Route](), b); !reflect.DeepEqual(got, []string{"/users/{id}"}) {
+		t.Fatalf("expected every route of b; got: %v\n", got)
+	}
+
+	if got := Covers(b, New[*Route]()); len(got) != 0 {
+		t.Fatalf("expected no routes; got: %v\n", got)
+	}
+
+	if got := Covers(nil, b); len(got) != 1 {
+		t.Fatalf("expected every route of b; got: %v\n", got)
+	}
+}
@@ -0,0 +1,421 @@
+package rtree
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errBadCSV = fmt.Errorf("[rtree %s]: bad csv", version)
+
+// The columns of the route metadata in the CSV files. Every other
+// column is the label of the route with the name of the column.
+const (
+	CSVPattern     = "pattern"
+	CSVDescription = "description"
+	CSVSource      = "source"
+	CSVProtected   = "protected"
+	CSVRedirect    = "redirect"
+	CSVCode        = "code"
+	CSVTimeout     = "timeout"
+	CSVSunset      = "sunset"
+	CSVDisabled    = "disabled"
+	// CSVWindow is the active window of the route – see InsertWindowed –,
+	// as its RFC 3339 bounds separated by a slash, eg.
+	// 2024-01-01T00:00:00Z/2024-02-01T00:00:00Z. A bound could
+	// be empty, which means no bound on that side.
+	CSVWindow = "window"
+)
+
+// csvColumns are the metadata columns exported by default.
+var csvColumns = []string{CSVPattern, CSVDescription, CSVSource, CSVProtected, CSVRedirect, CSVCode, CSVTimeout, CSVSunset, CSVDisabled, CSVWindow}
+
+// CSVRecord is a row of a CSV file, by the names of its columns.
+type CSVRecord map[string]string
+
+// ExportCSV writes the routes of the tree to w as CSV – with a header
+// row of the column names –, so large route and redirect tables could be
+// edited in spreadsheets, and loaded back by ImportCSV. The routes are
+// written in the order of their patterns. The given columns are either
+// the metadata columns – see CSVPattern and the others – or the labels
+// of the routes. Without columns, all the metadata columns are written,
+// followed by every label of the routes in sorted order.
+//
+// The values are not written, they are expected to be built from the
+// columns on import. The aliases are not written either.
+func (t *Tree[T]) ExportCSV(w io.Writer, columns ...string) error {
+	if t == nil {
+		return ErrTreeIsNil
+	}
+
+	t.mu.RLock()
+
+	var (
+		leaves = getAllLeafRec(t.root)
+		routes = make([]*NodeValue[T], 0, len(leaves))
+	)
+
+	for _, l := range leaves {
+		if l.value.aliasOf == 0 {
+			routes = append(routes, l.value)
+		}
+	}
+
+	t.mu.RUnlock()
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Pattern() < routes[j].Pattern()
+	})
+
+	if len(columns) == 0 {
+		columns = append(append([]string(nil), csvColumns...), csvLabels(routes)...)
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+
+	for _, nv := range routes {
+		for i, col := range columns {
+			row[i] = csvCell(nv, col)
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// csvLabels returns the sorted names of the labels of the routes,
+// that are not the names of metadata columns.
+func csvLabels[T storeValue](routes []*NodeValue[T]) []string {
+	seen := make(map[string]struct{})
+
+	for _, col := range csvColumns {
+		seen[col] = struct{}{}
+	}
+
+	labels := make([]string, 0)
+
+	for _, nv := range routes {
+		for k := range nv.meta.labels {
+			if _, exists := seen[k]; !exists {
+				seen[k] = struct{}{}
+				labels = append(labels, k)
+			}
+		}
+	}
+
+	sort.Strings(labels)
+
+	return labels
+}
+
+// csvCell returns the value of the given column of the route.
+func csvCell[T storeValue](nv *NodeValue[T], column string) string {
+	meta := &nv.meta
+
+	switch column {
+	case CSVPattern:
+		return nv.Pattern()
+	case CSVDescription:
+		return meta.description
+	case CSVSource:
+		return meta.source
+	case CSVProtected:
+		if meta.protected {
+			return strconv.FormatBool(true)
+		}
+	case CSVRedirect:
+		if meta.redirect != nil {
+			return meta.redirect.target
+		}
+	case CSVCode:
+		if meta.redirect != nil {
+			return strconv.Itoa(meta.redirect.code)
+		}
+	case CSVTimeout:
+		if meta.timeout > 0 {
+			return meta.timeout.String()
+		}
+	case CSVSunset:
+		if !meta.sunset.IsZero() {
+			return meta.sunset.Format(time.RFC3339)
+		}
+	case CSVDisabled:
+		if meta.disabled {
+			return strconv.FormatBool(true)
+		}
+	case CSVWindow:
+		if meta.window != nil {
+			return formatCSVWindow(meta.window)
+		}
+	default:
+		return meta.labels[column]
+	}
+
+	return ""
+}
+
+// ImportCSV inserts the routes of the CSV read from r – in the format
+// written by ExportCSV –, with the values built by the factory from
+// their rows. The first row must name the columns, and the pattern
+// column is mandatory. The rows with a redirect target are inserted as
+// redirects – with 301 Moved Permanently, if the code is empty –, and
+// their values are not built. Like InsertAll, it goes on after the
+// failed rows, and it skips the repeated patterns; the report has an
+// entry of every row. The error is only returned, if the CSV itself
+// could not be read.
+func (t *Tree[T]) ImportCSV(r io.Reader, factory func(CSVRecord) (T, error)) (InsertReport, error) {
+	if t == nil {
+		return nil, ErrTreeIsNil
+	}
+
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", errBadCSV, err)
+	}
+
+	hasPattern := false
+
+	for _, col := range header {
+		hasPattern = hasPattern || col == CSVPattern
+	}
+
+	if !hasPattern {
+		return nil, fmt.Errorf("%w: missing column: %s", errBadCSV, CSVPattern)
+	}
+
+	var (
+		report = make(InsertReport, 0)
+		seen   = make(map[string]struct{})
+	)
+
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+
+		if err != nil {
+			return report, fmt.Errorf("%w: %v", errBadCSV, err)
+		}
+
+		rec := make(CSVRecord, len(header))
+
+		for i, col := range header {
+			rec[col] = row[i]
+		}
+
+		res := InsertResult{
+			Key: rec[CSVPattern],
+		}
+
+		if _, exists := seen[res.Key]; exists {
+			res.Status = StatusSkippedDuplicate
+			report = append(report, res)
+			continue
+		}
+
+		seen[res.Key] = struct{}{}
+
+		if res.Err = t.insertCSVRecord(rec, factory); res.Err != nil {
+			res.Err = fmt.Errorf("line %d: %w", line, res.Err)
+		}
+
+		res.Status = insertStatusOf(res.Err)
+
+		report = append(report, res)
+	}
+}
+
+// insertCSVRecord inserts the route of a single row of a CSV file.
+func (t *Tree[T]) insertCSVRecord(rec CSVRecord, factory func(CSVRecord) (T, error)) error {
+	opts, err := csvRouteOptions(rec)
+	if err != nil {
+		return err
+	}
+
+	// The caches are bypassed before the route could be found at all, see InsertWindowed.
+	if rec[CSVWindow] != "" {
+		t.windowed.Store(true)
+	}
+
+	if target := rec[CSVRedirect]; target != "" {
+		code := http.StatusMovedPermanently
+
+		if rec[CSVCode] != "" {
+			if code, err = strconv.Atoi(rec[CSVCode]); err != nil {
+				return fmt.Errorf("%w: %s: %v", errBadCSV, CSVCode, err)
+			}
+		}
+
+		return t.InsertRedirect(rec[CSVPattern], target, code, opts...)
+	}
+
+	value, err := factory(rec)
+	if err != nil {
+		return err
+	}
+
+	return t.Insert(rec[CSVPattern], value, opts...)
+}
+
+// csvRouteOptions returns the options of the metadata columns of the row.
+func csvRouteOptions(rec CSVRecord) ([]RouteOption, error) {
+	var (
+		opts   []RouteOption
+		labels = make(map[string]string)
+	)
+
+	for col, cell := range rec {
+		if cell == "" {
+			continue
+		}
+
+		switch col {
+		case CSVPattern, CSVRedirect, CSVCode:
+		case CSVDescription:
+			opts = append(opts, WithDescription(cell))
+		case CSVSource:
+			opts = append(opts, WithSource(cell))
+		case CSVProtected:
+			protected, err := strconv.ParseBool(cell)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errBadCSV, col, err)
+			}
+
+			if protected {
+				opts = append(opts, withProtected())
+			}
+		case CSVTimeout:
+			d, err := time.ParseDuration(cell)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errBadCSV, col, err)
+			}
+
+			opts = append(opts, WithTimeout(d))
+		case CSVSunset:
+			at, err := time.Parse(time.RFC3339, cell)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errBadCSV, col, err)
+			}
+
+			opts = append(opts, WithSunset(at))
+		case CSVDisabled:
+			disabled, err := strconv.ParseBool(cell)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errBadCSV, col, err)
+			}
+
+			if disabled {
+				opts = append(opts, withDisabled())
+			}
+		case CSVWindow:
+			w, err := parseCSVWindow(cell)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errBadCSV, col, err)
+			}
+
+			opts = append(opts, withWindow(w))
+		default:
+			labels[col] = cell
+		}
+	}
+
+	if len(labels) > 0 {
+		opts = append(opts, withLabels(labels))
+	}
+
+	return opts, nil
+}
+
+// withProtected makes the route protected, the same as InsertProtected.
+func withProtected() RouteOption {
+	return func(rm *routeMeta) {
+		rm.protected = true
+	}
+}
+
+// withDisabled makes the route disabled, the same as Disable.
+func withDisabled() RouteOption {
+	return func(rm *routeMeta) {
+		rm.disabled = true
+	}
+}
+
+// withWindow sets the active window of the route, the same as InsertWindowed.
+func withWindow(w *activeWindow) RouteOption {
+	return func(rm *routeMeta) {
+		rm.window = w
+	}
+}
+
+// formatCSVWindow formats the window as the cell of CSVWindow.
+func formatCSVWindow(w *activeWindow) string {
+	var from, to string
+
+	if !w.from.IsZero() {
+		from = w.from.Format(time.RFC3339)
+	}
+
+	if !w.to.IsZero() {
+		to = w.to.Format(time.RFC3339)
+	}
+
+	return from + "/" + to
+}
+
+// parseCSVWindow parses the cell of CSVWindow.
+func parseCSVWindow(cell string) (*activeWindow, error) {
+	fromCell, toCell, ok := strings.Cut(cell, "/")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing „/” in %q", errBadWindow, cell)
+	}
+
+	var (
+		w   activeWindow
+		err error
+	)
+
+	if fromCell != "" {
+		if w.from, err = time.Parse(time.RFC3339, fromCell); err != nil {
+			return nil, err
+		}
+	}
+
+	if toCell != "" {
+		if w.to, err = time.Parse(time.RFC3339, toCell); err != nil {
+			return nil, err
+		}
+	}
+
+	if !w.from.IsZero() && !w.to.IsZero() && !w.to.After(w.from) {
+		return nil, fmt.Errorf("%w: %s is not after %s", errBadWindow, toCell, fromCell)
+	}
+
+	return &w, nil
+}
+
+// withLabels sets the labels of the route, the same as RouteBuilder.Meta.
+func withLabels(labels map[string]string) RouteOption {
+	return func(rm *routeMeta) {
+		rm.labels = labels
+	}
+}
@@ -0,0 +1,200 @@
+package rtree
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportCSV(t *testing.T) {
+	tree := New[*Route]()
+
+	spec, err := NewRoute("/api/users/{id}").Meta("owner", "team-a").Build()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertRoute(spec, &Route{name: "users"}, WithDescription("Get a user, by id"), WithTimeout(2*time.Second)); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertProtected("/health", &Route{name: "health"}, WithSource("core")); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertRedirect("/old/{id}", "/api/users/{id}", 308, WithSunset(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/beta", &Route{name: "beta"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Disable("/beta"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertWindowed("/campaign", &Route{name: "campaign"}, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tt := []struct {
+		name     string
+		columns  []string
+		expected string
+	}{
+		{
+			name: "all columns",
+			expected: "pattern,description,source,protected,redirect,code,timeout,sunset,disabled,window,owner\n" +
+				"/api/users/{id},\"Get a user, by id\",,,,,2s,,,,team-a\n" +
+				"/beta,,,,,,,,true,,\n" +
+				"/campaign,,,,,,,,,2030-01-01T00:00:00Z/,\n" +
+				"/health,,core,true,,,,,,,\n" +
+				"/old/{id},,,,/api/users/{id},308,,2030-01-02T03:04:05Z,,,\n",
+		},
+		{
+			name:    "given columns",
+			columns: []string{CSVPattern, "owner", CSVRedirect},
+			expected: "pattern,owner,redirect\n" +
+				"/api/users/{id},team-a,\n" +
+				"/beta,,\n" +
+				"/campaign,,\n" +
+				"/health,,\n" +
+				"/old/{id},,/api/users/{id}\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if err := tree.ExportCSV(&buf, tc.columns...); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s\n", tc.expected, buf.String())
+			}
+		})
+	}
+
+	// The exported table is loaded back the same.
+	factory := func(rec CSVRecord) (*Route, error) {
+		return &Route{name: rec["owner"]}, nil
+	}
+
+	var exported bytes.Buffer
+
+	if err := tree.ExportCSV(&exported); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	imported := New[*Route]()
+
+	report, err := imported.ImportCSV(strings.NewReader(exported.String()), factory)
+	if err != nil || !report.OK() {
+		t.Fatalf("not expected error, but got: %v %v\n", err, report.Failed())
+	}
+
+	var reexported bytes.Buffer
+
+	if err := imported.ExportCSV(&reexported); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if reexported.String() != exported.String() {
+		t.Errorf("expected round trip:\n%s\ngot:\n%s\n", exported.String(), reexported.String())
+	}
+
+	if fn := imported.Find("/api/users/5"); fn == nil || fn.GetValue().name != "team-a" || fn.Meta("owner") != "team-a" {
+		t.Errorf("expected the value built by the factory\n")
+	}
+
+	if fn := imported.Find("/old/5"); fn == nil || !fn.IsRedirect() {
+		t.Errorf("expected the redirect to be imported\n")
+	}
+
+	if !imported.IsDisabled("/beta") {
+		t.Errorf("expected the disabled route to be imported\n")
+	}
+
+	if fn := imported.Find("/campaign"); fn != nil {
+		t.Errorf("expected the windowed route to be inactive before its window\n")
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	errFactory := errors.New("no backend")
+
+	factory := func(rec CSVRecord) (*Route, error) {
+		if rec["backend"] == "" {
+			return nil, errFactory
+		}
+
+		return &Route{name: rec["backend"]}, nil
+	}
+
+	t.Run("bad header", func(t *testing.T) {
+		if _, err := New[*Route]().ImportCSV(strings.NewReader("path,backend\n/a,b\n"), factory); !errors.Is(err, errBadCSV) {
+			t.Errorf("expected error: %v; got: %v\n", errBadCSV, err)
+		}
+	})
+
+	t.Run("nil tree", func(t *testing.T) {
+		var tree *Tree[*Route]
+
+		if _, err := tree.ImportCSV(strings.NewReader("pattern\n"), factory); !errors.Is(err, ErrTreeIsNil) {
+			t.Errorf("expected error: %v; got: %v\n", ErrTreeIsNil, err)
+		}
+	})
+
+	input := "pattern,backend,timeout,redirect,code,window\n" +
+		"/users,svc-users,,,,\n" +
+		"/users,svc-other,,,,\n" +
+		"/orders,,,,,\n" +
+		"/slow,svc-slow,soon,,,\n" +
+		"/old,,,/users,,\n" +
+		"/bad/{,svc,,,,\n" +
+		"/late,svc-late,,,,2030-01-01T00:00:00Z\n" +
+		"/never,svc-never,,,,2030-01-01T00:00:00Z/2029-01-01T00:00:00Z\n"
+
+	tree := New[*Route]()
+
+	report, err := tree.ImportCSV(strings.NewReader(input), factory)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	expected := []struct {
+		key    string
+		status InsertStatus
+		err    error
+	}{
+		{key: "/users", status: StatusInserted},
+		{key: "/users", status: StatusSkippedDuplicate},
+		{key: "/orders", status: StatusFailed, err: errFactory},
+		{key: "/slow", status: StatusFailed, err: errBadCSV},
+		{key: "/old", status: StatusInserted},
+		{key: "/bad/{", status: StatusSyntaxError, err: errBadPathParamSyntax},
+		{key: "/late", status: StatusFailed, err: errBadCSV},
+		{key: "/never", status: StatusFailed, err: errBadCSV},
+	}
+
+	if len(report) != len(expected) {
+		t.Fatalf("expected %d results; got: %d\n", len(expected), len(report))
+	}
+
+	for i, e := range expected {
+		res := report[i]
+
+		if res.Key != e.key || res.Status != e.status || !errors.Is(res.Err, e.err) {
+			t.Errorf("expected result %d: %s %s %v; got: %s %s %v\n", i, e.key, e.status, e.err, res.Key, res.Status, res.Err)
+		}
+	}
+
+	if _, code := tree.Find("/old").Redirect(); code != 301 {
+		t.Errorf("expected the default redirect code: 301; got: %d\n", code)
+	}
+}
@@ -0,0 +1,229 @@
+package rtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Delimiters are the delimiters of the segments and the params of
+// the patterns – and the delimiter of the segments of the keys.
+type Delimiters struct {
+	Segment    byte
+	ParamStart byte
+	ParamEnd   byte
+}
+
+// DefaultDelimiters are the delimiters of the URLs, eg. /users/{id}.
+var DefaultDelimiters = Delimiters{
+	Segment:    slash,
+	ParamStart: curlyStart,
+	ParamEnd:   curlyEnd,
+}
+
+// validate checks, that the delimiters could be swapped with the default
+// ones: they are distinct, and none of them is a default delimiter of an
+// other kind. The param delimiters could not be the chars used inside of
+// the params, while none of them could be the escape char.
+func (d Delimiters) validate() error {
+	if d.Segment == d.ParamStart || d.Segment == d.ParamEnd || d.ParamStart == d.ParamEnd {
+		return fmt.Errorf("%w: delimiters are not distinct", errInvalidOption)
+	}
+
+	for _, c := range []byte{d.Segment, d.ParamStart, d.ParamEnd} {
+		if c == escapeChar {
+			return fmt.Errorf("%w: the escape char is not a delimiter", errInvalidOption)
+		}
+	}
+
+	if d.Segment == curlyStart || d.Segment == curlyEnd ||
+		d.ParamStart == slash || d.ParamStart == curlyEnd ||
+		d.ParamEnd == slash || d.ParamEnd == curlyStart {
+		return fmt.Errorf("%w: delimiter of an other kind by default", errInvalidOption)
+	}
+
+	for _, c := range []byte{d.ParamStart, d.ParamEnd} {
+		if c == matcherSeparator || c == '.' {
+			return fmt.Errorf("%w: %q is used inside of the params", errInvalidOption, c)
+		}
+	}
+
+	return nil
+}
+
+// delimiterTable translates the patterns and the keys to the default
+// delimiters – and back –, so the tree itself only knows about those.
+// The delimiters are swapped with their defaults, so a default one is
+// a plain char of the translated patterns and keys.
+type delimiterTable struct {
+	Delimiters
+
+	swap [256]byte
+}
+
+func newDelimiterTable(d Delimiters) *delimiterTable {
+	dt := &delimiterTable{Delimiters: d}
+
+	for i := range dt.swap {
+		dt.swap[i] = byte(i)
+	}
+
+	for _, pair := range [][2]byte{
+		{d.Segment, slash},
+		{d.ParamStart, curlyStart},
+		{d.ParamEnd, curlyEnd},
+	} {
+		dt.swap[pair[0]] = pair[1]
+		dt.swap[pair[1]] = pair[0]
+	}
+
+	return dt
+}
+
+// WithDelimiters sets the delimiters of the segments and the params, eg.
+// the dots of the topics, like orders.{region}.created. The keys do not
+// have to start with the segment delimiter, as in WithObjectKeys. The
+// patterns – and the params of the catch-all routes – are reported with
+// the given delimiters as well.
+func WithDelimiters[T storeValue](d Delimiters) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if err := d.validate(); err != nil {
+			t.invalidOption("delimiters", d)
+			return
+		}
+
+		t.setOption("delimiters", d)
+		t.objectKeys = true
+
+		if d == DefaultDelimiters {
+			t.delimiters = nil
+			return
+		}
+
+		t.delimiters = newDelimiterTable(d)
+	}
+}
+
+// delims returns the delimiters of the tree.
+func (t *Tree[T]) delims() Delimiters {
+	if t.delimiters == nil {
+		return DefaultDelimiters
+	}
+
+	return t.delimiters.Delimiters
+}
+
+// split splits the pattern – written with the delimiters – to its
+// segments. The delimiters inside of the params – eg. the dots of
+// a catch-all param, when the dot is the segment delimiter – and
+// the escaped ones do not split it.
+func (d Delimiters) split(pattern string) []string {
+	var (
+		segments = make([]string, 0, strings.Count(pattern, string(d.Segment))+1)
+		start    = 0
+		inParam  = false
+	)
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == escapeChar:
+			i++
+		case c == d.ParamStart:
+			inParam = true
+		case c == d.ParamEnd:
+			inParam = false
+		case c == d.Segment && !inParam:
+			segments = append(segments, pattern[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(segments, pattern[start:])
+}
+
+// isParamSegment reports whether the segment of a pattern – written
+// with the delimiters – is a single param.
+func (d Delimiters) isParamSegment(segment string) bool {
+	l := len(segment)
+
+	return l >= 2 && segment[0] == d.ParamStart && segment[l-1] == d.ParamEnd
+}
+
+// isCatchAll reports whether the param segment is a catch-all one.
+func (d Delimiters) isCatchAll(segment string) bool {
+	return strings.HasSuffix(segment, catchAllSuffix+string(d.ParamEnd))
+}
+
+// segmentDelimiter returns the delimiter of the segments of the keys.
+func (t *Tree[T]) segmentDelimiter() byte {
+	if t.delimiters == nil {
+		return slash
+	}
+
+	return t.delimiters.Segment
+}
+
+// canonicalKey translates the searched key to the default delimiters.
+func (t *Tree[T]) canonicalKey(key string) string {
+	if t.delimiters == nil {
+		return key
+	}
+
+	b := []byte(key)
+
+	for i := range b {
+		b[i] = t.delimiters.swap[b[i]]
+	}
+
+	return string(b)
+}
+
+// canonicalPattern translates the pattern to the default delimiters.
+func (t *Tree[T]) canonicalPattern(pattern string) string {
+	if t == nil || t.delimiters == nil {
+		return pattern
+	}
+
+	return t.delimiters.translate(pattern, t.delimiters.ParamStart, t.delimiters.ParamEnd)
+}
+
+// externalPattern translates the stored pattern back to the delimiters of the tree.
+func (t *Tree[T]) externalPattern(pattern string) string {
+	if t.delimiters == nil {
+		return pattern
+	}
+
+	return t.delimiters.translate(pattern, curlyStart, curlyEnd)
+}
+
+// translate swaps the delimiters of the pattern, whose params are between
+// the given open and end chars. The names of the params are not swapped,
+// so the pattern translated back is the original one.
+func (dt *delimiterTable) translate(pattern string, open, end byte) string {
+	var (
+		b           = []byte(pattern)
+		insideParam = false
+	)
+
+	for i := 0; i < len(b); i++ {
+		switch {
+		case insideParam:
+			if b[i] == end {
+				insideParam = false
+				b[i] = dt.swap[b[i]]
+			}
+		case b[i] == escapeChar:
+			// The escaped char is swapped, but it is still escaped.
+			if i+1 < len(b) {
+				i++
+				b[i] = dt.swap[b[i]]
+			}
+		case b[i] == open:
+			insideParam = true
+			b[i] = dt.swap[b[i]]
+		default:
+			b[i] = dt.swap[b[i]]
+		}
+	}
+
+	return string(b)
+}
@@ -0,0 +1,189 @@
+package rtree
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDelimiters(t *testing.T) {
+	type testCase struct {
+		name            string
+		delimiters      Delimiters
+		patterns        []string
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	var (
+		dots   = Delimiters{Segment: '.', ParamStart: '{', ParamEnd: '}'}
+		angles = Delimiters{Segment: '/', ParamStart: '<', ParamEnd: '>'}
+	)
+
+	tt := []testCase{
+		{
+			name:            "topic with param",
+			delimiters:      dots,
+			patterns:        []string{"orders.{region}.created", "orders.{region}.deleted"},
+			key:             "orders.eu.created",
+			expectedPattern: "orders.{region}.created",
+			expectedParams:  matchedParams{"region": "eu"},
+		},
+		{
+			name:            "slash is a plain char",
+			delimiters:      dots,
+			patterns:        []string{"files.{name}"},
+			key:             "files.a/b",
+			expectedPattern: "files.{name}",
+			expectedParams:  matchedParams{"name": "a/b"},
+		},
+		{
+			name:            "catch-all is joined by the delimiter",
+			delimiters:      dots,
+			patterns:        []string{"logs.{rest...}"},
+			key:             "logs.app.error.db",
+			expectedPattern: "logs.{rest...}",
+			expectedParams:  matchedParams{"rest": "app.error.db"},
+		},
+		{
+			name:            "matcher of the param",
+			delimiters:      dots,
+			patterns:        []string{"orders.{id:int}", "orders.{name}"},
+			key:             "orders.42",
+			expectedPattern: "orders.{id:int}",
+			expectedParams:  matchedParams{"id": "42"},
+		},
+		{
+			name:       "no match",
+			delimiters: dots,
+			patterns:   []string{"orders.{region}.created"},
+			key:        "orders/eu/created",
+		},
+		{
+			name:            "angle brackets",
+			delimiters:      angles,
+			patterns:        []string{"/users/<id>"},
+			key:             "/users/42",
+			expectedPattern: "/users/<id>",
+			expectedParams:  matchedParams{"id": "42"},
+		},
+		{
+			name:            "curly brackets are plain chars",
+			delimiters:      angles,
+			patterns:        []string{"/tpl/{name}", "/tpl/<id>"},
+			key:             "/tpl/{name}",
+			expectedPattern: "/tpl/{name}",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "escaped param delimiter",
+			delimiters:      angles,
+			patterns:        []string{"/raw/\\<id\\>"},
+			key:             "/raw/<id>",
+			expectedPattern: "/raw/\\<id\\>",
+			expectedParams:  matchedParams{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(WithDelimiters[*Route](tc.delimiters), WithSegmentMatcher[*Route]("int", Int))
+
+			for _, p := range tc.patterns {
+				if err := tree.Insert(p, &Route{}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			fn := tree.Find(tc.key)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+			}
+
+			// The routes are deleted by their original patterns.
+			if err := tree.Delete(tc.expectedPattern); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		})
+	}
+}
+
+func TestDelimitersValidation(t *testing.T) {
+	tt := []Delimiters{
+		{Segment: '.', ParamStart: '.', ParamEnd: '}'},
+		{Segment: '{', ParamStart: '<', ParamEnd: '>'},
+		{Segment: '/', ParamStart: '/', ParamEnd: '>'},
+		{Segment: '/', ParamStart: '}', ParamEnd: '{'},
+		{Segment: '\\', ParamStart: '{', ParamEnd: '}'},
+		{Segment: '/', ParamStart: ':', ParamEnd: '}'},
+	}
+
+	for _, d := range tt {
+		if _, err := NewChecked(WithDelimiters[*Route](d)); !errors.Is(err, errInvalidOption) {
+			t.Errorf("expected error of %+v: %v; got: %v\n", d, errInvalidOption, err)
+		}
+	}
+
+	if _, err := NewChecked(WithDelimiters[*Route](DefaultDelimiters)); err != nil {
+		t.Errorf("not expected error, but got: %v\n", err)
+	}
+}
+
+func TestDelimitersReports(t *testing.T) {
+	tree := New(WithDelimiters[*Route](Delimiters{Segment: '.', ParamStart: '<', ParamEnd: '>'}))
+
+	for _, p := range []string{"orders.<region>.created", "orders.list", "files.<path...>"} {
+		if err := tree.Insert(p, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	for _, key := range tree.GenerateRequests(9, 1) {
+		if strings.ContainsAny(key, "/<>") || tree.Find(key) == nil {
+			t.Errorf("expected a key with the delimiters of the tree; got: %s\n", key)
+		}
+	}
+
+	expectedGroups := map[string][]string{
+		"files":  {"files.<path...>"},
+		"orders": {"orders.<region>.created", "orders.list"},
+	}
+
+	if got := tree.GroupByPrefix(1); !reflect.DeepEqual(expectedGroups, got) {
+		t.Errorf("expected groups: %v; got: %v\n", expectedGroups, got)
+	}
+
+	if got := tree.Explain("orders.eu.created").Pattern; got != "orders.<region>.created" {
+		t.Errorf("expected explained pattern: orders.<region>.created; got: %s\n", got)
+	}
+
+	c := NewCoverage(tree)
+	c.Find("orders.list")
+
+	if got := c.Report().Hits; !reflect.DeepEqual(map[string]int{"orders.list": 1}, got) {
+		t.Errorf("expected hits: map[orders.list:1]; got: %v\n", got)
+	}
+
+	if _, err := fs.Stat(tree.FS(), "orders/<region>/created"); err != nil {
+		t.Errorf("not expected error, but got: %v\n", err)
+	}
+}
@@ -0,0 +1,113 @@
+package rtree
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrRouteDisabled is returned by FindE, if only a disabled route matches the key.
+var ErrRouteDisabled = fmt.Errorf("[rtree %s]: route is disabled", version)
+
+// Disable switches off the route of the given pattern, without removing
+// it: the lookups skip it – as if it was not stored –, until it is enabled
+// again, while its value and metadata are kept intact. Just like the other
+// metadata, the disabled state is not persisted. Disabling a disabled
+// route does nothing.
+func (t *Tree[T]) Disable(pattern string) error {
+	return t.setDisabled(pattern, true)
+}
+
+// Enable switches the disabled route of the given pattern on again.
+// Enabling a route, that is not disabled, does nothing.
+func (t *Tree[T]) Enable(pattern string) error {
+	return t.setDisabled(pattern, false)
+}
+
+// IsDisabled reports whether the route of the given pattern is disabled.
+func (t *Tree[T]) IsDisabled(pattern string) bool {
+	if checkTree(t) != nil || pattern == "" {
+		return false
+	}
+
+	pattern = t.normalizePattern(pattern)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	n := findExactRec(t.root, pattern)
+
+	return n != nil && n.value.meta.disabled
+}
+
+// IsDisabled reports whether the route is disabled.
+func (nv *NodeValue[T]) IsDisabled() bool {
+	return nv.meta.disabled
+}
+
+func (t *Tree[T]) setDisabled(pattern string, disabled bool) error {
+	if err := checkTree(t); err != nil {
+		return err
+	}
+
+	if pattern == "" {
+		return ErrKeyIsEmpty
+	}
+
+	pattern = t.normalizePattern(pattern)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := findExactRec(t.root, pattern)
+	if n == nil {
+		return errKeyNotFound
+	}
+
+	if n.value.meta.disabled == disabled {
+		return nil
+	}
+
+	// The value is replaced – just like by Upsert –, instead of
+	// being altered, since the lookups could still be reading it.
+	nv := *n.value
+	nv.meta.disabled = disabled
+
+	n.value = &nv
+	t.publish(&nv)
+
+	t.generation.Add(1)
+	t.notify(ChangeUpdate, &nv, t.resolve(&nv).value)
+
+	return nil
+}
+
+// matchesDisabled reports whether a disabled route matches the key.
+func (t *Tree[T]) matchesDisabled(key string) bool {
+	if t.runeMatching && !utf8.ValidString(key) {
+		return false
+	}
+
+	if t.readLocking {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+	}
+
+	segments := strings.Split(key, string(t.segmentDelimiter()))
+
+	accept := func(n *Node[T]) bool {
+		if !n.value.meta.disabled {
+			return false
+		}
+
+		_, ok := t.matchSegments(n.value, segments, 0)
+		return ok
+	}
+
+	n := findRec(t.root, t.searchKey(key), false, &search[T]{
+		accept: accept,
+		budget: &budget{limit: t.backtrackBudget},
+	})
+
+	return n != nil && n.value != nil
+}
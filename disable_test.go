@@ -0,0 +1,150 @@
+package rtree
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDisableAndEnable(t *testing.T) {
+	tree := New[*Route](WithLookupCache[*Route](16))
+
+	routes := map[string]string{
+		"/api/users/{id}": "user",
+		"/api/users/me":   "me",
+		"/api/{rest...}":  "rest",
+		"/static":         "static",
+	}
+
+	for pattern, name := range routes {
+		if err := tree.Insert(pattern, &Route{name: name}, WithDescription(name)); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// The lookup is cached before the route is disabled.
+	if fn := tree.Find("/api/users/me"); fn == nil || fn.GetValue().name != "me" {
+		t.Fatalf("expected the route to be found\n")
+	}
+
+	events, stop := tree.Watch(context.Background())
+	defer stop()
+
+	if err := tree.Disable("/api/users/me"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Disable("/static"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// Disabling twice does nothing.
+	if err := tree.Disable("/static"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if e := <-events; e.Kind != ChangeUpdate || e.Key != "/api/users/me" {
+		t.Errorf("expected the update of /api/users/me; got: %v %s\n", e.Kind, e.Key)
+	}
+
+	tt := []struct {
+		name         string
+		key          string
+		expectedName string
+		expectedErr  error
+	}{
+		{
+			name:         "disabled route falls through to the other branches",
+			key:          "/api/users/me",
+			expectedName: "user",
+		},
+		{
+			name:         "other routes are not affected",
+			key:          "/api/users/5",
+			expectedName: "user",
+		},
+		{
+			name:        "disabled route without other branches",
+			key:         "/static",
+			expectedErr: ErrRouteDisabled,
+		},
+		{
+			name:        "plain miss",
+			key:         "/missing",
+			expectedErr: ErrNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn, err := tree.FindE(tc.key)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn.GetValue().name != tc.expectedName {
+				t.Errorf("expected: %s; got: %s\n", tc.expectedName, fn.GetValue().name)
+			}
+
+			if h := tree.Resolve(tc.key); h == nil || h.Value().name != tc.expectedName {
+				t.Errorf("expected %s to be resolved\n", tc.expectedName)
+			}
+		})
+	}
+
+	if fn := tree.FindLongestMatch("/static/app.js"); fn != nil {
+		t.Errorf("expected no longest match of a disabled route; got: %s\n", fn.GetPattern())
+	}
+
+	if !tree.IsDisabled("/static") || tree.IsDisabled("/api/users/{id}") {
+		t.Errorf("expected only the disabled routes to be reported\n")
+	}
+
+	// The disabled routes are still listed.
+	if leaves := tree.GetAllLeaf(); len(leaves) != len(routes) {
+		t.Errorf("expected %d leaves; got: %d\n", len(routes), len(leaves))
+	}
+
+	if err := tree.Enable("/static"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	fn := tree.Find("/static")
+	if fn == nil || fn.GetValue().name != "static" || fn.Description() != "static" {
+		t.Errorf("expected the enabled route with its value and metadata\n")
+	}
+
+	if err := tree.Disable("/unknown"); !errors.Is(err, errKeyNotFound) {
+		t.Errorf("expected error: %v; got: %v\n", errKeyNotFound, err)
+	}
+
+	if err := tree.Enable(""); !errors.Is(err, ErrKeyIsEmpty) {
+		t.Errorf("expected error: %v; got: %v\n", ErrKeyIsEmpty, err)
+	}
+}
+
+func TestDisabledRouteIsNotAllowed(t *testing.T) {
+	mt := NewMethodTrees[*Route]()
+
+	for _, method := range []string{"GET", "DELETE"} {
+		if err := mt.Insert(method, "/users/{id}", getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := mt.Table().Tree("DELETE").Disable("/users/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if allowed := mt.Table().Allowed("/users/1"); !reflect.DeepEqual(allowed, []string{"GET"}) {
+		t.Errorf("expected allowed methods: [GET]; got: %v\n", allowed)
+	}
+}
@@ -7,15 +7,30 @@
 //	/api/foo/products/1										-> resource="products" 		id="1"
 //	/api/foo/categories/example-category 	-> resource="categories"  id="example-category"
 //
-// Due to the way we store these routes, there is a chance of trying to store
-// ambigoues routes aswell. That obviously would cause a bad behaviour.
-// Example for these routes:
+// Due to the way we store these routes, there is a chance of storing
+// overlapping routes aswell. Example for these routes:
 //
 // /api/{resource}/get
 // /api/products/get
 //
-// The two above would cause an error, however the two 2 below would not:
+// In this case the most specific route wins: the static children of a node
+// are always tried before the wildcard ones, regardless of the insertion
+// order. So /api/products/get matches the second route, while
+// /api/users/get matches the first one.
 //
-// /api/{resource}/get
-// /api/products/get-all
+// Wildcard siblings are all tried, the one with more static bytes first,
+// eg. /a/{x}/c before /a/{y}/{z}. Equally specific siblings are ordered
+// by their keys, so the winner never depends on the insertion order.
+//
+// A lookup of static routes is linear in the length of the key and in the
+// number of siblings on the way. Since the wildcard siblings are all tried,
+// the worst case of a lookup is visiting every node of the tree once –
+// twice with CatchAllFirst. WithBacktrackBudget puts an upper bound on it.
+//
+// The zero value of Tree is an empty tree ready to use – the same as New
+// without options –, since its root is created by the first insertion.
+// A nil *Tree is safe to call as well: its lookups miss and its listings
+// are empty, while the methods returning an error – eg. Insert, Delete
+// or Walk – report, that the tree is nil. An empty tree is never an
+// error: Delete reports the key as not found, and Walk visits nothing.
 package rtree
@@ -0,0 +1,154 @@
+package rtree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Equal reports whether the two trees have the same structure – the same
+// nodes with the same keys, in the same order of trying, see Dump – and
+// whether the values of their leaves are equal according to eq. If eq is nil, the values
+// are not compared. Two nil trees are equal.
+func Equal[T storeValue](a, b *Tree[T], eq func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if a == b {
+		return true
+	}
+
+	// The trees are never locked at the same time – a comparison of
+	// the same trees in the other order could wait for the first lock,
+	// while a writer is waiting for the second –, so the first one is
+	// copied under its own lock.
+	a.mu.RLock()
+	shape := shapeOf(a.root)
+	a.mu.RUnlock()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return equalRec(shape, b.root, eq)
+}
+
+// nodeShape is the copy of a node, as much as Equal compares of it.
+type nodeShape[T storeValue] struct {
+	key      string
+	leaf     bool
+	value    T
+	children []*nodeShape[T]
+}
+
+// shapeOf copies the node and its descendants – the children
+// in the canonical order –, or returns nil for a nil node.
+func shapeOf[T storeValue](n *Node[T]) *nodeShape[T] {
+	if n == nil {
+		return nil
+	}
+
+	s := &nodeShape[T]{
+		key:      n.key,
+		leaf:     n.IsLeaf(),
+		children: make([]*nodeShape[T], 0, len(n.children)),
+	}
+
+	if s.leaf {
+		s.value = n.value.value
+	}
+
+	for _, ch := range canonicalChildren(n) {
+		s.children = append(s.children, shapeOf(ch))
+	}
+
+	return s
+}
+
+func equalRec[T storeValue](a *nodeShape[T], b *Node[T], eq func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if a.key != b.key || a.leaf != b.IsLeaf() || len(a.children) != len(b.children) {
+		return false
+	}
+
+	if a.leaf && eq != nil && !eq(a.value, b.value.value) {
+		return false
+	}
+
+	for i, ch := range canonicalChildren(b) {
+		if !equalRec(a.children[i], ch, eq) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Dump returns the canonical text form of the structure of the tree, eg.
+//
+//	"/api/"
+//	  "products/get" *
+//	  "{resource}/get" *
+//
+// Every node is a line with its quoted key, indented by its depth, and the
+// leaves are marked with a star. The static siblings keep their order of
+// insertion in the tree – only one of them could match at all –, so they
+// are sorted by their keys. The trees with the same structure have the
+// same dump, so it could be compared to a golden file in the tests,
+// which rely on the exact splitting of the nodes.
+func (t *Tree[T]) Dump() string {
+	if checkTree(t) != nil {
+		return ""
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var sb strings.Builder
+
+	if t.root != nil {
+		dumpRec(&sb, t.root, 0)
+	}
+
+	return sb.String()
+}
+
+func dumpRec[T storeValue](sb *strings.Builder, n *Node[T], depth int) {
+	fmt.Fprintf(sb, "%s%q", strings.Repeat("  ", depth), n.key)
+
+	if n.IsLeaf() {
+		sb.WriteString(" *")
+	}
+
+	sb.WriteString("\n")
+
+	for _, ch := range canonicalChildren(n) {
+		dumpRec(sb, ch, depth+1)
+	}
+}
+
+// canonicalChildren returns the children of the node, with the static
+// ones – which precede the wildcard ones – sorted by their keys.
+func canonicalChildren[T storeValue](n *Node[T]) []*Node[T] {
+	static := 0
+
+	for static < len(n.children) && nodeRank(n.children[static].key) == 0 {
+		static++
+	}
+
+	if static < 2 {
+		return n.children
+	}
+
+	children := make([]*Node[T], len(n.children))
+	copy(children, n.children)
+
+	sort.Slice(children[:static], func(i, j int) bool {
+		return children[i].key < children[j].key
+	})
+
+	return children
+}
@@ -0,0 +1,166 @@
+package rtree
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEqual(t *testing.T) {
+	patterns := []string{
+		"/api/{resource}/get",
+		"/api/products/get",
+		"/api/products/list",
+		"/files/{path...}",
+	}
+
+	build := func(order []int, values map[string]string) *Tree[string] {
+		tree := New[string]()
+
+		for _, i := range order {
+			if err := tree.Insert(patterns[i], values[patterns[i]]); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		return tree
+	}
+
+	values := map[string]string{
+		"/api/{resource}/get": "resource",
+		"/api/products/get":   "get",
+		"/api/products/list":  "list",
+		"/files/{path...}":    "files",
+	}
+
+	changed := map[string]string{
+		"/api/{resource}/get": "resource",
+		"/api/products/get":   "get-v2",
+		"/api/products/list":  "list",
+		"/files/{path...}":    "files",
+	}
+
+	eq := func(a, b string) bool { return a == b }
+
+	var (
+		forward  = build([]int{0, 1, 2, 3}, values)
+		backward = build([]int{3, 2, 1, 0}, values)
+		other    = build([]int{1, 3, 0, 2}, changed)
+		fewer    = build([]int{0, 1, 2}, values)
+		nilTree  *Tree[string]
+	)
+
+	tt := []struct {
+		name     string
+		a, b     *Tree[string]
+		eq       func(string, string) bool
+		expected bool
+	}{
+		{name: "same tree", a: forward, b: forward, eq: eq, expected: true},
+		{name: "different insertion order", a: forward, b: backward, eq: eq, expected: true},
+		{name: "different values", a: forward, b: other, eq: eq, expected: false},
+		{name: "values not compared", a: forward, b: other, eq: nil, expected: true},
+		{name: "missing route", a: forward, b: fewer, eq: eq, expected: false},
+		{name: "empty tree", a: forward, b: New[string](), eq: eq, expected: false},
+		{name: "both empty", a: New[string](), b: New[string](), eq: eq, expected: true},
+		{name: "nil tree", a: forward, b: nilTree, eq: eq, expected: false},
+		{name: "both nil", a: nilTree, b: nilTree, eq: eq, expected: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Equal(tc.a, tc.b, tc.eq); got != tc.expected {
+				t.Errorf("expected equal: %v; got: %v\n", tc.expected, got)
+			}
+
+			if got := Equal(tc.b, tc.a, tc.eq); got != tc.expected {
+				t.Errorf("expected symmetric equal: %v; got: %v\n", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestEqualLocking(t *testing.T) {
+	var (
+		a = New[string]()
+		b = New[string]()
+	)
+
+	// While a writer holds the second tree, the comparison must not
+	// hold the first one, or the same comparison in the other order –
+	// waiting for the first tree behind its writer – would deadlock.
+	b.mu.Lock()
+
+	compared := make(chan bool)
+
+	go func() {
+		compared <- Equal(a, b, nil)
+	}()
+
+	// The comparison is given time to wait for the second tree.
+	time.Sleep(50 * time.Millisecond)
+
+	written := make(chan error)
+
+	go func() {
+		written <- a.Insert("/users", "users")
+	}()
+
+	select {
+	case err := <-written:
+		if err != nil {
+			t.Errorf("not expected error, but got: %v\n", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expected the writer of the first tree not to wait for the comparison\n")
+	}
+
+	b.mu.Unlock()
+
+	<-compared
+}
+
+func TestDump(t *testing.T) {
+	tree := New[*Route]()
+
+	for _, p := range []string{"/api/{resource}/get", "/api/products/get", "/api/products/list", "/files/{path...}"} {
+		if err := tree.Insert(p, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	golden := `"/"
+  "api/"
+    "products/"
+      "get" *
+      "list" *
+    "{resource}/get" *
+  "files/{path...}" *
+`
+
+	// The static siblings are sorted, regardless of the order of insertion.
+	reversed := New[*Route]()
+
+	for _, p := range []string{"/files/{path...}", "/api/products/list", "/api/products/get", "/api/{resource}/get"} {
+		if err := reversed.Insert(p, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if got := reversed.Dump(); got != golden {
+		t.Fatalf("expected dump:\n%s\ngot:\n%s\n", golden, got)
+	}
+
+	if got := tree.Dump(); got != golden {
+		t.Fatalf("expected dump:\n%s\ngot:\n%s\n", golden, got)
+	}
+
+	var nilTree *Tree[*Route]
+
+	if got := nilTree.Dump(); got != "" {
+		t.Errorf("expected empty dump of a nil tree; got: %q\n", got)
+	}
+
+	if got := New[*Route]().Dump(); got != "" {
+		t.Errorf("expected empty dump of an empty tree; got: %q\n", got)
+	}
+}
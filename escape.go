@@ -0,0 +1,104 @@
+package rtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// escapeChar escapes the literal curly brackets – and itself – in the
+// patterns, eg. /legacy/\{id\} matches the literal path /legacy/{id}.
+const escapeChar = '\\'
+
+var errBadEscape = fmt.Errorf("%w: bad escape sequence", errBadPathParamSyntax)
+
+// isEscapable reports whether the char could – and must – be escaped.
+func isEscapable(c byte) bool {
+	return c == curlyStart || c == curlyEnd || c == escapeChar
+}
+
+// escapeKey escapes the search key the same way as the patterns are
+// escaped, so the literal brackets of the key are never taken as params.
+func escapeKey(key string) string {
+	if !strings.ContainsAny(key, `{}\`) {
+		return key
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(key) + 2)
+
+	for i := 0; i < len(key); i++ {
+		if isEscapable(key[i]) {
+			sb.WriteByte(escapeChar)
+		}
+		sb.WriteByte(key[i])
+	}
+
+	return sb.String()
+}
+
+// unescape removes the escape chars of the – static part of a – pattern.
+func unescape(pattern string) string {
+	if strings.IndexByte(pattern, escapeChar) == -1 {
+		return pattern
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(pattern))
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == escapeChar && i+1 < len(pattern) {
+			i++
+		}
+		sb.WriteByte(pattern[i])
+	}
+
+	return sb.String()
+}
+
+// isEscaped reports whether the char at the given index is escaped,
+// meaning it is preceded by an odd number of escape chars.
+func isEscaped(s string, idx int) bool {
+	count := 0
+
+	for i := idx - 1; i >= 0 && s[i] == escapeChar; i-- {
+		count++
+	}
+
+	return count%2 == 1
+}
+
+// indexUnescaped returns the index of the first unescaped c in s, or -1.
+func indexUnescaped(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == escapeChar {
+			i++
+			continue
+		}
+
+		if s[i] == c {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// countUnescaped returns the number of the unescaped c in s.
+func countUnescaped(s string, c byte) int {
+	count := 0
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == escapeChar {
+			i++
+			continue
+		}
+
+		if s[i] == c {
+			count++
+		}
+	}
+
+	return count
+}
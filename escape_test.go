@@ -0,0 +1,206 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckPathParamsEscape(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		err   error
+	}
+
+	tt := []testCase{
+		{
+			name:  "no error on escaped brackets",
+			input: `/legacy/\{id\}`,
+			err:   nil,
+		},
+		{
+			name:  "no error on escaped escape char",
+			input: `/legacy/a\\b`,
+			err:   nil,
+		},
+		{
+			name:  "no error on escaped brackets next to a param",
+			input: `/legacy/\{id\}/{id}`,
+			err:   nil,
+		},
+		{
+			name:  "error on escaping other chars",
+			input: `/legacy/\a`,
+			err:   errBadEscape,
+		},
+		{
+			name:  "error on escape char at the end",
+			input: `/legacy/a\`,
+			err:   errBadEscape,
+		},
+		{
+			name:  "error on escape inside a param",
+			input: `/legacy/{i\}d}`,
+			err:   errBadEscape,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := checkPathParams(tc.input); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestFindEscaped(t *testing.T) {
+	type testCase struct {
+		name            string
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	tree := New[*Route]()
+
+	for _, k := range []string{
+		`/legacy/\{id\}`,
+		`/legacy/{id}`,
+		`/legacy/\{a\}/x`,
+		`/legacy/\}b/y`,
+		`/legacy/a\\b`,
+	} {
+		if err := tree.Insert(k, &Route{name: k}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:            "literal brackets",
+			key:             "/legacy/{id}",
+			expectedPattern: `/legacy/\{id\}`,
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "param next to the literal brackets",
+			key:             "/legacy/5",
+			expectedPattern: `/legacy/{id}`,
+			expectedParams:  matchedParams{"id": "5"},
+		},
+		{
+			name:            "param value with brackets",
+			key:             "/legacy/a{b}",
+			expectedPattern: `/legacy/{id}`,
+			expectedParams:  matchedParams{"id": "a{b}"},
+		},
+		{
+			name:            "literal brackets in the middle",
+			key:             "/legacy/{a}/x",
+			expectedPattern: `/legacy/\{a\}/x`,
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "literal closing bracket sharing a prefix with an escaped opening one",
+			key:             "/legacy/}b/y",
+			expectedPattern: `/legacy/\}b/y`,
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "literal escape char",
+			key:             `/legacy/a\b`,
+			expectedPattern: `/legacy/a\\b`,
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "no match on the escaped form",
+			key:             `/legacy/\{a\}/x`,
+			expectedPattern: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.Find(tc.key)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Errorf("expected not to find, but got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetPattern(); got != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, got)
+			}
+
+			if got := fn.GetParams(); !reflect.DeepEqual(got, tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, got)
+			}
+		})
+	}
+}
+
+func TestFindLongestMatchEscaped(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert(`/legacy/\{id\}`, getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	fn := tree.FindLongestMatch("/legacy/{id}/more")
+	if fn == nil {
+		t.Fatal("expected to find, but got <nil>")
+	}
+
+	if got := fn.GetPattern(); got != `/legacy/\{id\}` {
+		t.Errorf("expected pattern: %s; got: %s\n", `/legacy/\{id\}`, got)
+	}
+}
+
+func TestExpandEscaped(t *testing.T) {
+	got, err := Expand(`/legacy/\{id\}/{id}`, map[string]string{"id": "5"})
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got != "/legacy/{id}/5" {
+		t.Errorf("expected: %s; got: %s\n", "/legacy/{id}/5", got)
+	}
+}
+
+func TestGetPathParamsEscaped(t *testing.T) {
+	expected := []paramInfo{{key: "id", pos: 3}}
+
+	if got := getPathParams(`/legacy/\{x\}/{id}`); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected params: %v; got: %v\n", expected, got)
+	}
+}
+
+func TestEscapedParamIdx(t *testing.T) {
+	tree := New[*Route]()
+
+	// Every node is created with an escaped bracket in its key.
+	for _, k := range []string{`/legacy/\{id\}`, `/old/\{a\}/{b}`, `/old/\{a\}/x`} {
+		if err := tree.Insert(k, &Route{name: k}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	err := tree.Walk(func(n *Node[*Route]) WalkVerdict {
+		if expected := indexUnescaped(n.key, curlyStart); n.paramIdx != expected {
+			t.Errorf("expected param index of %s: %d; got: %d\n", n.key, expected, n.paramIdx)
+		}
+
+		return Continue
+	})
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+}
@@ -0,0 +1,114 @@
+package rtree_test
+
+import (
+	"fmt"
+
+	"github.com/balazskvancz/rtree"
+)
+
+type handler struct {
+	name string
+}
+
+func ExampleTree_Insert() {
+	tree := rtree.New[*handler]()
+
+	fmt.Println(tree.Insert("/api/users", &handler{name: "list users"}))
+	fmt.Println(tree.Insert("/api/users/{id}", &handler{name: "get user"}))
+
+	// The keys must start with a slash, and must not end with one.
+	fmt.Println(tree.Insert("api/users", &handler{}) != nil)
+	fmt.Println(tree.Insert("/api/users/", &handler{}) != nil)
+
+	// Output:
+	// <nil>
+	// <nil>
+	// true
+	// true
+}
+
+func ExampleTree_Find() {
+	tree := rtree.New[*handler]()
+
+	_ = tree.Insert("/api/users", &handler{name: "list users"})
+	_ = tree.Insert("/api/users/{id}", &handler{name: "get user"})
+
+	if fn := tree.Find("/api/users/42"); fn != nil {
+		fmt.Println(fn.GetValue().name, fn.GetPattern())
+	}
+
+	fmt.Println(tree.Find("/api/groups") == nil)
+
+	// Output:
+	// get user /api/users/{id}
+	// true
+}
+
+func ExampleTree_FindLongestMatch() {
+	tree := rtree.New[*handler]()
+
+	_ = tree.Insert("/api", &handler{name: "gateway"})
+	_ = tree.Insert("/api/billing", &handler{name: "billing service"})
+
+	fmt.Println(tree.FindLongestMatch("/api/billing/invoices/7").GetValue().name)
+	fmt.Println(tree.FindLongestMatch("/api/users").GetValue().name)
+
+	// Output:
+	// billing service
+	// gateway
+}
+
+// The most specific route wins, regardless of the insertion order.
+func Example_wildcard() {
+	tree := rtree.New[*handler]()
+
+	_ = tree.Insert("/files/{path...}", &handler{name: "download"})
+	_ = tree.Insert("/files/{dir}/index", &handler{name: "index"})
+	_ = tree.Insert("/files/readme", &handler{name: "readme"})
+
+	for _, key := range []string{"/files/readme", "/files/docs/index", "/files/docs/a/b.txt"} {
+		fn := tree.Find(key)
+
+		fmt.Println(key, "->", fn.GetValue().name, fn.GetParams())
+	}
+
+	// Output:
+	// /files/readme -> readme map[]
+	// /files/docs/index -> index map[dir:docs]
+	// /files/docs/a/b.txt -> download map[path:docs/a/b.txt]
+}
+
+func Example_params() {
+	tree := rtree.New[*handler]()
+
+	_ = tree.Insert("/api/{resource}/{id}", &handler{name: "resource"})
+
+	fn := tree.Find("/api/products/example-product")
+
+	params := fn.GetParams()
+
+	fmt.Println(params["resource"], params["id"])
+
+	// Params are expanded back into the pattern.
+	key, _ := rtree.Expand(fn.GetPattern(), params)
+
+	fmt.Println(key)
+
+	// Output:
+	// products example-product
+	// /api/products/example-product
+}
+
+func Example_segmentMatcher() {
+	tree := rtree.New(rtree.WithSegmentMatcher[*handler]("int", rtree.Int))
+
+	_ = tree.Insert("/users/{id:int}", &handler{name: "by id"})
+	_ = tree.Insert("/users/{name}", &handler{name: "by name"})
+
+	fmt.Println(tree.Find("/users/42").GetValue().name)
+	fmt.Println(tree.Find("/users/alice").GetValue().name)
+
+	// Output:
+	// by id
+	// by name
+}
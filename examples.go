@@ -0,0 +1,97 @@
+package rtree
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// Examples returns at most n distinct concrete keys of the given URL
+// pattern, eg. for table-driven tests of the handlers. The params are
+// substituted the same way as by GenerateRequests, but since there is
+// no tree, neither matchers nor constraints are known – use the
+// Examples method of the tree for those. The keys only depend on the
+// pattern, and a pattern without params has a single example, itself.
+// An invalid pattern has none.
+func Examples(pattern string, n int) []string {
+	if n <= 0 || pattern == "" || checkUrl(pattern) != nil {
+		return make([]string, 0)
+	}
+
+	var (
+		segments = DefaultDelimiters.split(pattern)
+		rnd      = patternRand(pattern)
+	)
+
+	return collectExamples(n, len(getPathParams(pattern)) > 0, func() (string, bool) {
+		return sampleKey(segments, DefaultDelimiters, rnd), true
+	})
+}
+
+// Examples returns at most n distinct concrete keys, that resolve to the
+// stored route of the given pattern. Unlike the package-level Examples,
+// the segment matchers, the constraints and the param policies are all
+// honored, since a key is only returned, if the lookup of it resolves
+// to the route. The pattern has to be stored as it is, otherwise there
+// are no examples.
+func (t *Tree[T]) Examples(pattern string, n int) []string {
+	if n <= 0 || pattern == "" || checkTree(t) != nil {
+		return make([]string, 0)
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	path := findExactPath(t.root, t.normalizePattern(pattern))
+	if path == nil {
+		return make([]string, 0)
+	}
+
+	return t.examples(path[len(path)-1], n)
+}
+
+// examples returns at most n distinct keys of the given leaf.
+// The caller must hold the read lock.
+func (t *Tree[T]) examples(leaf *Node[T], n int) []string {
+	rnd := patternRand(leaf.value.Pattern())
+
+	return collectExamples(n, len(leaf.value.params) > 0, func() (string, bool) {
+		return t.generateKey(leaf, rnd)
+	})
+}
+
+// collectExamples calls sample until it has n distinct keys, or until
+// it is given up. Without params every sample would be the same.
+func collectExamples(n int, hasParams bool, sample func() (string, bool)) []string {
+	var (
+		keys = make([]string, 0, n)
+		seen = make(map[string]struct{}, n)
+	)
+
+	for attempt := 0; attempt < n*maxGenerateAttempts && len(keys) < n; attempt++ {
+		key, ok := sample()
+		if !ok {
+			// generateKey already made its own attempts.
+			break
+		}
+
+		if _, exists := seen[key]; !exists {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+
+		if !hasParams {
+			break
+		}
+	}
+
+	return keys
+}
+
+// patternRand returns the source of the examples of the pattern,
+// seeded by its hash, so the examples are reproducible.
+func patternRand(pattern string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(pattern))
+
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
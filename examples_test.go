@@ -0,0 +1,131 @@
+package rtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExamples(t *testing.T) {
+	tt := []struct {
+		name     string
+		pattern  string
+		n        int
+		expected int
+	}{
+		{name: "static pattern", pattern: "/api/users", n: 5, expected: 1},
+		{name: "escaped braces", pattern: `/api/\{literal\}`, n: 5, expected: 1},
+		{name: "single param", pattern: "/api/users/{id}", n: 5, expected: 5},
+		{name: "catch-all", pattern: "/files/{path...}", n: 3, expected: 3},
+		{name: "zero", pattern: "/api/users/{id}", n: 0, expected: 0},
+		{name: "empty pattern", pattern: "", n: 5, expected: 0},
+		{name: "invalid pattern", pattern: "api/users", n: 5, expected: 0},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Examples(tc.pattern, tc.n)
+
+			if len(got) != tc.expected {
+				t.Fatalf("expected %d examples; got: %v\n", tc.expected, got)
+			}
+
+			tree := New[*Route]()
+
+			if tc.expected > 0 {
+				if err := tree.Insert(tc.pattern, getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			seen := make(map[string]struct{})
+
+			for _, key := range got {
+				if _, exists := seen[key]; exists {
+					t.Errorf("duplicate example: %s\n", key)
+				}
+
+				seen[key] = struct{}{}
+
+				if fn := tree.Find(key); fn == nil || fn.pattern != tc.pattern {
+					t.Errorf("example %s does not match %s\n", key, tc.pattern)
+				}
+			}
+
+			if again := Examples(tc.pattern, tc.n); !reflect.DeepEqual(got, again) {
+				t.Errorf("expected the same examples; got: %v and %v\n", got, again)
+			}
+		})
+	}
+
+	if got := Examples(`/api/\{literal\}`, 1); got[0] != "/api/{literal}" {
+		t.Errorf("expected unescaped example; got: %s\n", got[0])
+	}
+}
+
+func TestTreeExamples(t *testing.T) {
+	tree := New(WithSegmentMatcher[*Route]("hex", SegmentMatcherFunc(func(segment string) (bool, string) {
+		return segment != "" && strings.Trim(segment, hexDigits) == "", segment
+	})))
+
+	spec, err := NewRoute("/api/users/{id}").Constraint("id", Int).Build()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertRoute(spec, getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for _, p := range []string{"/api/users/{name}/posts", "/blobs/{sum:hex}", "/static"} {
+		if err := tree.Insert(p, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []struct {
+		name     string
+		pattern  string
+		n        int
+		expected int
+		check    func(key string) bool
+	}{
+		{name: "constraint", pattern: "/api/users/{id}", n: 4, expected: 4, check: func(key string) bool {
+			ok, _ := Int.Match(strings.TrimPrefix(key, "/api/users/"))
+			return ok
+		}},
+		{name: "matcher", pattern: "/blobs/{sum:hex}", n: 2, expected: 2, check: func(key string) bool {
+			return strings.Trim(strings.TrimPrefix(key, "/blobs/"), hexDigits) == ""
+		}},
+		{name: "static", pattern: "/static", n: 3, expected: 1, check: func(key string) bool {
+			return key == "/static"
+		}},
+		{name: "not stored", pattern: "/api/posts/{id}", n: 3, expected: 0},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tree.Examples(tc.pattern, tc.n)
+
+			if len(got) != tc.expected {
+				t.Fatalf("expected %d examples; got: %v\n", tc.expected, got)
+			}
+
+			for _, key := range got {
+				if !tc.check(key) {
+					t.Errorf("example %s does not satisfy the route\n", key)
+				}
+
+				if fn := tree.Find(key); fn == nil || fn.pattern != tc.pattern {
+					t.Errorf("example %s does not resolve to %s\n", key, tc.pattern)
+				}
+			}
+		})
+	}
+
+	var nilTree *Tree[*Route]
+
+	if got := nilTree.Examples("/static", 1); len(got) != 0 {
+		t.Errorf("expected no examples of a nil tree; got: %v\n", got)
+	}
+}
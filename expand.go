@@ -0,0 +1,69 @@
+package rtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	errMissingParam  = fmt.Errorf("[rtree %s]: missing value of path param", version)
+	errUnknownParam  = fmt.Errorf("[rtree %s]: unknown path param", version)
+	errBadParamValue = fmt.Errorf("[rtree %s]: bad value of path param", version)
+)
+
+// Expand fills the path params of the pattern with the given values,
+// eg. /api/{resource}/{id} with resource="products" and id="1" results
+// in /api/products/1. Every param of the pattern must have a value, and
+// every value must belong to a param of the pattern. Since a value
+// replaces exactly one segment, it must be non-empty without any slash –
+// except for the catch-all params, which could span multiple segments.
+func Expand(pattern string, params map[string]string) (string, error) {
+	return expand(pattern, params, true)
+}
+
+// expand is the main logic of Expand. If it is not strict, the values
+// without a param in the pattern are simply ignored.
+func expand(pattern string, params map[string]string, strict bool) (string, error) {
+	if pattern == "" {
+		return "", ErrKeyIsEmpty
+	}
+
+	if err := checkUrl(pattern); err != nil {
+		return "", err
+	}
+
+	var (
+		segments = strings.Split(pattern, string(slash))
+		names    = make(map[string]struct{})
+	)
+
+	for i, seg := range segments {
+		if !isParamSegment(seg) {
+			segments[i] = unescape(seg)
+			continue
+		}
+
+		name, _, catchAll := parseParam(seg[1 : len(seg)-1])
+
+		value, exists := params[name]
+		if !exists {
+			return "", fmt.Errorf("%w: %s", errMissingParam, name)
+		}
+
+		// Only the value of a catch-all param could span multiple segments.
+		if value == "" || (!catchAll && strings.ContainsRune(value, slash)) {
+			return "", fmt.Errorf("%w: %s=%q", errBadParamValue, name, value)
+		}
+
+		segments[i] = value
+		names[name] = struct{}{}
+	}
+
+	for name := range params {
+		if _, exists := names[name]; !exists && strict {
+			return "", fmt.Errorf("%w: %s", errUnknownParam, name)
+		}
+	}
+
+	return strings.Join(segments, string(slash)), nil
+}
@@ -0,0 +1,85 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	type testCase struct {
+		name     string
+		pattern  string
+		params   map[string]string
+		expected string
+		err      error
+	}
+
+	tt := []testCase{
+		{
+			name:    "error if pattern is empty",
+			pattern: "",
+			err:     ErrKeyIsEmpty,
+		},
+		{
+			name:    "error if pattern has bad syntax",
+			pattern: "/api/{id",
+			err:     errBadPathParamSyntax,
+		},
+		{
+			name:    "error if a param is missing",
+			pattern: "/api/{resource}/{id}",
+			params:  map[string]string{"resource": "products"},
+			err:     errMissingParam,
+		},
+		{
+			name:    "error if there is an unknown param",
+			pattern: "/api/{resource}",
+			params:  map[string]string{"resource": "products", "id": "1"},
+			err:     errUnknownParam,
+		},
+		{
+			name:    "error if a value contains a slash",
+			pattern: "/api/{resource}",
+			params:  map[string]string{"resource": "products/1"},
+			err:     errBadParamValue,
+		},
+		{
+			name:    "error if a value is empty",
+			pattern: "/api/{resource}",
+			params:  map[string]string{"resource": ""},
+			err:     errBadParamValue,
+		},
+		{
+			name:     "static pattern without params",
+			pattern:  "/api/products",
+			params:   nil,
+			expected: "/api/products",
+		},
+		{
+			name:     "params are filled",
+			pattern:  "/api/{resource}/get/{id}",
+			params:   map[string]string{"resource": "products", "id": "1"},
+			expected: "/api/products/get/1",
+		},
+		{
+			name:     "params with matcher are filled",
+			pattern:  "/{lang:lang}/docs",
+			params:   map[string]string{"lang": "en"},
+			expected: "/en/docs",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Expand(tc.pattern, tc.params)
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+
+			if got != tc.expected {
+				t.Errorf("expected: %s; got: %s\n", tc.expected, got)
+			}
+		})
+	}
+}
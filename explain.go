@@ -0,0 +1,91 @@
+package rtree
+
+// StepOutcome describes how the search went on at a visited node.
+type StepOutcome string
+
+const (
+	// OutcomeNoCommonPrefix means the node has nothing in common with the search key.
+	OutcomeNoCommonPrefix StepOutcome = "no-common-prefix"
+	// OutcomePartialMatch means the search key diverged from the key of the node.
+	OutcomePartialMatch StepOutcome = "partial-match"
+	// OutcomeDescend means the node matched, and the search went on among its children.
+	OutcomeDescend StepOutcome = "descend"
+	// OutcomeNotLeaf means the search key ended on a node without stored value.
+	OutcomeNotLeaf StepOutcome = "not-leaf"
+	// OutcomeRejected means the leaf was rejected, eg. by a segment matcher.
+	OutcomeRejected StepOutcome = "rejected"
+	// OutcomeMatched means the leaf is the result of the search.
+	OutcomeMatched StepOutcome = "matched"
+	// OutcomeBudgetExceeded means the search was aborted at the node,
+	// since it ran out of its backtrack budget.
+	OutcomeBudgetExceeded StepOutcome = "budget-exceeded"
+)
+
+// ExplainStep is a single visited node of the search.
+type ExplainStep struct {
+	// NodeKey is the key stored in the visited node.
+	NodeKey string
+	// SearchKey is the remaining part of the search key at the node.
+	SearchKey string
+	// LCP is the length of the longest common prefix of the two keys above.
+	LCP int
+	// Wildcard reports whether the search was inside a path param
+	// when it arrived at the node.
+	Wildcard bool
+	Outcome  StepOutcome
+}
+
+// Explanation is the structured trace of a search.
+type Explanation struct {
+	Key     string
+	Steps   []ExplainStep
+	Matched bool
+	Pattern string
+	Params  map[string]string
+
+	// BudgetExceeded reports whether the search was aborted.
+	BudgetExceeded bool
+}
+
+// Explain conducts the same search as Find – without the default route –
+// and records every visited node, the computed LCPs, the wildcard
+// transitions and where the matching stopped.
+func (t *Tree[T]) Explain(key string) Explanation {
+	exp := Explanation{
+		Key:   key,
+		Steps: make([]ExplainStep, 0),
+	}
+
+	if err := checkTree(t); err != nil {
+		return exp
+	}
+
+	n, params, err := t.lookup(key, &exp, t.resolution)
+
+	exp.BudgetExceeded = err != nil
+
+	if n == nil {
+		return exp
+	}
+
+	exp.Matched = true
+	exp.Pattern = n.value.Pattern()
+	exp.Params = params
+
+	return exp
+}
+
+// Backtracks returns the number of the dead ends of the search: the
+// visited nodes, which the search had to return from without a match.
+func (e Explanation) Backtracks() int {
+	count := 0
+
+	for _, s := range e.Steps {
+		switch s.Outcome {
+		case OutcomeNoCommonPrefix, OutcomePartialMatch, OutcomeNotLeaf, OutcomeRejected:
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,97 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	type testCase struct {
+		name            string
+		key             string
+		expectedMatched bool
+		expectedPattern string
+		expectedSteps   []ExplainStep
+		// expectedBacktracks is the number of the dead ends.
+		expectedBacktracks int
+	}
+
+	tree := New[*Route]()
+
+	for _, r := range []string{"/api/{resource}/get", "/api/products/get"} {
+		if err := tree.Insert(r, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:               "no common prefix with the root",
+			expectedBacktracks: 1,
+			key:                "/foo",
+			expectedMatched:    false,
+			expectedSteps: []ExplainStep{
+				{NodeKey: "/api/", SearchKey: "/foo", LCP: 1, Outcome: OutcomePartialMatch},
+			},
+		},
+		{
+			name:            "static match",
+			key:             "/api/products/get",
+			expectedMatched: true,
+			expectedPattern: "/api/products/get",
+			expectedSteps: []ExplainStep{
+				{NodeKey: "/api/", SearchKey: "/api/products/get", LCP: 5, Outcome: OutcomeDescend},
+				{NodeKey: "products/get", SearchKey: "products/get", LCP: 12, Outcome: OutcomeMatched},
+			},
+		},
+		{
+			name:               "wildcard match after static miss",
+			expectedBacktracks: 1,
+			key:                "/api/users/get",
+			expectedMatched:    true,
+			expectedPattern:    "/api/{resource}/get",
+			expectedSteps: []ExplainStep{
+				{NodeKey: "/api/", SearchKey: "/api/users/get", LCP: 5, Outcome: OutcomeDescend},
+				{NodeKey: "products/get", SearchKey: "users/get", LCP: 0, Outcome: OutcomeNoCommonPrefix},
+				{NodeKey: "{resource}/get", SearchKey: "users/get", LCP: 0, Outcome: OutcomeMatched},
+			},
+		},
+		{
+			name:               "no match at all",
+			expectedBacktracks: 2,
+			key:                "/api/products/list",
+			expectedMatched:    false,
+			expectedSteps: []ExplainStep{
+				{NodeKey: "/api/", SearchKey: "/api/products/list", LCP: 5, Outcome: OutcomeDescend},
+				{NodeKey: "products/get", SearchKey: "products/list", LCP: 9, Outcome: OutcomePartialMatch},
+				{NodeKey: "{resource}/get", SearchKey: "products/list", LCP: 0, Outcome: OutcomePartialMatch},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tree.Explain(tc.key)
+
+			if got.Key != tc.key {
+				t.Errorf("expected key: %s; got: %s\n", tc.key, got.Key)
+			}
+
+			if got.Matched != tc.expectedMatched {
+				t.Errorf("expected matched: %v; got: %v\n", tc.expectedMatched, got.Matched)
+			}
+
+			if got.Pattern != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, got.Pattern)
+			}
+
+			if !reflect.DeepEqual(tc.expectedSteps, got.Steps) {
+				t.Errorf("expected steps: %+v; got: %+v\n", tc.expectedSteps, got.Steps)
+			}
+
+			if b := got.Backtracks(); b != tc.expectedBacktracks {
+				t.Errorf("expected backtracks: %d; got: %d\n", tc.expectedBacktracks, b)
+			}
+		})
+	}
+}
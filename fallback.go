@@ -0,0 +1,33 @@
+package rtree
+
+// WithDefaultRoute sets the key of the leaf that Find should resolve
+// to, in case there was no match for the searched key.
+func WithDefaultRoute[T storeValue](key string) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.setOption("default route", key)
+		t.defaultRoute = key
+	}
+}
+
+// FindWithFallback searches for the given key, and in case of no match
+// it tries the fallback keys one after another, in the given order.
+// It returns the first match, or nil if neither of the keys matched.
+func (t *Tree[T]) FindWithFallback(key string, fallbacks ...string) *FoundNode[T] {
+	if err := checkTree(t); err != nil {
+		return nil
+	}
+
+	return t.observe(key, func(key string) *FoundNode[T] {
+		if fn := t.find(key, t.resolution); fn != nil {
+			return fn
+		}
+
+		for _, fb := range fallbacks {
+			if fn := t.find(fb, t.resolution); fn != nil {
+				return fn
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,150 @@
+package rtree
+
+import "testing"
+
+func getFallbackTree(t *testing.T, opts ...OptionFunc[*Route]) *Tree[*Route] {
+	tree := New(opts...)
+
+	routes := map[string]string{
+		"/api/users/{id}": "users",
+		"/api/fallback":   "fallback",
+		"/404":            "not-found",
+	}
+
+	for k, v := range routes {
+		if err := tree.Insert(k, &Route{name: v}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	return tree
+}
+
+func TestFindWithFallback(t *testing.T) {
+	type testCase struct {
+		name         string
+		getTree      getTreeFn
+		searchKey    string
+		fallbacks    []string
+		expectedName string
+	}
+
+	tt := []testCase{
+		{
+			name:         "cant find, if tree is <nil>",
+			getTree:      func(t *testing.T) *Tree[*Route] { return nil },
+			searchKey:    "/api/users/1",
+			fallbacks:    []string{"/404"},
+			expectedName: "",
+		},
+		{
+			name:         "returns the original match",
+			getTree:      func(t *testing.T) *Tree[*Route] { return getFallbackTree(t) },
+			searchKey:    "/api/users/1",
+			fallbacks:    []string{"/404"},
+			expectedName: "users",
+		},
+		{
+			name:         "returns the first matching fallback",
+			getTree:      func(t *testing.T) *Tree[*Route] { return getFallbackTree(t) },
+			searchKey:    "/api/products/1",
+			fallbacks:    []string{"/api/missing", "/api/fallback", "/404"},
+			expectedName: "fallback",
+		},
+		{
+			name:         "no match, if neither of the fallbacks match",
+			getTree:      func(t *testing.T) *Tree[*Route] { return getFallbackTree(t) },
+			searchKey:    "/api/products/1",
+			fallbacks:    []string{"/api/missing"},
+			expectedName: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := tc.getTree(t)
+
+			node := tree.FindWithFallback(tc.searchKey, tc.fallbacks...)
+
+			if tc.expectedName == "" {
+				if node != nil {
+					t.Error("expected not to find, but got route")
+				}
+				return
+			}
+
+			if node == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := node.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+		})
+	}
+}
+
+func TestFindWithDefaultRoute(t *testing.T) {
+	type testCase struct {
+		name         string
+		getTree      getTreeFn
+		searchKey    string
+		expectedName string
+	}
+
+	tt := []testCase{
+		{
+			name:         "no default route, no match",
+			getTree:      func(t *testing.T) *Tree[*Route] { return getFallbackTree(t) },
+			searchKey:    "/api/products/1",
+			expectedName: "",
+		},
+		{
+			name: "default route, original match",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				return getFallbackTree(t, WithDefaultRoute[*Route]("/404"))
+			},
+			searchKey:    "/api/users/1",
+			expectedName: "users",
+		},
+		{
+			name: "default route, resolves to default",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				return getFallbackTree(t, WithDefaultRoute[*Route]("/404"))
+			},
+			searchKey:    "/api/products/1",
+			expectedName: "not-found",
+		},
+		{
+			name: "not stored default route, no match",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				return getFallbackTree(t, WithDefaultRoute[*Route]("/500"))
+			},
+			searchKey:    "/api/products/1",
+			expectedName: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := tc.getTree(t)
+
+			node := tree.Find(tc.searchKey)
+
+			if tc.expectedName == "" {
+				if node != nil {
+					t.Error("expected not to find, but got route")
+				}
+				return
+			}
+
+			if node == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := node.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package rtree
+
+import (
+	"sort"
+	"strings"
+)
+
+// FindWithFixups is the same as Find, but if the key has no match, it
+// checks whether removing or adding a trailing slash, or fixing the case
+// of the static segments – or both – would produce one. If so, it returns
+// the route of the corrected key, and the corrected key itself, so the
+// router could redirect the client to the canonical path, eg. with 301
+// Moved Permanently. The corrected key is empty, if the key matched as
+// it is – or the default route is returned, since no fix helped.
+//
+// The case of the segments is only fixed, if the tree is case-sensitive,
+// and it takes a scan of the stored routes, so it is only worth on misses.
+func (t *Tree[T]) FindWithFixups(key string) (*FoundNode[T], string) {
+	if err := checkTree(t); err != nil {
+		return nil, ""
+	}
+
+	var fixed string
+
+	fn := t.observe(key, func(key string) *FoundNode[T] {
+		if fn := t.find(key, t.resolution); fn != nil {
+			return fn
+		}
+
+		var fn *FoundNode[T]
+
+		if fn, fixed = t.findFixup(key); fn != nil {
+			return fn
+		}
+
+		return t.findDefault(key, t.resolution)
+	})
+
+	return fn, fixed
+}
+
+// findFixup returns the route of the first corrected key, that has a match,
+// and the corrected key itself. The slash is fixed first, then the case.
+func (t *Tree[T]) findFixup(key string) (*FoundNode[T], string) {
+	if key == "" {
+		return nil, ""
+	}
+
+	candidates := make([]string, 0, 2)
+
+	if len(key) > 1 && key[len(key)-1] == slash {
+		candidates = append(candidates, key[:len(key)-1])
+	} else {
+		candidates = append(candidates, key+string(slash))
+	}
+
+	for _, c := range candidates {
+		if n, params := t.cachedLookup(c, t.resolution); n != nil {
+			return t.newFoundNode(n, params), c
+		}
+	}
+
+	if t.caseInsensitive {
+		return nil, ""
+	}
+
+	leaves := getAllLeafRec(t.root)
+
+	// The first of the routes differing in case only wins.
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].value.pattern < leaves[j].value.pattern
+	})
+
+	for _, c := range append([]string{key}, candidates...) {
+		for _, l := range leaves {
+			fixed, ok := foldMatch(l.value.pattern, c)
+			if !ok || fixed == key {
+				continue
+			}
+
+			// The lookup decides, so the matchers and the precedence are honored.
+			if n, params := t.findNode(fixed); n == l {
+				return t.newFoundNode(n, params), fixed
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// foldMatch matches the key against the pattern, comparing the static
+// segments regardless of their case, and returns the key with the static
+// segments of the pattern. The patterns with params in the middle of
+// their segments are not matched.
+func foldMatch(pattern, key string) (string, bool) {
+	var (
+		pSegments = strings.Split(pattern, string(slash))
+		kSegments = strings.Split(key, string(slash))
+		fixed     = make([]string, 0, len(kSegments))
+	)
+
+	for i, seg := range pSegments {
+		if i >= len(kSegments) {
+			return "", false
+		}
+
+		if isParamSegment(seg) {
+			if isCatchAll(seg) {
+				return strings.Join(append(fixed, kSegments[i:]...), string(slash)), true
+			}
+
+			fixed = append(fixed, kSegments[i])
+			continue
+		}
+
+		if indexUnescaped(seg, curlyStart) != -1 {
+			return "", false
+		}
+
+		static := unescape(seg)
+
+		if !strings.EqualFold(static, kSegments[i]) {
+			return "", false
+		}
+
+		fixed = append(fixed, static)
+	}
+
+	if len(pSegments) != len(kSegments) {
+		return "", false
+	}
+
+	return strings.Join(fixed, string(slash)), true
+}
@@ -0,0 +1,68 @@
+package rtree
+
+import "testing"
+
+func TestFindWithFixups(t *testing.T) {
+	tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+	for _, p := range []string{"/api/Users/{id}", "/api/users", "/docs/{lang:lang}/intro", "/files/{path...}", "/About"} {
+		if err := tree.Insert(p, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []struct {
+		name            string
+		key             string
+		expectedPattern string
+		expectedFixed   string
+	}{
+		{name: "exact match", key: "/api/users", expectedPattern: "/api/users"},
+		{name: "trailing slash removed", key: "/api/users/", expectedPattern: "/api/users", expectedFixed: "/api/users"},
+		{name: "case fixed", key: "/API/USERS", expectedPattern: "/api/users", expectedFixed: "/api/users"},
+		{name: "case fixed with params", key: "/api/users/AbC", expectedPattern: "/api/Users/{id}", expectedFixed: "/api/Users/AbC"},
+		{name: "case and slash fixed", key: "/about/", expectedPattern: "/About", expectedFixed: "/About"},
+		{name: "catch-all keeps its value", key: "/FILES/A/b", expectedPattern: "/files/{path...}", expectedFixed: "/files/A/b"},
+		{name: "matcher honored", key: "/DOCS/en/intro", expectedPattern: "/docs/{lang:lang}/intro", expectedFixed: "/docs/en/intro"},
+		{name: "matcher rejects", key: "/DOCS/xx/intro"},
+		{name: "no fix at all", key: "/nothing"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn, fixed := tree.FindWithFixups(tc.key)
+
+			if fixed != tc.expectedFixed {
+				t.Errorf("expected fixed key: %q; got: %q\n", tc.expectedFixed, fixed)
+			}
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Errorf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil || fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %v\n", tc.expectedPattern, fn)
+			}
+		})
+	}
+
+	// A case-insensitive tree needs no case fixes.
+	folded := New(WithCaseInsensitive[*Route]())
+
+	if err := folded.Insert("/api/users", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn, fixed := folded.FindWithFixups("/API/Users"); fn == nil || fixed != "" {
+		t.Errorf("expected match without fix; got: %v, %q\n", fn, fixed)
+	}
+
+	var nilTree *Tree[*Route]
+
+	if fn, fixed := nilTree.FindWithFixups("/api"); fn != nil || fixed != "" {
+		t.Errorf("expected no match of a nil tree; got: %v, %q\n", fn, fixed)
+	}
+}
@@ -0,0 +1,542 @@
+package rtree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+var (
+	errFlatUnsupported = fmt.Errorf("[rtree %s]: not supported by the flat format", version)
+	errBadFlatFile     = fmt.Errorf("[rtree %s]: bad flat file", version)
+)
+
+const (
+	flatMagic   = "RTFL"
+	flatVersion = 1
+
+	// flatCaseInsensitive is the flag of the case-insensitive trees.
+	flatCaseInsensitive = 1 << 0
+	// flatObjectKeys is the flag of the trees, whose keys are not URLs,
+	// so their percent-encodings are not normalized.
+	flatObjectKeys = 1 << 1
+
+	// The sizes of the header and the records, in bytes.
+	flatHeaderSize = 24
+	flatNodeSize   = 24
+	flatLeafSize   = 24
+	flatParamSize  = 16
+
+	// flatNoLeaf is the leaf index of the inner nodes.
+	flatNoLeaf = ^uint32(0)
+	// flatHasParam is the flag of the nodes, whose key has a param.
+	flatHasParam = 1 << 0
+)
+
+// The flat format – all the numbers are little endian uint32s:
+//
+//	header: magic, version and flags (as two uint16s), and the number
+//	        of the nodes, the leaves, the params and the string bytes
+//	nodes:  key offset, key length, first child, number of the children,
+//	        leaf index and flags – the children of a node are adjacent,
+//	        since the nodes are written in breadth-first order
+//	leaves: pattern offset, pattern length, value offset, value length,
+//	        first param and number of the params
+//	params: name offset, name length, position and whether it is a catch-all
+//	strings: the bytes of all the keys, patterns, values and param names
+
+// WriteFlat writes the tree to w in a flat binary format, that could be
+// memory-mapped by OpenFlat, so a very large – eg. classification – table
+// is searched without loading it on the heap. The values are encoded by the
+// given function. Only the patterns and the values are written, and not the
+// metadata of the routes, so the routes, whose match depends on them – the
+// disabled, windowed, guarded, redirected and split ones –, could not be
+// written. Neither could the trees with segment matchers, custom delimiters,
+// topic syntax, param policies, non-empty params, the CatchAllFirst
+// resolution order or a trailing slash policy other than the strict one.
+func (t *Tree[T]) WriteFlat(w io.Writer, encode func(T) ([]byte, error)) error {
+	if t == nil {
+		return ErrTreeIsNil
+	}
+
+	if err := t.checkFlat(); err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	fw := &flatWriter{}
+
+	if err := writeFlatNodes(fw, t, encode); err != nil {
+		return err
+	}
+
+	var flags uint16
+
+	if t.caseInsensitive {
+		flags |= flatCaseInsensitive
+	}
+
+	if t.objectKeys {
+		flags |= flatObjectKeys
+	}
+
+	return fw.writeTo(w, flags)
+}
+
+// checkFlat checks, that the lookups of the tree could be done on its flat form.
+func (t *Tree[T]) checkFlat() error {
+	switch {
+	case t.delimiters != nil:
+		return fmt.Errorf("%w: custom delimiters", errFlatUnsupported)
+	case t.syntax.isTopic():
+		return fmt.Errorf("%w: topic syntax", errFlatUnsupported)
+	case t.resolution == CatchAllFirst:
+		return fmt.Errorf("%w: CatchAllFirst resolution order", errFlatUnsupported)
+	case t.trailingSlash != TrailingSlashStrict:
+		return fmt.Errorf("%w: trailing slash policy", errFlatUnsupported)
+	case t.paramPolicy != nil || len(t.paramPolicies) > 0:
+		return fmt.Errorf("%w: param policies", errFlatUnsupported)
+	case t.nonEmptyParams:
+		return fmt.Errorf("%w: non-empty params", errFlatUnsupported)
+	}
+
+	return nil
+}
+
+// checkFlatLeaf checks, that the route could be matched on the flat form,
+// which has neither matchers nor metadata: every stored route is matched.
+func (t *Tree[T]) checkFlatLeaf(nv *NodeValue[T]) error {
+	var unsupported string
+
+	switch {
+	case len(nv.meta.constraints) > 0 || hasMatcher(nv.params):
+		unsupported = "segment matcher"
+	case nv.meta.disabled:
+		unsupported = "disabled route"
+	case nv.meta.window != nil:
+		unsupported = "windowed route"
+	case nv.meta.guard != nil:
+		unsupported = "guard"
+	case nv.meta.redirect != nil:
+		unsupported = "redirect"
+	case t.resolve(nv).split != nil:
+		unsupported = "traffic split"
+	default:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s of %s", errFlatUnsupported, unsupported, nv.Pattern())
+}
+
+type flatWriter struct {
+	nodes   []uint32
+	leaves  []uint32
+	params  []uint32
+	strings []byte
+}
+
+// addString appends the string to the strings, and returns its offset.
+func (fw *flatWriter) addString(s string) uint32 {
+	off := uint32(len(fw.strings))
+
+	fw.strings = append(fw.strings, s...)
+
+	return off
+}
+
+func writeFlatNodes[T storeValue](fw *flatWriter, t *Tree[T], encode func(T) ([]byte, error)) error {
+	if t.root == nil {
+		return nil
+	}
+
+	// The nodes are written in breadth-first order, so the children of
+	// a node are adjacent, and only the first of them has to be stored.
+	var (
+		queue = []*Node[T]{t.root}
+		next  = uint32(1)
+	)
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		leaf := flatNoLeaf
+
+		if n.value != nil {
+			idx, err := writeFlatLeaf(fw, t, n.value, encode)
+			if err != nil {
+				return err
+			}
+
+			leaf = idx
+		}
+
+		var flags uint32
+		if n.hasParam() {
+			flags |= flatHasParam
+		}
+
+		fw.nodes = append(fw.nodes,
+			fw.addString(n.key), uint32(len(n.key)),
+			next, uint32(len(n.children)),
+			leaf, flags,
+		)
+
+		next += uint32(len(n.children))
+		queue = append(queue, n.children...)
+	}
+
+	return nil
+}
+
+func writeFlatLeaf[T storeValue](fw *flatWriter, t *Tree[T], nv *NodeValue[T], encode func(T) ([]byte, error)) (uint32, error) {
+	if err := t.checkFlatLeaf(nv); err != nil {
+		return 0, err
+	}
+
+	value, err := encode(t.resolve(nv).value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: value of %s: %v", errFlatUnsupported, nv.Pattern(), err)
+	}
+
+	var (
+		idx        = uint32(len(fw.leaves) / 6)
+		firstParam = uint32(len(fw.params) / 4)
+	)
+
+	for _, pi := range nv.params {
+		var catchAll uint32
+		if pi.catchAll {
+			catchAll = 1
+		}
+
+		fw.params = append(fw.params, fw.addString(pi.key), uint32(len(pi.key)), uint32(pi.pos), catchAll)
+	}
+
+	patternOff := fw.addString(nv.pattern)
+	valueOff := fw.addString(string(value))
+
+	fw.leaves = append(fw.leaves,
+		patternOff, uint32(len(nv.pattern)),
+		valueOff, uint32(len(value)),
+		firstParam, uint32(len(nv.params)),
+	)
+
+	return idx, nil
+}
+
+func (fw *flatWriter) writeTo(w io.Writer, flags uint16) error {
+	header := make([]byte, flatHeaderSize)
+
+	copy(header, flatMagic)
+	binary.LittleEndian.PutUint16(header[4:], flatVersion)
+	binary.LittleEndian.PutUint16(header[6:], flags)
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(fw.nodes)/6))
+	binary.LittleEndian.PutUint32(header[12:], uint32(len(fw.leaves)/6))
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(fw.params)/4))
+	binary.LittleEndian.PutUint32(header[20:], uint32(len(fw.strings)))
+
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	for _, section := range [][]uint32{fw.nodes, fw.leaves, fw.params} {
+		var b [4]byte
+
+		for _, v := range section {
+			binary.LittleEndian.PutUint32(b[:], v)
+
+			if _, err := bw.Write(b[:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := bw.Write(fw.strings); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// FlatTree is a read-only tree in the flat format written by WriteFlat.
+// Its lookups work on the mapped file directly, without loading it on
+// the heap. It is safe for concurrent use, until it is closed.
+type FlatTree struct {
+	data  []byte
+	close func() error
+
+	caseInsensitive bool
+	objectKeys      bool
+
+	nodeCount  int
+	leafCount  int
+	paramCount int
+
+	// The offsets of the sections in the data.
+	nodesOff   int
+	leavesOff  int
+	paramsOff  int
+	stringsOff int
+}
+
+// FlatMatch is the result of a lookup in a FlatTree. It is
+// a copy, so it is still valid after the tree is closed.
+type FlatMatch struct {
+	Pattern string
+	Value   []byte
+	Params  map[string]string
+}
+
+// OpenFlat memory-maps the flat tree written to the given file.
+// The tree must be closed, once it is not used anymore.
+func OpenFlat(path string) (*FlatTree, error) {
+	data, closeFn, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ft, err := newFlatTree(data)
+	if err != nil {
+		closeFn()
+		return nil, err
+	}
+
+	ft.close = closeFn
+
+	return ft, nil
+}
+
+// newFlatTree validates the header and the sizes of the given flat data.
+func newFlatTree(data []byte) (*FlatTree, error) {
+	if len(data) < flatHeaderSize || string(data[:4]) != flatMagic {
+		return nil, errBadFlatFile
+	}
+
+	if v := binary.LittleEndian.Uint16(data[4:]); v != flatVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", errBadFlatFile, v)
+	}
+
+	flags := binary.LittleEndian.Uint16(data[6:])
+
+	ft := &FlatTree{
+		data:            data,
+		caseInsensitive: flags&flatCaseInsensitive != 0,
+		objectKeys:      flags&flatObjectKeys != 0,
+		nodeCount:       int(binary.LittleEndian.Uint32(data[8:])),
+		leafCount:       int(binary.LittleEndian.Uint32(data[12:])),
+		paramCount:      int(binary.LittleEndian.Uint32(data[16:])),
+	}
+
+	stringsSize := int(binary.LittleEndian.Uint32(data[20:]))
+
+	ft.nodesOff = flatHeaderSize
+	ft.leavesOff = ft.nodesOff + ft.nodeCount*flatNodeSize
+	ft.paramsOff = ft.leavesOff + ft.leafCount*flatLeafSize
+	ft.stringsOff = ft.paramsOff + ft.paramCount*flatParamSize
+
+	if ft.stringsOff+stringsSize != len(data) {
+		return nil, fmt.Errorf("%w: size mismatch", errBadFlatFile)
+	}
+
+	// Every reference must point inside of the data, so
+	// the lookups never have to check the bounds again.
+	if err := ft.validate(stringsSize); err != nil {
+		return nil, err
+	}
+
+	return ft, nil
+}
+
+func (ft *FlatTree) validate(stringsSize int) error {
+	inStrings := func(off, l uint32) bool {
+		return uint64(off)+uint64(l) <= uint64(stringsSize)
+	}
+
+	for i := 0; i < ft.nodeCount; i++ {
+		keyOff, keyLen, first, count, leaf, _ := ft.node(i)
+
+		if !inStrings(keyOff, keyLen) || uint64(first)+uint64(count) > uint64(ft.nodeCount) {
+			return fmt.Errorf("%w: node %d", errBadFlatFile, i)
+		}
+
+		if leaf != flatNoLeaf && int(leaf) >= ft.leafCount {
+			return fmt.Errorf("%w: leaf of node %d", errBadFlatFile, i)
+		}
+
+		// The children always come after their parent, so the
+		// lookups could never run into a cycle.
+		if count > 0 && int(first) <= i {
+			return fmt.Errorf("%w: children of node %d", errBadFlatFile, i)
+		}
+	}
+
+	for i := 0; i < ft.leafCount; i++ {
+		off := ft.leavesOff + i*flatLeafSize
+
+		var (
+			patternOff, patternLen = ft.u32(off), ft.u32(off + 4)
+			valueOff, valueLen     = ft.u32(off + 8), ft.u32(off + 12)
+			first, count           = ft.u32(off + 16), ft.u32(off + 20)
+		)
+
+		if !inStrings(patternOff, patternLen) || !inStrings(valueOff, valueLen) || uint64(first)+uint64(count) > uint64(ft.paramCount) {
+			return fmt.Errorf("%w: leaf %d", errBadFlatFile, i)
+		}
+	}
+
+	for i := 0; i < ft.paramCount; i++ {
+		off := ft.paramsOff + i*flatParamSize
+
+		if !inStrings(ft.u32(off), ft.u32(off+4)) {
+			return fmt.Errorf("%w: param %d", errBadFlatFile, i)
+		}
+	}
+
+	return nil
+}
+
+// Close unmaps the file of the tree.
+func (ft *FlatTree) Close() error {
+	if ft.close == nil {
+		return nil
+	}
+
+	err := ft.close()
+
+	ft.close = nil
+	ft.data = nil
+
+	return err
+}
+
+// Len returns the number of the stored routes.
+func (ft *FlatTree) Len() int {
+	return ft.leafCount
+}
+
+func (ft *FlatTree) u32(off int) uint32 {
+	return binary.LittleEndian.Uint32(ft.data[off:])
+}
+
+// str returns the string at the given offset of the strings, without
+// copying it, so it must not outlive the mapping of the file.
+func (ft *FlatTree) str(off, l uint32) string {
+	if l == 0 {
+		return ""
+	}
+
+	return unsafe.String(&ft.data[ft.stringsOff+int(off)], int(l))
+}
+
+func (ft *FlatTree) node(i int) (keyOff, keyLen, first, count, leaf, flags uint32) {
+	off := ft.nodesOff + i*flatNodeSize
+
+	return ft.u32(off), ft.u32(off + 4), ft.u32(off + 8), ft.u32(off + 12), ft.u32(off + 16), ft.u32(off + 20)
+}
+
+// Find searches for the key the same way as the Find of the tree it was
+// written from – apart from its default route –, and returns nil if there
+// is no match.
+func (ft *FlatTree) Find(key string) *FlatMatch {
+	if ft == nil || ft.data == nil || ft.nodeCount == 0 || key == "" {
+		return nil
+	}
+
+	// Just like in the tree, only the static parts are compared
+	// by the normalized key, the params are matched in the key.
+	searchKey := key
+	if !ft.objectKeys {
+		searchKey = normalizePercent(searchKey)
+	}
+
+	if ft.caseInsensitive {
+		searchKey = asciiLower(searchKey)
+	}
+
+	leaf := ft.findRec(0, escapeKey(searchKey), false)
+	if leaf == flatNoLeaf {
+		return nil
+	}
+
+	return ft.match(int(leaf), key)
+}
+
+// findRec is the findRec of the flat nodes. Since the flat trees have no
+// segment matchers, the first leaf found is the match. It returns the
+// index of the found leaf, or flatNoLeaf.
+func (ft *FlatTree) findRec(i int, key string, isWildcard bool) uint32 {
+	keyOff, keyLen, first, count, leaf, flags := ft.node(i)
+
+	nodeKey := ft.str(keyOff, keyLen)
+
+	var rem string
+
+	if isWildcard || flags&flatHasParam != 0 {
+		// The flat nodes are not compiled, so their keys
+		// are interpreted by the reference matcher.
+		nodeOffset, keyOffset, stillWildcard := getOffsets(nodeKey, key, isWildcard)
+		if nodeOffset != len(nodeKey) {
+			return flatNoLeaf
+		}
+
+		rem, isWildcard = key[keyOffset:], stillWildcard
+	} else {
+		lcp := longestCommonPrefix(nodeKey, key)
+
+		if (lcp == 0 && nodeKey != "") || lcp < len(nodeKey) {
+			return flatNoLeaf
+		}
+
+		rem = key[lcp:]
+	}
+
+	if rem == "" {
+		return leaf
+	}
+
+	for c := first; c < first+count; c++ {
+		if found := ft.findRec(int(c), rem, isWildcard); found != flatNoLeaf {
+			return found
+		}
+	}
+
+	return flatNoLeaf
+}
+
+// match copies the found leaf, and matches its params in the key.
+func (ft *FlatTree) match(i int, key string) *FlatMatch {
+	off := ft.leavesOff + i*flatLeafSize
+
+	var (
+		pattern = ft.str(ft.u32(off), ft.u32(off+4))
+		value   = ft.str(ft.u32(off+8), ft.u32(off+12))
+		first   = int(ft.u32(off + 16))
+		count   = int(ft.u32(off + 20))
+	)
+
+	params := make([]paramInfo, count)
+
+	for p := 0; p < count; p++ {
+		pOff := ft.paramsOff + (first+p)*flatParamSize
+
+		params[p] = paramInfo{
+			key:      strings.Clone(ft.str(ft.u32(pOff), ft.u32(pOff+4))),
+			pos:      int(ft.u32(pOff + 8)),
+			catchAll: ft.u32(pOff+12) != 0,
+		}
+	}
+
+	return &FlatMatch{
+		Pattern: strings.Clone(pattern),
+		Value:   []byte(value),
+		Params:  matchParams(params, key),
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package rtree
+
+import "os"
+
+// mapFile reads the given file, since it could not be memory-mapped
+// on this platform, so the flat tree is loaded on the heap after all.
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return nil }, nil
+}
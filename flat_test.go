@@ -0,0 +1,270 @@
+package rtree
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func encodeFlatString(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+// writeFlatFile writes the tree to a temporary file, and opens it.
+func writeFlatFile(t *testing.T, tree *Tree[string]) *FlatTree {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	if err := tree.WriteFlat(&buf, encodeFlatString); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "routes.flat")
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	ft, err := OpenFlat(path)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	t.Cleanup(func() { ft.Close() })
+
+	return ft
+}
+
+// TestFlatTreeEquivalence checks, that the flat tree gives the
+// same matches, as the tree it was written from.
+func TestFlatTreeEquivalence(t *testing.T) {
+	patterns := []string{
+		"/", "/api", "/api/users", "/api/users/{id}", "/api/users/{id}/posts",
+		"/api/{version}/status", "/static/{path...}", "/static/css", "/svc/orders",
+		"/svc/order", "/legacy/\\{id\\}", "/a/{b}/c/{d}", "/a/{b}/e",
+	}
+
+	keys := []string{
+		"/", "/a", "/api", "/api/", "/api/x", "/api/users", "/API/Users", "/api/users/1",
+		"/api/users/1/posts", "/api/users/1/posts/2", "/api/v1/status", "/api/v1/statusx",
+		"/static/css", "/static/css/site.css", "/static/a/b/c", "/svc/orders", "/svc/order",
+		"/svc/ord", "/legacy/{id}", "/legacy/1", "/a/1/c/2", "/a/1/e", "/a/1/c", "/x/y", "",
+	}
+
+	for _, opts := range [][]OptionFunc[string]{
+		nil,
+		{WithCaseInsensitive[string]()},
+	} {
+		tree := New(opts...)
+
+		for _, p := range patterns {
+			if err := tree.Insert(p, "value of "+p); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		ft := writeFlatFile(t, tree)
+
+		if ft.Len() != len(patterns) {
+			t.Errorf("expected length: %d; got: %d\n", len(patterns), ft.Len())
+		}
+
+		for _, key := range keys {
+			var (
+				expected = tree.Find(key)
+				got      = ft.Find(key)
+			)
+
+			if (expected == nil) != (got == nil) {
+				t.Errorf("%s: expected: %v; got: %v\n", key, expected, got)
+				continue
+			}
+
+			if expected == nil {
+				continue
+			}
+
+			if got.Pattern != expected.GetPattern() {
+				t.Errorf("%s: expected pattern: %s; got: %s\n", key, expected.GetPattern(), got.Pattern)
+			}
+
+			if string(got.Value) != expected.GetValue() {
+				t.Errorf("%s: expected value: %s; got: %s\n", key, expected.GetValue(), got.Value)
+			}
+
+			if !reflect.DeepEqual(got.Params, map[string]string(expected.GetParams())) {
+				t.Errorf("%s: expected params: %v; got: %v\n", key, expected.GetParams(), got.Params)
+			}
+		}
+	}
+}
+
+func TestFlatTreeAfterClose(t *testing.T) {
+	tree := New[string]()
+
+	if err := tree.Insert("/api/users/{id}", "users"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	ft := writeFlatFile(t, tree)
+
+	match := ft.Find("/api/users/1")
+
+	if err := ft.Close(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if match == nil || match.Pattern != "/api/users/{id}" || match.Params["id"] != "1" {
+		t.Errorf("expected the match to outlive the tree; got: %v\n", match)
+	}
+
+	if got := ft.Find("/api/users/1"); got != nil {
+		t.Errorf("expected no match after close; got: %v\n", got)
+	}
+}
+
+func TestWriteFlatUnsupported(t *testing.T) {
+	type testCase struct {
+		name      string
+		opts      []OptionFunc[string]
+		pattern   string
+		routeOpts []RouteOption
+		prepare   func(tree *Tree[string]) error
+	}
+
+	digits := SegmentMatcherFunc(func(s string) (bool, string) { return true, s })
+
+	guard, err := ParseGuard("header.beta")
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tt := []testCase{
+		{
+			name:    "segment matcher",
+			opts:    []OptionFunc[string]{WithSegmentMatcher[string]("digits", digits)},
+			pattern: "/users/{id:digits}",
+		},
+		{
+			name:    "custom delimiters",
+			opts:    []OptionFunc[string]{WithDelimiters[string](Delimiters{Segment: '.', ParamStart: '{', ParamEnd: '}'})},
+			pattern: "orders.{region}",
+		},
+		{
+			name:    "topic syntax",
+			opts:    []OptionFunc[string]{WithPatternSyntax[string](SyntaxMQTT)},
+			pattern: "sport/+/player",
+		},
+		{
+			name:    "catch-all first",
+			opts:    []OptionFunc[string]{WithResolutionOrder[string](CatchAllFirst)},
+			pattern: "/files/{path...}",
+		},
+		{
+			name:    "trailing slash policy",
+			opts:    []OptionFunc[string]{WithTrailingSlash[string](TrailingSlashIgnore)},
+			pattern: "/users",
+		},
+		{
+			name:    "param policy",
+			opts:    []OptionFunc[string]{WithParamPolicyFor[string]("id", 8, nil)},
+			pattern: "/users/{id}",
+		},
+		{
+			name:    "non-empty params",
+			opts:    []OptionFunc[string]{WithNonEmptyParams[string]()},
+			pattern: "/users/{id}",
+		},
+		{
+			name:    "disabled route",
+			pattern: "/users",
+			prepare: func(tree *Tree[string]) error { return tree.Disable("/users") },
+		},
+		{
+			name:    "windowed route",
+			pattern: "/users",
+			prepare: func(tree *Tree[string]) error {
+				return tree.InsertWindowed("/window", "value", time.Now(), time.Now().Add(time.Hour))
+			},
+		},
+		{
+			name:      "guarded route",
+			pattern:   "/users",
+			routeOpts: []RouteOption{WithGuard(guard)},
+		},
+		{
+			name:    "redirect",
+			pattern: "/users",
+			prepare: func(tree *Tree[string]) error {
+				return tree.InsertRedirect("/old", "/users", http.StatusMovedPermanently)
+			},
+		},
+		{
+			name:    "traffic split",
+			pattern: "/users",
+			prepare: func(tree *Tree[string]) error {
+				return tree.InsertSplit("/split", "primary", 90, "canary", 10)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			if err := tree.Insert(tc.pattern, "value", tc.routeOpts...); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if tc.prepare != nil {
+				if err := tc.prepare(tree); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			var buf bytes.Buffer
+
+			if err := tree.WriteFlat(&buf, encodeFlatString); !errors.Is(err, errFlatUnsupported) {
+				t.Errorf("expected error: %v; got: %v\n", errFlatUnsupported, err)
+			}
+		})
+	}
+}
+
+func TestOpenFlatBadFile(t *testing.T) {
+	tree := New[string]()
+
+	if err := tree.Insert("/api", "api"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := tree.WriteFlat(&buf, encodeFlatString); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	dir := t.TempDir()
+
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("XXXX"), buf.Bytes()[4:]...),
+		"truncated": buf.Bytes()[:buf.Len()-1],
+	} {
+		path := filepath.Join(dir, name)
+
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		if _, err := OpenFlat(path); !errors.Is(err, errBadFlatFile) {
+			t.Errorf("%s: expected error: %v; got: %v\n", name, errBadFlatFile, err)
+		}
+	}
+}
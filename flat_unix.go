@@ -0,0 +1,34 @@
+//go:build unix
+
+package rtree
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile memory-maps the given file read-only.
+func mapFile(path string) ([]byte, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// An empty file could not be mapped, but it is not a flat tree either.
+	if fi.Size() == 0 {
+		return nil, nil, errBadFlatFile
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
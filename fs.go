@@ -0,0 +1,301 @@
+package rtree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var errIsDirectory = errors.New("is a directory")
+
+// RouteInfo is the metadata of a stored route, as it is
+// served by the file system returned by FS.
+type RouteInfo struct {
+	ID          uint64            `json:"id"`
+	Pattern     string            `json:"pattern"`
+	Description string            `json:"description,omitempty"`
+	Params      []ParamInfo       `json:"params"`
+	Source      string            `json:"source,omitempty"`
+	Protected   bool              `json:"protected,omitempty"`
+	Disabled    bool              `json:"disabled,omitempty"`
+	AliasOf     uint64            `json:"aliasOf,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Timeout     time.Duration     `json:"timeout,omitempty"`
+	Retry       *RetryPolicy      `json:"retry,omitempty"`
+	Sunset      *time.Time        `json:"sunset,omitempty"`
+}
+
+// FS returns the routes of the tree as a read-only file system, so the
+// generic tools – eg. fs.WalkDir or fs.Glob – could be used for audits.
+// The segments of the patterns are the directories and the files, eg.
+// the route /api/users/{id} is opened as api/users/{id}. Reading a
+// route gives its RouteInfo in JSON, while the Sys method of its
+// FileInfo returns the *RouteInfo itself.
+//
+// A pattern that has routes below it – eg. /api next to /api/users –
+// is a directory, whose FileInfo still holds the RouteInfo. The root
+// route is the „.” directory. The segments are split by the delimiter of
+// the tree, see WithDelimiters. The patterns with empty segments – or
+// with slashes inside of their segments – could not be opened. Every
+// Open sees the routes stored at the time of it.
+func (t *Tree[T]) FS() fs.FS {
+	return routeFS[T]{tree: t}
+}
+
+type routeFS[T storeValue] struct {
+	tree *Tree[T]
+}
+
+// fsEntry is a single file or directory of the route file system.
+type fsEntry struct {
+	route    *RouteInfo
+	children map[string]struct{}
+}
+
+func (fsys routeFS[T]) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entries := fsys.entries()
+
+	e, exists := entries[name]
+	if !exists {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	info := &fsInfo{
+		name:  path.Base(name),
+		route: e.route,
+		dir:   name == "." || len(e.children) > 0,
+	}
+
+	if info.dir {
+		return &fsDir{info: info, entries: dirEntries(entries, name, e)}, nil
+	}
+
+	data, err := json.Marshal(e.route)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	info.size = int64(len(data))
+
+	return &fsFile{info: info, r: bytes.NewReader(data)}, nil
+}
+
+// entries indexes the stored routes by their paths in the file system.
+func (fsys routeFS[T]) entries() map[string]*fsEntry {
+	entries := map[string]*fsEntry{
+		".": {},
+	}
+
+	t := fsys.tree
+
+	if checkTree(t) != nil {
+		return entries
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	d := t.delims()
+
+	for _, l := range getAllLeafRec(t.root) {
+		pattern := strings.TrimPrefix(l.value.Pattern(), string(d.Segment))
+
+		// A slash inside of a segment would be a directory of its own.
+		if d.Segment != slash && strings.IndexByte(pattern, slash) != -1 {
+			continue
+		}
+
+		name := strings.Join(d.split(pattern), string(slash))
+		if name == "" {
+			name = "."
+		}
+
+		if !fs.ValidPath(name) {
+			continue
+		}
+
+		e := fsEntryOf(entries, name)
+		e.route = t.routeInfo(l.value)
+
+		// The ancestors are the directories of the route.
+		for name != "." {
+			dir := path.Dir(name)
+
+			parent := fsEntryOf(entries, dir)
+			if parent.children == nil {
+				parent.children = make(map[string]struct{})
+			}
+			parent.children[path.Base(name)] = struct{}{}
+
+			name = dir
+		}
+	}
+
+	return entries
+}
+
+func fsEntryOf(entries map[string]*fsEntry, name string) *fsEntry {
+	e, exists := entries[name]
+	if !exists {
+		e = &fsEntry{}
+		entries[name] = e
+	}
+
+	return e
+}
+
+// routeInfo returns the metadata of the given route.
+// The caller must hold the read lock.
+func (t *Tree[T]) routeInfo(nv *NodeValue[T]) *RouteInfo {
+	info := &RouteInfo{
+		ID:          nv.id,
+		Pattern:     nv.Pattern(),
+		Description: nv.meta.description,
+		Params:      nv.ParamInfos(),
+		Source:      nv.meta.source,
+		Protected:   nv.meta.protected,
+		Disabled:    nv.meta.disabled,
+		AliasOf:     nv.aliasOf,
+		Timeout:     nv.meta.timeout,
+		Retry:       copyRetryPolicy(nv.meta.retry),
+	}
+
+	if !nv.meta.sunset.IsZero() {
+		sunset := nv.meta.sunset
+		info.Sunset = &sunset
+	}
+
+	if len(nv.meta.labels) > 0 {
+		info.Labels = make(map[string]string, len(nv.meta.labels))
+
+		for k, v := range nv.meta.labels {
+			info.Labels[k] = v
+		}
+	}
+
+	return info
+}
+
+// dirEntries returns the sorted entries of the given directory.
+func dirEntries(entries map[string]*fsEntry, name string, dir *fsEntry) []fs.DirEntry {
+	names := make([]string, 0, len(dir.children))
+
+	for child := range dir.children {
+		names = append(names, child)
+	}
+
+	sort.Strings(names)
+
+	list := make([]fs.DirEntry, 0, len(names))
+
+	for _, child := range names {
+		childPath := path.Join(name, child)
+
+		var (
+			e    = entries[childPath]
+			info = &fsInfo{
+				name:  child,
+				route: e.route,
+				dir:   len(e.children) > 0,
+			}
+		)
+
+		if !info.dir {
+			// The size must be the same, as the one reported by Stat.
+			if data, err := json.Marshal(e.route); err == nil {
+				info.size = int64(len(data))
+			}
+		}
+
+		list = append(list, fs.FileInfoToDirEntry(info))
+	}
+
+	return list
+}
+
+// fsInfo is the fs.FileInfo of a route or a directory.
+type fsInfo struct {
+	name  string
+	size  int64
+	dir   bool
+	route *RouteInfo
+}
+
+func (fi *fsInfo) Name() string       { return fi.name }
+func (fi *fsInfo) Size() int64        { return fi.size }
+func (fi *fsInfo) ModTime() time.Time { return time.Time{} }
+func (fi *fsInfo) IsDir() bool        { return fi.dir }
+
+// Sys returns the *RouteInfo of the route, or nil for
+// the directories, which are not routes themselves.
+func (fi *fsInfo) Sys() any {
+	if fi.route == nil {
+		return nil
+	}
+
+	return fi.route
+}
+
+func (fi *fsInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
+
+// fsFile is an opened route.
+type fsFile struct {
+	info *fsInfo
+	r    *bytes.Reader
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *fsFile) Read(b []byte) (int, error) { return f.r.Read(b) }
+func (f *fsFile) Close() error               { return nil }
+
+// fsDir is an opened directory.
+type fsDir struct {
+	info    *fsInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *fsDir) Close() error               { return nil }
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errIsDirectory}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *fsDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	rem := d.entries[d.offset:]
+
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return rem, nil
+	}
+
+	if len(rem) == 0 {
+		return nil, io.EOF
+	}
+
+	if count > len(rem) {
+		count = len(rem)
+	}
+
+	d.offset += count
+
+	return rem[:count], nil
+}
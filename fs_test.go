@@ -0,0 +1,103 @@
+package rtree
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFS(t *testing.T) {
+	tree := New[*Route]()
+
+	routes := []string{
+		"/",
+		"/api",
+		"/api/users",
+		"/api/users/{id}",
+		"/api/users/{id}/posts",
+		"/files/{path...}",
+	}
+
+	for _, key := range routes {
+		if err := tree.Insert(key, &Route{}, WithSource("routes.yaml")); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	fsys := tree.FS()
+
+	if err := fstest.TestFS(fsys, "api/users/{id}/posts", "files/{path...}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	walked := make([]string, 0)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		walked = append(walked, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	expected := []string{".", "api", "api/users", "api/users/{id}", "api/users/{id}/posts", "files", "files/{path...}"}
+
+	if !reflect.DeepEqual(walked, expected) {
+		t.Errorf("expected: %v; got: %v\n", expected, walked)
+	}
+
+	data, err := fs.ReadFile(fsys, "api/users/{id}/posts")
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var info RouteInfo
+
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if info.Pattern != "/api/users/{id}/posts" || info.Source != "routes.yaml" || len(info.Params) != 1 {
+		t.Errorf("unexpected route info: %+v\n", info)
+	}
+
+	// A route with routes below it is a directory, but it holds its metadata.
+	st, err := fs.Stat(fsys, "api/users/{id}")
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if ri, ok := st.Sys().(*RouteInfo); !st.IsDir() || !ok || ri.Pattern != "/api/users/{id}" {
+		t.Errorf("expected directory with route info; got: %v, %v\n", st.IsDir(), st.Sys())
+	}
+
+	// The files directory is not a route itself.
+	if st, err := fs.Stat(fsys, "files"); err != nil || st.Sys() != nil {
+		t.Errorf("expected directory without route info; got: %v, %v\n", st, err)
+	}
+
+	for _, name := range []string{"/api/users", "api/groups", "api/users/"} {
+		if _, err := fsys.Open(name); err == nil {
+			t.Errorf("expected error opening %q\n", name)
+		}
+	}
+
+	if _, err := fsys.Open("api/groups"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected error: %v; got: %v\n", fs.ErrNotExist, err)
+	}
+}
+
+func TestFSEmptyTree(t *testing.T) {
+	fsys := New[*Route]().FS()
+
+	if err := fstest.TestFS(fsys); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+}
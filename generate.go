@@ -0,0 +1,169 @@
+package rtree
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// maxGenerateAttempts is the number of the random keys tried for a route
+// with params, before it is given up – eg. since it is shadowed, or its
+// matchers accept none of the generated values.
+const maxGenerateAttempts = 64
+
+const (
+	lowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	hexDigits    = "0123456789abcdef"
+)
+
+// GenerateRequests returns n concrete keys, that exercise the stored routes
+// in a round-robin fashion, so every route gets a key if n is large enough.
+// The params are substituted with numbers, words, slugs and UUIDs, and
+// a key is only returned, if the lookup of it resolves to its own route,
+// so the segment matchers, the constraints and the param policies are all
+// honored. The routes that could not be reached are skipped. The keys
+// only depend on the stored routes and on the seed.
+func (t *Tree[T]) GenerateRequests(n int, seed int64) []string {
+	if n <= 0 || checkTree(t) != nil {
+		return make([]string, 0)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	leaves := getAllLeafRec(t.root)
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].value.Pattern() < leaves[j].value.Pattern()
+	})
+
+	var (
+		requests  = make([]string, 0, n)
+		reachable = make([]*Node[T], 0, len(leaves))
+	)
+
+	for _, l := range leaves {
+		if len(requests) == n {
+			return requests
+		}
+
+		if key, ok := t.generateKey(l, rnd); ok {
+			requests = append(requests, key)
+			reachable = append(reachable, l)
+		}
+	}
+
+	for progress := true; progress && len(requests) < n; {
+		progress = false
+
+		for _, l := range reachable {
+			if len(requests) == n {
+				break
+			}
+
+			// A route once reached could be reached again with a high
+			// chance, but not for sure, since the values are random.
+			if key, ok := t.generateKey(l, rnd); ok {
+				requests = append(requests, key)
+				progress = true
+			}
+		}
+	}
+
+	return requests
+}
+
+// generateKey returns a random key, that resolves to the given leaf.
+// The caller must hold the read lock.
+func (t *Tree[T]) generateKey(leaf *Node[T], rnd *rand.Rand) (string, bool) {
+	// The keys are generated with the delimiters of the tree.
+	var (
+		d        = t.delims()
+		segments = d.split(leaf.value.Pattern())
+	)
+
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		key := sampleKey(segments, d, rnd)
+
+		if n, _ := t.findNode(key); n == leaf {
+			return key, true
+		}
+
+		// Without params every attempt would be the same.
+		if len(leaf.value.params) == 0 {
+			break
+		}
+	}
+
+	return "", false
+}
+
+// sampleKey returns a key of the pattern split to the given segments –
+// written with the given delimiters –, with the params substituted by
+// random values.
+func sampleKey(segments []string, d Delimiters, rnd *rand.Rand) string {
+	parts := make([]string, len(segments))
+
+	for i, seg := range segments {
+		if !d.isParamSegment(seg) {
+			parts[i] = unescape(seg)
+			continue
+		}
+
+		parts[i] = sampleValue(rnd, d.isCatchAll(seg), d.Segment)
+	}
+
+	return strings.Join(parts, string(d.Segment))
+}
+
+// sampleValue returns a random value of a param. The value of
+// a catch-all param consists of one or more segments.
+func sampleValue(rnd *rand.Rand, catchAll bool, delimiter byte) string {
+	if !catchAll {
+		return sampleSegment(rnd)
+	}
+
+	segments := make([]string, 1+rnd.Intn(3))
+
+	for i := range segments {
+		segments[i] = sampleSegment(rnd)
+	}
+
+	return strings.Join(segments, string(delimiter))
+}
+
+func sampleSegment(rnd *rand.Rand) string {
+	switch rnd.Intn(4) {
+	case 0:
+		return fmt.Sprint(1 + rnd.Intn(99999))
+	case 1:
+		return randomString(rnd, lowerLetters, 3+rnd.Intn(6))
+	case 2:
+		return randomString(rnd, lowerLetters, 3+rnd.Intn(4)) + "-" + fmt.Sprint(rnd.Intn(100))
+	default:
+		return sampleUUID(rnd)
+	}
+}
+
+func sampleUUID(rnd *rand.Rand) string {
+	return strings.Join([]string{
+		randomString(rnd, hexDigits, 8),
+		randomString(rnd, hexDigits, 4),
+		randomString(rnd, hexDigits, 4),
+		randomString(rnd, hexDigits, 4),
+		randomString(rnd, hexDigits, 12),
+	}, "-")
+}
+
+func randomString(rnd *rand.Rand, alphabet string, length int) string {
+	b := make([]byte, length)
+
+	for i := range b {
+		b[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+
+	return string(b)
+}
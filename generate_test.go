@@ -0,0 +1,87 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRequests(t *testing.T) {
+	tree := New(WithSegmentMatcher[*Route]("uuid", UUID))
+
+	routes := []string{
+		"/",
+		"/users",
+		"/users/{id}",
+		"/users/{id}/posts/{post}",
+		"/users/me",
+		"/files/{path...}",
+		"/sessions/{sid:uuid}",
+		"/legacy/\\{id\\}",
+	}
+
+	for _, key := range routes {
+		if err := tree.Insert(key, &Route{name: key}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	spec, err := NewRoute("/orders/{id}").Constraint("id", Int).Build()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertRoute(spec, &Route{name: spec.Pattern()}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	routes = append(routes, spec.Pattern())
+
+	requests := tree.GenerateRequests(3*len(routes), 42)
+
+	if len(requests) != 3*len(routes) {
+		t.Fatalf("expected %d requests; got: %d\n", 3*len(routes), len(requests))
+	}
+
+	hits := make(map[string]int)
+
+	for _, key := range requests {
+		fn := tree.Find(key)
+		if fn == nil {
+			t.Fatalf("expected match of generated key %s; got <nil>\n", key)
+		}
+
+		hits[fn.GetPattern()]++
+	}
+
+	for _, pattern := range routes {
+		if hits[pattern] == 0 {
+			t.Errorf("expected route %s to be exercised\n", pattern)
+		}
+	}
+
+	if again := tree.GenerateRequests(3*len(routes), 42); !reflect.DeepEqual(again, requests) {
+		t.Errorf("expected the same requests for the same seed\n")
+	}
+}
+
+func TestGenerateRequestsUnreachable(t *testing.T) {
+	tree := New(WithSegmentMatcher[*Route]("never", SegmentMatcherFunc(func(string) (bool, string) {
+		return false, ""
+	})))
+
+	for _, key := range []string{"/a", "/b/{id:never}"} {
+		if err := tree.Insert(key, &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	requests := tree.GenerateRequests(3, 1)
+
+	if !reflect.DeepEqual(requests, []string{"/a", "/a", "/a"}) {
+		t.Fatalf("expected only the reachable route; got: %v\n", requests)
+	}
+
+	if requests := New[*Route]().GenerateRequests(3, 1); len(requests) != 0 {
+		t.Fatalf("expected no requests of an empty tree; got: %v\n", requests)
+	}
+}
@@ -0,0 +1,57 @@
+package rtree
+
+import (
+	"sort"
+	"strings"
+)
+
+// GroupByPrefix buckets the patterns of all the stored routes by their
+// first depth segments, eg. with depth 2 both /api/users and
+// /api/users/{id} go under /api/users. Patterns with less segments form
+// a group of their own. The patterns of a group are sorted.
+func (t *Tree[T]) GroupByPrefix(depth int) map[string][]string {
+	groups := make(map[string][]string)
+
+	if err := checkTree(t); err != nil {
+		return groups
+	}
+
+	t.mu.RLock()
+	leaves := getAllLeafRec(t.root)
+	t.mu.RUnlock()
+
+	for _, l := range leaves {
+		pattern := l.value.Pattern()
+		prefix := patternPrefix(pattern, depth, t.delims())
+
+		groups[prefix] = append(groups[prefix], pattern)
+	}
+
+	for _, patterns := range groups {
+		sort.Strings(patterns)
+	}
+
+	return groups
+}
+
+// patternPrefix returns the first depth segments of the
+// pattern, written with the given delimiters.
+func patternPrefix(pattern string, depth int, d Delimiters) string {
+	if depth < 1 {
+		return string(d.Segment)
+	}
+
+	// The leading delimiter is not a segment of its own.
+	lead := ""
+
+	if pattern != "" && pattern[0] == d.Segment {
+		lead, pattern = pattern[:1], pattern[1:]
+	}
+
+	segments := d.split(pattern)
+	if depth >= len(segments) {
+		return lead + pattern
+	}
+
+	return lead + strings.Join(segments[:depth], string(d.Segment))
+}
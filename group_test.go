@@ -0,0 +1,87 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupByPrefix(t *testing.T) {
+	type testCase struct {
+		name     string
+		routes   []string
+		depth    int
+		expected map[string][]string
+	}
+
+	routes := []string{
+		"/api/users/{id}",
+		"/api/users",
+		"/api/products/{id}/reviews",
+		"/api/products",
+		"/health",
+	}
+
+	tt := []testCase{
+		{
+			name:     "empty tree",
+			routes:   nil,
+			depth:    1,
+			expected: map[string][]string{},
+		},
+		{
+			name:   "zero depth puts everything in one group",
+			routes: routes,
+			depth:  0,
+			expected: map[string][]string{
+				"/": {"/api/products", "/api/products/{id}/reviews", "/api/users", "/api/users/{id}", "/health"},
+			},
+		},
+		{
+			name:   "first segment",
+			routes: routes,
+			depth:  1,
+			expected: map[string][]string{
+				"/api":    {"/api/products", "/api/products/{id}/reviews", "/api/users", "/api/users/{id}"},
+				"/health": {"/health"},
+			},
+		},
+		{
+			name:   "first two segments",
+			routes: routes,
+			depth:  2,
+			expected: map[string][]string{
+				"/api/users":    {"/api/users", "/api/users/{id}"},
+				"/api/products": {"/api/products", "/api/products/{id}/reviews"},
+				"/health":       {"/health"},
+			},
+		},
+		{
+			name:   "params are the part of the prefix",
+			routes: routes,
+			depth:  3,
+			expected: map[string][]string{
+				"/api/users":         {"/api/users"},
+				"/api/users/{id}":    {"/api/users/{id}"},
+				"/api/products":      {"/api/products"},
+				"/api/products/{id}": {"/api/products/{id}/reviews"},
+				"/health":            {"/health"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			for _, r := range tc.routes {
+				if err := tree.Insert(r, getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			if got := tree.GroupByPrefix(tc.depth); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected groups: %v; got: %v\n", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,571 @@
+package rtree
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errBadGuard = fmt.Errorf("[rtree %s]: bad guard expression", version)
+
+// Guard is a compiled condition of a route on the attributes of the
+// lookup – eg. the headers of the request –, see ParseGuard.
+type Guard struct {
+	expr string
+	root guardNode
+}
+
+// guardNode is a node of the syntax tree of a guard expression.
+type guardNode interface {
+	eval(attrs map[string]string) bool
+}
+
+type (
+	guardOr struct {
+		left, right guardNode
+	}
+
+	guardAnd struct {
+		left, right guardNode
+	}
+
+	guardNot struct {
+		operand guardNode
+	}
+
+	// guardPresent is a bare attribute, which holds,
+	// if the attribute is given and it is not empty.
+	guardPresent struct {
+		attr string
+	}
+
+	guardCompare struct {
+		op          string
+		left, right guardOperand
+	}
+)
+
+// guardOperand is either an attribute or a literal of a comparison.
+type guardOperand struct {
+	attr    string
+	literal string
+	isAttr  bool
+}
+
+// ParseGuard compiles the guard expression. The expression is built of
+// the comparisons of attributes and literals – by ==, !=, <, <=, > and
+// >= –, combined by &&, || and !, and grouped by parentheses, eg.:
+//
+//	header.x-api-version >= 2 && (cookie.beta == "on" || !header.x-legacy)
+//
+// The attributes are named by letters, digits, „_”, „.” and „-”, starting
+// with a letter or „_”, while the literals are numbers or quoted strings.
+// Two values are compared as numbers, if both of them are numbers, and
+// as strings otherwise. A bare attribute holds, if it is not empty, while
+// every comparison of a missing attribute is false – „!=” included.
+func ParseGuard(expr string) (*Guard, error) {
+	tokens, err := tokenizeGuard(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &guardParser{tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("%w: unexpected %q in %q", errBadGuard, p.peek().text, expr)
+	}
+
+	return &Guard{expr: expr, root: root}, nil
+}
+
+// WithGuard makes the route only match the lookups, whose attributes
+// satisfy the guard, see FindAttrs. The lookups without attributes –
+// eg. Find – evaluate the guard on none, so the guarded routes could
+// fall back to the routes of the same keys without a guard.
+//
+// A pattern could be inserted several times with different guards – and
+// once without a guard –: its values are tried in the order of their
+// insertion, while the one without a guard is tried last. The value
+// without a guard – or the first inserted one, if all of them are
+// guarded – is the value of the pattern for Upsert, Disable, Alias and
+// the snapshots and the store of the tree, while Delete removes all
+// the values of the pattern.
+func WithGuard(g *Guard) RouteOption {
+	return func(rm *routeMeta) {
+		rm.guard = g
+	}
+}
+
+// addVariant stores the value as an other value of its already stored
+// pattern, if neither of them is an alias, and its guard differs from
+// the guards of all the values of the pattern. The value without a guard
+// takes the place of the leaf, while the others are its variants. It
+// reports whether the value is stored. The caller must hold the write lock.
+func (t *Tree[T]) addVariant(nv *NodeValue[T]) bool {
+	n := findExactRec(t.root, nv.pattern)
+	if n == nil || nv.aliasOf != 0 || n.value.aliasOf != 0 {
+		return false
+	}
+
+	if n.value.meta.guard.String() == nv.meta.guard.String() {
+		return false
+	}
+
+	for _, v := range n.value.variants {
+		if v.value.meta.guard.String() == nv.meta.guard.String() {
+			return false
+		}
+	}
+
+	t.stored(nv)
+
+	// The value of the leaf is replaced – just like by Upsert –,
+	// instead of being altered, since the lookups could still be reading it.
+	if nv.meta.guard == nil {
+		prev := *n.value
+		prev.variants = nil
+
+		nv.variants = withVariant(n.value.variants, &prev)
+		n.value = nv
+		t.publish(&prev)
+
+		return true
+	}
+
+	next := *n.value
+	next.variants = withVariant(n.value.variants, nv)
+	n.value = &next
+	t.publish(&next)
+
+	return true
+}
+
+// withVariant returns a copy of the variants with the given value added.
+func withVariant[T storeValue](variants []*Node[T], nv *NodeValue[T]) []*Node[T] {
+	added := make([]*Node[T], 0, len(variants)+1)
+	added = append(added, variants...)
+	added = append(added, &Node[T]{key: nv.pattern, value: nv, paramIdx: -1})
+
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].value.id < added[j].value.id
+	})
+
+	return added
+}
+
+// removeValue removes the given value of the leaf at the end of the path,
+// notifying the watchers with the given kind. Unlike remove, it keeps the
+// other values of the pattern: the first variant takes the place of the
+// removed value of the leaf. It reports whether the leaf is removed as
+// well, since it had no other values. The caller must hold the write lock.
+func (t *Tree[T]) removeValue(path []*Node[T], nv *NodeValue[T], kind ChangeKind) bool {
+	n := path[len(path)-1]
+
+	if len(n.value.variants) == 0 {
+		t.remove(path, kind)
+		return true
+	}
+
+	var next NodeValue[T]
+
+	if n.value == nv {
+		next = *n.value.variants[0].value
+		next.variants = withoutVariant(n.value.variants, next.id)
+	} else {
+		next = *n.value
+		next.variants = withoutVariant(n.value.variants, nv.id)
+	}
+
+	n.value = &next
+	t.publish(&next)
+
+	delete(t.routes, nv.id)
+
+	t.generation.Add(1)
+	t.notify(kind, nv, nv.value)
+
+	return false
+}
+
+// withoutVariant returns a copy of the variants without the given value.
+func withoutVariant[T storeValue](variants []*Node[T], id uint64) []*Node[T] {
+	kept := make([]*Node[T], 0, len(variants))
+
+	for _, v := range variants {
+		if v.value.id != id {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
+
+// Eval reports whether the attributes satisfy the guard.
+// A nil guard is satisfied by any attributes.
+func (g *Guard) Eval(attrs map[string]string) bool {
+	if g == nil {
+		return true
+	}
+
+	return g.root.eval(attrs)
+}
+
+// String returns the expression the guard was compiled of.
+func (g *Guard) String() string {
+	if g == nil {
+		return ""
+	}
+
+	return g.expr
+}
+
+// Guard returns the guard of the matched route, or nil if the route has none.
+func (fn *FoundNode[T]) Guard() *Guard {
+	if fn.meta == nil {
+		return nil
+	}
+
+	return fn.meta.guard
+}
+
+// FindAttrs searches for the key the same way as TryFind, but the guards
+// of the routes are evaluated on the given attributes, so a key could
+// match different routes depending on eg. the headers of the request.
+// The lookup cache is bypassed, since the result depends on the
+// attributes as well.
+func (t *Tree[T]) FindAttrs(key string, attrs map[string]string) *FoundNode[T] {
+	if err := checkTree(t); err != nil {
+		return nil
+	}
+
+	return t.observe(key, func(key string) *FoundNode[T] {
+		if key == "" {
+			return nil
+		}
+
+		n, params, _ := t.lookupWith(key, attrs, nil, nil, t.resolution)
+		if n == nil {
+			return nil
+		}
+
+		return t.newFoundNode(n, params)
+	})
+}
+
+func (n *guardOr) eval(attrs map[string]string) bool {
+	return n.left.eval(attrs) || n.right.eval(attrs)
+}
+
+func (n *guardAnd) eval(attrs map[string]string) bool {
+	return n.left.eval(attrs) && n.right.eval(attrs)
+}
+
+func (n *guardNot) eval(attrs map[string]string) bool {
+	return !n.operand.eval(attrs)
+}
+
+func (n *guardPresent) eval(attrs map[string]string) bool {
+	return attrs[n.attr] != ""
+}
+
+func (n *guardCompare) eval(attrs map[string]string) bool {
+	left, ok := n.left.value(attrs)
+	if !ok {
+		return false
+	}
+
+	right, ok := n.right.value(attrs)
+	if !ok {
+		return false
+	}
+
+	cmp := compareGuardValues(left, right)
+
+	switch n.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// value returns the value of the operand, and false,
+// if it is an attribute missing from the given ones.
+func (o guardOperand) value(attrs map[string]string) (string, bool) {
+	if !o.isAttr {
+		return o.literal, true
+	}
+
+	v, ok := attrs[o.attr]
+
+	return v, ok
+}
+
+// compareGuardValues compares the values as numbers, if both
+// of them are numbers, and as strings otherwise.
+func compareGuardValues(a, b string) int {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type guardTokenKind int
+
+const (
+	guardIdent guardTokenKind = iota
+	guardNumber
+	guardString
+	guardOp
+	guardEOF
+)
+
+type guardToken struct {
+	kind guardTokenKind
+	// text is the token as written, while value is
+	// the unquoted content of the string literals.
+	text  string
+	value string
+}
+
+// guardOps are the operators, the longer ones first.
+var guardOps = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"}
+
+func tokenizeGuard(expr string) ([]guardToken, error) {
+	var tokens []guardToken
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string in %q", errBadGuard, expr)
+			}
+
+			text := expr[i : i+end+2]
+			tokens = append(tokens, guardToken{kind: guardString, text: text, value: text[1 : len(text)-1]})
+			i += end + 2
+		case isGuardDigit(c) || c == '-' && i+1 < len(expr) && isGuardDigit(expr[i+1]):
+			j := i + 1
+			for j < len(expr) && (isGuardDigit(expr[j]) || expr[j] == '.') {
+				j++
+			}
+
+			if _, err := strconv.ParseFloat(expr[i:j], 64); err != nil {
+				return nil, fmt.Errorf("%w: bad number %q in %q", errBadGuard, expr[i:j], expr)
+			}
+
+			tokens = append(tokens, guardToken{kind: guardNumber, text: expr[i:j], value: expr[i:j]})
+			i = j
+		case isGuardIdentStart(c):
+			j := i + 1
+			for j < len(expr) && isGuardIdentPart(expr[j]) {
+				j++
+			}
+
+			tokens = append(tokens, guardToken{kind: guardIdent, text: expr[i:j], value: expr[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range guardOps {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+
+			if op == "" {
+				return nil, fmt.Errorf("%w: unexpected %q in %q", errBadGuard, c, expr)
+			}
+
+			tokens = append(tokens, guardToken{kind: guardOp, text: op})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, guardToken{kind: guardEOF, text: "end of expression"}), nil
+}
+
+func isGuardDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isGuardIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isGuardIdentPart(c byte) bool {
+	return isGuardIdentStart(c) || isGuardDigit(c) || c == '.' || c == '-'
+}
+
+// guardParser is a recursive descent parser of the guard expressions:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" or ")" | operand [ compare operand ]
+type guardParser struct {
+	tokens []guardToken
+	pos    int
+}
+
+func (p *guardParser) peek() guardToken {
+	return p.tokens[p.pos]
+}
+
+func (p *guardParser) next() guardToken {
+	tok := p.tokens[p.pos]
+
+	if tok.kind != guardEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *guardParser) done() bool {
+	return p.peek().kind == guardEOF
+}
+
+// accept consumes the next token, if it is the given operator.
+func (p *guardParser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == guardOp && tok.text == op {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *guardParser) parseOr() (guardNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &guardOr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *guardParser) parseAnd() (guardNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &guardAnd{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *guardParser) parseUnary() (guardNode, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &guardNot{operand: operand}, nil
+	}
+
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.accept(")") {
+			return nil, fmt.Errorf("%w: missing „)” before %s", errBadGuard, p.peek().text)
+		}
+
+		return inner, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	if tok.kind != guardOp || !isGuardComparison(tok.text) {
+		if !left.isAttr {
+			return nil, fmt.Errorf("%w: literal %s without comparison", errBadGuard, left.literal)
+		}
+
+		return &guardPresent{attr: left.attr}, nil
+	}
+
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &guardCompare{op: tok.text, left: left, right: right}, nil
+}
+
+func (p *guardParser) parseOperand() (guardOperand, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case guardIdent:
+		return guardOperand{attr: tok.value, isAttr: true}, nil
+	case guardNumber, guardString:
+		return guardOperand{literal: tok.value}, nil
+	default:
+		return guardOperand{}, fmt.Errorf("%w: unexpected %s", errBadGuard, tok.text)
+	}
+}
+
+func isGuardComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	default:
+		return false
+	}
+}
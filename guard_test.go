@@ -0,0 +1,275 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseGuard(t *testing.T) {
+	tt := []struct {
+		name     string
+		expr     string
+		attrs    map[string]string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "numeric comparison",
+			expr:     "header.x-api-version >= 2",
+			attrs:    map[string]string{"header.x-api-version": "10"},
+			expected: true,
+		},
+		{
+			name:     "numeric comparison fails",
+			expr:     "header.x-api-version >= 2",
+			attrs:    map[string]string{"header.x-api-version": "1.5"},
+			expected: false,
+		},
+		{
+			name:     "string comparison",
+			expr:     `cookie.variant == "b"`,
+			attrs:    map[string]string{"cookie.variant": "b"},
+			expected: true,
+		},
+		{
+			name:     "comparison of missing attribute",
+			expr:     `cookie.variant != 'b'`,
+			expected: false,
+		},
+		{
+			name:     "bare attribute",
+			expr:     "header.x-beta",
+			attrs:    map[string]string{"header.x-beta": "1"},
+			expected: true,
+		},
+		{
+			name:     "negated bare attribute",
+			expr:     "!header.x-beta",
+			expected: true,
+		},
+		{
+			name:     "precedence",
+			expr:     `a == 1 || b == 1 && c == 1`,
+			attrs:    map[string]string{"a": "1", "b": "1", "c": "0"},
+			expected: true,
+		},
+		{
+			name:     "parentheses",
+			expr:     `(a == 1 || b == 1) && c == 1`,
+			attrs:    map[string]string{"a": "1", "b": "1", "c": "0"},
+			expected: false,
+		},
+		{
+			name:     "comparison of attributes",
+			expr:     "a < b",
+			attrs:    map[string]string{"a": "9", "b": "10"},
+			expected: true,
+		},
+		{
+			name: "missing operand",
+			expr: "a >=",
+			err:  errBadGuard,
+		},
+		{
+			name: "literal without comparison",
+			expr: "2 && a",
+			err:  errBadGuard,
+		},
+		{
+			name: "unbalanced parentheses",
+			expr: "(a == 1",
+			err:  errBadGuard,
+		},
+		{
+			name: "unterminated string",
+			expr: `a == "b`,
+			err:  errBadGuard,
+		},
+		{
+			name: "unexpected character",
+			expr: "a = 1",
+			err:  errBadGuard,
+		},
+		{
+			name: "trailing token",
+			expr: "a b",
+			err:  errBadGuard,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := ParseGuard(tc.expr)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := g.Eval(tc.attrs); got != tc.expected {
+				t.Errorf("expected: %v; got: %v\n", tc.expected, got)
+			}
+
+			if g.String() != tc.expr {
+				t.Errorf("expected expression: %q; got: %q\n", tc.expr, g.String())
+			}
+		})
+	}
+}
+
+func TestFindAttrs(t *testing.T) {
+	tree := New[*Route]()
+
+	guards := make(map[string]*Guard)
+
+	for _, expr := range []string{"header.x-api-version >= 2", "header.x-api-version >= 3", `cookie.beta == "on"`} {
+		g, err := ParseGuard(expr)
+		if err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		guards[expr] = g
+	}
+
+	inserts := []struct {
+		key   string
+		name  string
+		guard string
+	}{
+		{"/api/users/{id}", "v2", "header.x-api-version >= 2"},
+		{"/api/users/{id}", "plain", ""},
+		{"/api/users/{id}", "v3", "header.x-api-version >= 3"},
+		{"/api/beta", "beta", "header.x-api-version >= 2"},
+		{"/api/beta", "cookie", `cookie.beta == "on"`},
+	}
+
+	for _, ins := range inserts {
+		var opts []RouteOption
+		if ins.guard != "" {
+			opts = append(opts, WithGuard(guards[ins.guard]))
+		}
+
+		if err := tree.Insert(ins.key, &Route{name: ins.name}, opts...); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// The same guard could not be given twice to a pattern.
+	if err := tree.Insert("/api/users/{id}", getRoute(), WithGuard(guards["header.x-api-version >= 3"])); err == nil {
+		t.Fatalf("expected error of the same guard twice\n")
+	}
+
+	tt := []struct {
+		name          string
+		key           string
+		attrs         map[string]string
+		expectedName  string
+		expectedGuard string
+	}{
+		{
+			name:          "guard satisfied",
+			key:           "/api/users/1",
+			attrs:         map[string]string{"header.x-api-version": "2"},
+			expectedName:  "v2",
+			expectedGuard: "header.x-api-version >= 2",
+		},
+		{
+			name:          "guards in the order of insertion",
+			key:           "/api/users/1",
+			attrs:         map[string]string{"header.x-api-version": "3"},
+			expectedName:  "v2",
+			expectedGuard: "header.x-api-version >= 2",
+		},
+		{
+			name:         "guard not satisfied",
+			key:          "/api/users/1",
+			attrs:        map[string]string{"header.x-api-version": "1"},
+			expectedName: "plain",
+		},
+		{
+			name:         "no attributes",
+			key:          "/api/users/1",
+			expectedName: "plain",
+		},
+		{
+			name:          "guarded route without fallback",
+			key:           "/api/beta",
+			attrs:         map[string]string{"header.x-api-version": "3"},
+			expectedName:  "beta",
+			expectedGuard: "header.x-api-version >= 2",
+		},
+		{
+			name:          "second guard of the pattern",
+			key:           "/api/beta",
+			attrs:         map[string]string{"cookie.beta": "on"},
+			expectedName:  "cookie",
+			expectedGuard: `cookie.beta == "on"`,
+		},
+		{
+			name: "guarded route without fallback not satisfied",
+			key:  "/api/beta",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.FindAttrs(tc.key, tc.attrs)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetValue().name)
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match: %s; got none\n", tc.expectedName)
+			}
+
+			if fn.GetValue().name != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, fn.GetValue().name)
+			}
+
+			if got := fn.Guard().String(); got != tc.expectedGuard {
+				t.Errorf("expected guard: %q; got: %q\n", tc.expectedGuard, got)
+			}
+		})
+	}
+
+	// The lookups without attributes never match the guarded routes.
+	if fn := tree.Find("/api/users/1"); fn == nil || fn.GetValue().name != "plain" {
+		t.Errorf("expected the unguarded route to be found by Find\n")
+	}
+
+	if fn := tree.Find("/api/beta"); fn != nil {
+		t.Errorf("expected no match of a guarded route by Find; got: %s\n", fn.GetValue().name)
+	}
+
+	// The unguarded value is the one of the pattern, the variants are kept by Upsert.
+	if err := tree.Upsert("/api/users/{id}", &Route{name: "upserted"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.FindAttrs("/api/users/1", map[string]string{"header.x-api-version": "2"}); fn == nil || fn.GetValue().name != "v2" {
+		t.Errorf("expected the guarded value to be kept by Upsert\n")
+	}
+
+	if fn := tree.Find("/api/users/1"); fn == nil || fn.GetValue().name != "upserted" {
+		t.Errorf("expected the upserted value to be found by Find\n")
+	}
+
+	// Delete removes all the values of the pattern.
+	if err := tree.Delete("/api/users/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.FindAttrs("/api/users/1", map[string]string{"header.x-api-version": "3"}); fn != nil {
+		t.Errorf("expected no match after Delete; got: %s\n", fn.GetValue().name)
+	}
+
+	if got := len(tree.routes); got != 2 {
+		t.Errorf("expected routes: 2; got: %d\n", got)
+	}
+}
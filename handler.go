@@ -0,0 +1,136 @@
+package rtree
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HandlerOption configures the handler returned by NewHandler.
+type HandlerOption func(*handler)
+
+// PanicHandler handles the value recovered from a panicking route handler.
+type PanicHandler func(w http.ResponseWriter, r *http.Request, recovered any)
+
+type handler struct {
+	routes *MethodTrees[http.Handler]
+
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+	panicHandler     PanicHandler
+}
+
+// WithNotFoundHandler sets the handler of the requests, whose path has
+// no route at all. By default it is http.NotFound.
+func WithNotFoundHandler(h http.Handler) HandlerOption {
+	return func(hd *handler) {
+		hd.notFound = h
+	}
+}
+
+// WithMethodNotAllowedHandler sets the handler of the requests, whose
+// path has routes, but not for their method. The Allow header is already
+// set, when it is called. By default it responds 405 Method Not Allowed.
+func WithMethodNotAllowedHandler(h http.Handler) HandlerOption {
+	return func(hd *handler) {
+		hd.methodNotAllowed = h
+	}
+}
+
+// WithPanicHandler sets the handler of the panics of the route handlers.
+// Without it the panics are not recovered, they are left to net/http.
+func WithPanicHandler(fn PanicHandler) HandlerOption {
+	return func(hd *handler) {
+		hd.panicHandler = fn
+	}
+}
+
+// NewHandler returns the HTTP adapter of the method-aware route table:
+// it serves every request with the handler of its method and path, with
+// the matched params in the context of the request, see ParamsFromContext.
+// A known path without a route of the method is answered by 405 Method
+// Not Allowed – and the Allow header –, except for the OPTIONS requests
+// with WithAutoOptions, which are answered by 204 No Content and the
+// Allow header. An unknown path is answered by 404 Not Found. The
+// redirects and the rate-limits of the routes are served as well.
+func NewHandler(routes *MethodTrees[http.Handler], opts ...HandlerOption) http.Handler {
+	hd := &handler{
+		routes: routes,
+	}
+
+	for _, o := range opts {
+		o(hd)
+	}
+
+	return hd
+}
+
+func (hd *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if hd.panicHandler != nil {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				hd.panicHandler(w, r, recovered)
+			}
+		}()
+	}
+
+	fn, allowed := hd.routes.FindMethod(r.Method, r.URL.Path)
+
+	if fn != nil {
+		hd.serveFound(w, r, fn)
+		return
+	}
+
+	if len(allowed) == 0 {
+		if hd.notFound != nil {
+			hd.notFound.ServeHTTP(w, r)
+			return
+		}
+
+		http.NotFound(w, r)
+
+		return
+	}
+
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+	if r.Method == http.MethodOptions && containsMethod(allowed, http.MethodOptions) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if hd.methodNotAllowed != nil {
+		hd.methodNotAllowed.ServeHTTP(w, r)
+		return
+	}
+
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// serveFound serves the request of the matched route: a request denied by
+// the rate-limit of the route is answered by 429 Too Many Requests, while
+// a redirect – eg. of TrailingSlashRedirect – is answered by its target,
+// keeping the query of the request. Only the other routes have a handler.
+func (hd *handler) serveFound(w http.ResponseWriter, r *http.Request, fn *FoundNode[http.Handler]) {
+	if !fn.Allowed() {
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+
+	if fn.IsRedirect() {
+		target, code := fn.Redirect()
+		if target == "" {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+			target += "?" + r.URL.RawQuery
+		}
+
+		http.Redirect(w, r, target, code)
+
+		return
+	}
+
+	fn.GetValue().ServeHTTP(w, r.WithContext(NewContext(r.Context(), fn.GetParams())))
+}
@@ -0,0 +1,171 @@
+package rtree
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newHandlerRoutes(t *testing.T) *MethodTrees[http.Handler] {
+	t.Helper()
+
+	mt := NewMethodTrees[http.Handler]()
+
+	inserts := []struct {
+		method  string
+		key     string
+		handler http.HandlerFunc
+	}{
+		{"GET", "/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			params, _ := ParamsFromContext(r.Context())
+			fmt.Fprintf(w, "user %s", params["id"])
+		}},
+		{"DELETE", "/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}},
+		{"GET", "/panic", func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}},
+	}
+
+	for _, ins := range inserts {
+		if err := mt.Insert(ins.method, ins.key, ins.handler); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	return mt
+}
+
+func TestHandler(t *testing.T) {
+	type testCase struct {
+		name           string
+		opts           []HandlerOption
+		autoOptions    bool
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedAllow  string
+	}
+
+	custom := []HandlerOption{
+		WithNotFoundHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+			fmt.Fprint(w, "custom not found")
+		})),
+		WithMethodNotAllowedHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprintf(w, "allowed: %s", w.Header().Get("Allow"))
+		})),
+		WithPanicHandler(func(w http.ResponseWriter, r *http.Request, recovered any) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "recovered: %v", recovered)
+		}),
+	}
+
+	tt := []testCase{
+		{name: "match with params", method: "GET", path: "/users/5", expectedStatus: http.StatusOK, expectedBody: "user 5"},
+		{name: "default not found", method: "GET", path: "/posts", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+		{name: "default method not allowed", method: "POST", path: "/users/5", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "Method Not Allowed\n", expectedAllow: "DELETE, GET"},
+		{name: "options without auto options", method: "OPTIONS", path: "/users/5", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "Method Not Allowed\n", expectedAllow: "DELETE, GET"},
+		{name: "auto options", autoOptions: true, method: "OPTIONS", path: "/users/5", expectedStatus: http.StatusNoContent, expectedAllow: "DELETE, GET, OPTIONS"},
+		{name: "custom not found", opts: custom, method: "GET", path: "/posts", expectedStatus: http.StatusTeapot, expectedBody: "custom not found"},
+		{name: "custom method not allowed", opts: custom, method: "POST", path: "/users/5", expectedStatus: http.StatusConflict, expectedBody: "allowed: DELETE, GET", expectedAllow: "DELETE, GET"},
+		{name: "custom panic handler", opts: custom, method: "GET", path: "/panic", expectedStatus: http.StatusInternalServerError, expectedBody: "recovered: boom"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			routes := newHandlerRoutes(t)
+
+			if tc.autoOptions {
+				routes.SetOptions(WithAutoOptions[http.Handler]())
+			}
+
+			var (
+				rec = httptest.NewRecorder()
+				req = httptest.NewRequest(tc.method, tc.path, nil)
+			)
+
+			NewHandler(routes, tc.opts...).ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("expected status: %d; got: %d\n", tc.expectedStatus, rec.Code)
+			}
+
+			if got := rec.Body.String(); got != tc.expectedBody {
+				t.Errorf("expected body: %q; got: %q\n", tc.expectedBody, got)
+			}
+
+			if got := rec.Header().Get("Allow"); got != tc.expectedAllow {
+				t.Errorf("expected Allow: %q; got: %q\n", tc.expectedAllow, got)
+			}
+		})
+	}
+}
+
+func TestHandlerPanicWithoutHandler(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered != "boom" {
+			t.Errorf("expected the panic to be left to the caller; got: %v\n", recovered)
+		}
+	}()
+
+	NewHandler(newHandlerRoutes(t)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+}
+
+func TestHandlerRouteMeta(t *testing.T) {
+	routes := NewMethodTrees[http.Handler](
+		WithTrailingSlash[http.Handler](TrailingSlashRedirect),
+		WithRateLimiting[http.Handler](),
+	)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+
+	if err := routes.Insert("GET", "/users/{id}", ok); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := routes.Insert("GET", "/limited", ok, WithRateLimit(0.001, 1)); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := routes.tree("GET").InsertRedirect("/old/{id}", "/users/{id}", http.StatusPermanentRedirect); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tt := []struct {
+		name             string
+		path             string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{name: "redirect route", path: "/old/5", expectedStatus: http.StatusPermanentRedirect, expectedLocation: "/users/5"},
+		{name: "redirect route keeps the query", path: "/old/5?tab=info", expectedStatus: http.StatusPermanentRedirect, expectedLocation: "/users/5?tab=info"},
+		{name: "trailing slash redirect", path: "/users/5/", expectedStatus: http.StatusMovedPermanently, expectedLocation: "/users/5"},
+		{name: "allowed by the rate-limit", path: "/limited", expectedStatus: http.StatusOK},
+		{name: "denied by the rate-limit", path: "/limited", expectedStatus: http.StatusTooManyRequests},
+	}
+
+	handler := NewHandler(routes)
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", tc.path, nil))
+
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("expected status: %d; got: %d\n", tc.expectedStatus, rec.Code)
+			}
+
+			if got := rec.Header().Get("Location"); got != tc.expectedLocation {
+				t.Errorf("expected Location: %q; got: %q\n", tc.expectedLocation, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package rtree
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// ValueHasher returns the bytes that represent a stored value in the hash.
+type ValueHasher[T storeValue] func(value T) []byte
+
+// Hash returns a stable hash of all the stored patterns. The insertion
+// order does not affect the result, so it is suitable for cheap change
+// detection, eg. to be used as an ETag.
+func (t *Tree[T]) Hash() uint64 {
+	return t.HashWith(nil)
+}
+
+// HashWith is similar to Hash, but it involves the stored values
+// as well – represented by the bytes returned by the given hasher.
+// The hasher is called under the read lock of the tree, so it must
+// not mutate the tree.
+func (t *Tree[T]) HashWith(hasher ValueHasher[T]) uint64 {
+	h := fnv.New64a()
+
+	if err := checkTree(t); err != nil {
+		return h.Sum64()
+	}
+
+	// The leaves are read until the end, since a
+	// mutation could take their values away.
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	leaves := getAllLeafRec(t.root)
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].value.pattern < leaves[j].value.pattern
+	})
+
+	for _, l := range leaves {
+		// The separator byte makes sure, that the boundaries
+		// of the patterns are also part of the hash.
+		h.Write([]byte(l.value.pattern))
+		h.Write([]byte{0})
+
+		if hasher != nil {
+			h.Write(hasher(t.resolve(l.value).value))
+			h.Write([]byte{0})
+		}
+	}
+
+	return h.Sum64()
+}
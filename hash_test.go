@@ -0,0 +1,115 @@
+package rtree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	type testCase struct {
+		name      string
+		routes1   []string
+		routes2   []string
+		withValue bool
+		isEqual   bool
+	}
+
+	tt := []testCase{
+		{
+			name:    "empty trees are equal",
+			routes1: []string{},
+			routes2: []string{},
+			isEqual: true,
+		},
+		{
+			name:    "insertion order does not matter",
+			routes1: []string{"/foo", "/foo/bar", "/baz/{id}"},
+			routes2: []string{"/baz/{id}", "/foo/bar", "/foo"},
+			isEqual: true,
+		},
+		{
+			name:    "different patterns are not equal",
+			routes1: []string{"/foo", "/foo/bar"},
+			routes2: []string{"/foo", "/foo/baz"},
+			isEqual: false,
+		},
+		{
+			name:    "boundaries of the patterns are involved",
+			routes1: []string{"/foo", "/foobar"},
+			routes2: []string{"/foofoo", "/bar"},
+			isEqual: false,
+		},
+		{
+			name:      "different values are not equal with hasher",
+			routes1:   []string{"/foo", "/foo/bar"},
+			routes2:   []string{"/foo", "/foo/bar"},
+			withValue: true,
+			isEqual:   false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			build := func(routes []string, name string) *Tree[*Route] {
+				tree := New[*Route]()
+
+				for _, r := range routes {
+					if err := tree.Insert(r, &Route{name: name}); err != nil {
+						t.Fatalf("not expected error, but got: %v\n", err)
+					}
+				}
+
+				return tree
+			}
+
+			var (
+				tree1 = build(tc.routes1, "first")
+				tree2 = build(tc.routes2, "second")
+
+				hash1 = tree1.Hash()
+				hash2 = tree2.Hash()
+			)
+
+			if tc.withValue {
+				hasher := func(r *Route) []byte { return []byte(r.name) }
+
+				hash1 = tree1.HashWith(hasher)
+				hash2 = tree2.HashWith(hasher)
+			}
+
+			if (hash1 == hash2) != tc.isEqual {
+				t.Errorf("expected equality: %v; got hashes: %d, %d\n", tc.isEqual, hash1, hash2)
+			}
+		})
+	}
+}
+
+func TestHashConcurrent(t *testing.T) {
+	tree := New[string]()
+
+	for i := 0; i < 100; i++ {
+		if err := tree.Insert(fmt.Sprintf("/routes/%d", i), "route"); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	done := make(chan struct{})
+
+	// The leaves taken away by the mutations must not be read by the hash.
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("/routes/%d", i%100)
+
+			_ = tree.Delete(key)
+			_ = tree.Insert(key, "route")
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		tree.HashWith(func(v string) []byte { return []byte(v) })
+	}
+
+	<-done
+}
@@ -0,0 +1,116 @@
+package rtree
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+var errBadHostPattern = fmt.Errorf("[rtree %s]: bad host pattern", version)
+
+const (
+	// hostPrefix starts the patterns of the host routes. Its first
+	// segment is an escape char followed by a NUL, which is not a valid
+	// escape sequence, so no other pattern could start with it. Since
+	// every escape char of the searched keys is escaped, neither could
+	// the lookups of the paths reach the host routes – only FindHost.
+	hostPrefix = "/\\\x00/"
+	// hostSeparator separates the labels of the host from the path.
+	hostSeparator = "/:"
+)
+
+// HostPattern returns the pattern of the route, that matches both the
+// host and the path, eg. api.{tenant}.example.com and /users/{id}. The
+// labels of the host are its segments, so a param must be a whole label,
+// and the catch-all params are not allowed in the host. The hosts are
+// matched case-insensitively. The returned pattern could be used by the
+// methods of the tree – eg. Delete – as any other pattern.
+func HostPattern(host, path string) (string, error) {
+	if host == "" {
+		return "", errBadHostPattern
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || strings.ContainsAny(label, `/:\`) {
+			return "", fmt.Errorf("%w: %s", errBadHostPattern, host)
+		}
+
+		if !strings.ContainsAny(label, `{}`) {
+			continue
+		}
+
+		if !isParamSegment(label) || strings.Count(label, string(curlyStart)) != 1 || isCatchAll(label) {
+			return "", fmt.Errorf("%w: the param of %s is not a whole label", errBadHostPattern, host)
+		}
+	}
+
+	if path == "" || path[0] != slash {
+		return "", errMissingSlashPrefix
+	}
+
+	return hostKey(foldPattern(host), path), nil
+}
+
+// hostKey returns the key of the given host and path.
+func hostKey(host, path string) string {
+	if path == string(slash) {
+		path = ""
+	}
+
+	return hostPrefix + strings.ReplaceAll(host, ".", string(slash)) + hostSeparator + path
+}
+
+// InsertHost stores the route, that matches both the host and the path,
+// so a single lookup resolves the params of both, see HostPattern.
+func (t *Tree[T]) InsertHost(host, path string, value T, opts ...RouteOption) error {
+	pattern, err := HostPattern(host, path)
+	if err != nil {
+		return err
+	}
+
+	return t.Insert(pattern, value, opts...)
+}
+
+// FindHost searches for the route of the given host and path, eg. the
+// Host header and the path of a request. The port of the host is ignored.
+func (t *Tree[T]) FindHost(host, path string) *FoundNode[T] {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	// The fully qualified form of the host is the same host.
+	host = strings.TrimSuffix(host, ".")
+
+	if host == "" || strings.ContainsAny(host, `/:`) || strings.Contains(host, "..") || host[0] == '.' {
+		return nil
+	}
+
+	if path == "" || path[0] != slash {
+		return nil
+	}
+
+	return t.observe(hostKey(asciiLower(host), path), t.findHost)
+}
+
+// findHost is the unobserved version of FindHost.
+func (t *Tree[T]) findHost(key string) *FoundNode[T] {
+	// The prefix is kept as it is, while the rest of
+	// the key is searched the same way as the paths.
+	var (
+		rest    = key[len(hostPrefix):]
+		escaped = hostPrefix + t.searchKey(rest)
+		b       = &budget{limit: t.backtrackBudget}
+	)
+
+	n, params := t.lookupFiltered(key, escaped, nil, nil, nil, b, (*NodeValue[T]).isHost)
+	if n == nil {
+		return t.findDefault(key, t.resolution)
+	}
+
+	return t.newFoundNode(n, params)
+}
+
+// isHost reports whether the route matches the host as well.
+func (nv *NodeValue[T]) isHost() bool {
+	return strings.HasPrefix(nv.pattern, hostPrefix)
+}
@@ -0,0 +1,244 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHostPattern(t *testing.T) {
+	type testCase struct {
+		name            string
+		host            string
+		path            string
+		expectedPattern string
+		expectedErr     error
+	}
+
+	tt := []testCase{
+		{
+			name:            "static host",
+			host:            "api.example.com",
+			path:            "/users",
+			expectedPattern: "/\\\x00/api/example/com/:/users",
+		},
+		{
+			name:            "host param",
+			host:            "API.{tenant}.example.com",
+			path:            "/users/{id}",
+			expectedPattern: "/\\\x00/api/{tenant}/example/com/:/users/{id}",
+		},
+		{
+			name:            "root path",
+			host:            "example.com",
+			path:            "/",
+			expectedPattern: "/\\\x00/example/com/:",
+		},
+		{
+			name:        "empty host",
+			host:        "",
+			path:        "/",
+			expectedErr: errBadHostPattern,
+		},
+		{
+			name:        "empty label",
+			host:        "api..com",
+			path:        "/",
+			expectedErr: errBadHostPattern,
+		},
+		{
+			name:        "param inside of a label",
+			host:        "api-{tenant}.example.com",
+			path:        "/",
+			expectedErr: errBadHostPattern,
+		},
+		{
+			name:        "catch-all in the host",
+			host:        "{sub...}.example.com",
+			path:        "/",
+			expectedErr: errBadHostPattern,
+		},
+		{
+			name:        "port in the host",
+			host:        "example.com:8080",
+			path:        "/",
+			expectedErr: errBadHostPattern,
+		},
+		{
+			name:        "path without slash",
+			host:        "example.com",
+			path:        "users",
+			expectedErr: errMissingSlashPrefix,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := HostPattern(tc.host, tc.path)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if pattern != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, pattern)
+			}
+		})
+	}
+}
+
+func TestFindHost(t *testing.T) {
+	type testCase struct {
+		name           string
+		host           string
+		path           string
+		expectedName   string
+		expectedParams matchedParams
+	}
+
+	tree := New[*Route]()
+
+	routes := []struct {
+		host string
+		path string
+		name string
+	}{
+		{host: "api.{tenant}.example.com", path: "/users/{id}", name: "tenant-users"},
+		{host: "api.{tenant}.example.com", path: "/", name: "tenant-root"},
+		{host: "api.example.com", path: "/users/{id}", name: "users"},
+	}
+
+	for _, r := range routes {
+		if err := tree.InsertHost(r.host, r.path, &Route{name: r.name}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// The path routes never collide with the host routes.
+	if err := tree.Insert("/users/{id}", &Route{name: "path-users"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tt := []testCase{
+		{
+			name:           "host and path params",
+			host:           "api.acme.example.com",
+			path:           "/users/42",
+			expectedName:   "tenant-users",
+			expectedParams: matchedParams{"tenant": "acme", "id": "42"},
+		},
+		{
+			name:           "port and case of the host are ignored",
+			host:           "API.Acme.example.com:8443",
+			path:           "/users/42",
+			expectedName:   "tenant-users",
+			expectedParams: matchedParams{"tenant": "acme", "id": "42"},
+		},
+		{
+			name:           "root path",
+			host:           "api.acme.example.com.",
+			path:           "/",
+			expectedName:   "tenant-root",
+			expectedParams: matchedParams{"tenant": "acme"},
+		},
+		{
+			name:           "static host",
+			host:           "api.example.com",
+			path:           "/users/1",
+			expectedName:   "users",
+			expectedParams: matchedParams{"id": "1"},
+		},
+		{
+			name: "unknown host",
+			host: "www.example.com",
+			path: "/users/1",
+		},
+		{
+			name: "empty label",
+			host: "api..example.com",
+			path: "/users/1",
+		},
+		{
+			name: "path without slash",
+			host: "api.example.com",
+			path: "users/1",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.FindHost(tc.host, tc.path)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetValue().name != tc.expectedName {
+				t.Errorf("expected route: %s; got: %s\n", tc.expectedName, fn.GetValue().name)
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+			}
+		})
+	}
+
+	if fn := tree.Find("/users/1"); fn == nil || fn.GetValue().name != "path-users" {
+		t.Fatalf("expected the path route; got: %v\n", fn)
+	}
+}
+
+func TestHostRoutesAreIsolated(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.InsertHost("admin.example.com", "/secret", &Route{name: "secret"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for _, pattern := range []string{"/{page}", "/files/{path...}"} {
+		if err := tree.Insert(pattern, &Route{name: pattern}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	pattern, err := HostPattern("admin.example.com", "/secret")
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// None of the plain paths reaches the host route.
+	for _, key := range []string{
+		"//admin/example/com/:/secret",
+		"/\x00/admin/example/com/:/secret",
+		pattern,
+	} {
+		if fn := tree.Find(key); fn != nil && fn.GetValue().name == "secret" {
+			t.Errorf("expected %q not to reach the host route\n", key)
+		}
+	}
+
+	// Nor the host lookups reach the plain routes.
+	if fn := tree.FindHost("www.example.com", "/files/a"); fn != nil {
+		t.Errorf("expected no match; got: %s\n", fn.GetPattern())
+	}
+
+	if fn := tree.FindHost("admin.example.com", "/secret"); fn == nil || fn.GetValue().name != "secret" {
+		t.Errorf("expected the host route; got: %v\n", fn)
+	}
+
+	// The host patterns are still usable as any other pattern.
+	if err := tree.Delete(pattern); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.FindHost("admin.example.com", "/secret"); fn != nil {
+		t.Errorf("expected no match; got: %s\n", fn.GetPattern())
+	}
+}
@@ -0,0 +1,101 @@
+package rtree
+
+import "unicode/utf8"
+
+// MatchKind is the kind of the match found by FindOrLongest.
+type MatchKind uint8
+
+const (
+	// MatchNone means neither the route nor the prefix matched.
+	MatchNone MatchKind = iota
+	// MatchExact means the key matched a route, as by Find.
+	MatchExact
+	// MatchLongest means only a prefix of the key matched,
+	// as by FindLongestMatch.
+	MatchLongest
+)
+
+// longestMatch records the longest match during the search of
+// an exact one, so both are found in a single traversal.
+type longestMatch[T storeValue] struct {
+	boundary bool
+	node     *Node[T]
+	// rem is the rest of the search key after the recorded node.
+	rem string
+}
+
+// record records the node, whose key fully matched the search key –
+// followed by the given rest –, if it is a longer match than the last
+// recorded one.
+func (lm *longestMatch[T]) record(n *Node[T], rem string) {
+	if lm == nil || !n.IsLeaf() || n.value.meta.inactive() {
+		return
+	}
+
+	if lm.node != nil && len(rem) >= len(lm.rem) {
+		return
+	}
+
+	if lm.boundary && !isSegmentBoundary(n.key, rem) {
+		return
+	}
+
+	lm.node = n
+	lm.rem = rem
+}
+
+// FindOrLongest searches for the route of the key, as Find does, and if
+// there is none, it falls back to the longest match of the key, as
+// FindLongestMatch does – but it walks the tree only once for both. It
+// returns the kind of the found match as well. Unlike Find, it never
+// returns the default route.
+func (t *Tree[T]) FindOrLongest(key string) (*FoundNode[T], MatchKind) {
+	if err := checkTree(t); err != nil {
+		return nil, MatchNone
+	}
+
+	kind := MatchNone
+
+	fn := t.observe(key, func(key string) *FoundNode[T] {
+		var fn *FoundNode[T]
+
+		fn, kind = t.findOrLongest(key)
+
+		return fn
+	})
+
+	return fn, kind
+}
+
+// findOrLongest is the unobserved version of FindOrLongest.
+func (t *Tree[T]) findOrLongest(key string) (*FoundNode[T], MatchKind) {
+	if key == "" {
+		return nil, MatchNone
+	}
+
+	if t.runeMatching && !utf8.ValidString(key) {
+		return nil, MatchNone
+	}
+
+	longest := &longestMatch[T]{boundary: t.boundaryPrefixes}
+
+	n, params, err := t.lookupWith(key, nil, nil, longest, t.resolution)
+	if n != nil {
+		return t.newFoundNode(n, params), MatchExact
+	}
+
+	if fn := t.findTrailingSlash(key, t.resolution); fn != nil {
+		return fn, MatchExact
+	}
+
+	// The aborted search could have missed the longest match.
+	if err != nil {
+		longest.node = findLongestMatchRec(t.root, t.searchKey(key), t.boundaryPrefixes)
+	}
+
+	if longest.node == nil || longest.node.value == nil {
+		return nil, MatchNone
+	}
+
+	return t.newLongestMatch(longest.node, key), MatchLongest
+}
@@ -0,0 +1,142 @@
+package rtree
+
+import "testing"
+
+func TestFindOrLongest(t *testing.T) {
+	type testCase struct {
+		name            string
+		opts            []OptionFunc[*Route]
+		key             string
+		expectedPattern string
+		expectedKind    MatchKind
+	}
+
+	boundary := []OptionFunc[*Route]{WithSegmentBoundaryPrefixes[*Route]()}
+
+	tt := []testCase{
+		{
+			name:            "exact static match",
+			key:             "/api/users",
+			expectedPattern: "/api/users",
+			expectedKind:    MatchExact,
+		},
+		{
+			name:            "exact param match",
+			key:             "/api/users/1/posts",
+			expectedPattern: "/api/users/{id}/posts",
+			expectedKind:    MatchExact,
+		},
+		{
+			name:            "longest match of the service",
+			key:             "/api/users/1/comments",
+			expectedPattern: "/api/users",
+			expectedKind:    MatchLongest,
+		},
+		{
+			name:            "longest match inside of a segment",
+			key:             "/api/usersx",
+			expectedPattern: "/api/users",
+			expectedKind:    MatchLongest,
+		},
+		{
+			name:            "longest match on segment boundary",
+			opts:            boundary,
+			key:             "/api/usersx",
+			expectedPattern: "/api",
+			expectedKind:    MatchLongest,
+		},
+		{
+			name:         "no match",
+			key:          "/files/1",
+			expectedKind: MatchNone,
+		},
+		{
+			name:         "empty key",
+			key:          "",
+			expectedKind: MatchNone,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			for _, key := range []string{"/api", "/api/users", "/api/users/{id}/posts"} {
+				if err := tree.Insert(key, &Route{}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			fn, kind := tree.FindOrLongest(tc.key)
+
+			if kind != tc.expectedKind {
+				t.Fatalf("expected kind: %d; got: %d\n", tc.expectedKind, kind)
+			}
+
+			if tc.expectedKind == MatchNone {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+		})
+	}
+}
+
+// TestFindOrLongestEquivalence checks, that the single traversal gives the
+// same results, as Find followed by FindLongestMatch on its misses.
+func TestFindOrLongestEquivalence(t *testing.T) {
+	patterns := []string{
+		"/", "/api", "/api/users", "/api/users/{id}", "/api/users/{id}/posts",
+		"/api/{version}/status", "/static/{path...}", "/static/css", "/svc/orders", "/svc/order",
+	}
+
+	keys := []string{
+		"/", "/a", "/api", "/api/", "/api/x", "/api/users", "/api/users/", "/api/users/1",
+		"/api/users/1/posts", "/api/users/1/posts/2", "/api/v1/status", "/api/v1/statusx",
+		"/static/css", "/static/css/site.css", "/svc/orders/1", "/svc/order/1", "/svc/ord", "/x/y",
+	}
+
+	for _, opts := range [][]OptionFunc[*Route]{
+		nil,
+		{WithSegmentBoundaryPrefixes[*Route]()},
+		{WithResolutionOrder[*Route](CatchAllFirst)},
+	} {
+		tree := New(opts...)
+
+		for _, p := range patterns {
+			if err := tree.Insert(p, &Route{}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		for _, key := range keys {
+			var (
+				expected     = tree.Find(key)
+				expectedKind = MatchExact
+			)
+
+			if expected == nil {
+				expected, expectedKind = tree.FindLongestMatch(key), MatchLongest
+			}
+
+			if expected == nil {
+				expectedKind = MatchNone
+			}
+
+			got, kind := tree.FindOrLongest(key)
+
+			if kind != expectedKind || (expected == nil) != (got == nil) || expected != nil && expected.GetPattern() != got.GetPattern() {
+				t.Errorf("%s: expected: %v (%d); got: %v (%d)\n", key, expected, expectedKind, got, kind)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package rtree
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WithDescription attaches a human-readable description to the route,
+// which is part of the reference generated by ExportMarkdown.
+func WithDescription(description string) RouteOption {
+	return func(rm *routeMeta) {
+		rm.description = description
+	}
+}
+
+// Description returns the description of the route, if any.
+func (nv *NodeValue[T]) Description() string {
+	return nv.meta.description
+}
+
+// Description returns the description of the matched route, if any.
+func (fn *FoundNode[T]) Description() string {
+	if fn.meta == nil {
+		return ""
+	}
+
+	return fn.meta.description
+}
+
+// ExportMarkdown writes the reference of the stored routes in markdown
+// to w: a section of every route – sorted by their patterns – with its
+// description, params and metadata, eg. its labels, timeout or sunset.
+// The routes with params also get an example key, that resolves to them.
+// The reference of an empty tree has no sections.
+func (t *Tree[T]) ExportMarkdown(w io.Writer) error {
+	if t == nil {
+		return ErrTreeIsNil
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("# Endpoints\n")
+
+	for _, info := range t.markdownRoutes() {
+		writeMarkdownRoute(&sb, info)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// markdownRoute is a route of the markdown reference.
+type markdownRoute struct {
+	*RouteInfo
+
+	// aliasOf is the pattern of the route this one is an alias of.
+	aliasOf string
+
+	// example is a key, that resolves to the route.
+	example string
+}
+
+// markdownRoutes returns the routes of the reference, sorted by their patterns.
+func (t *Tree[T]) markdownRoutes() []markdownRoute {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	routes := make([]markdownRoute, 0, len(t.routes))
+
+	for _, nv := range t.routes {
+		r := markdownRoute{RouteInfo: t.routeInfo(nv)}
+
+		if target, exists := t.routes[nv.aliasOf]; exists {
+			r.aliasOf = target.pattern
+		}
+
+		if len(nv.params) > 0 {
+			r.example = t.exampleOf(nv)
+		}
+
+		routes = append(routes, r)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Pattern < routes[j].Pattern
+	})
+
+	return routes
+}
+
+// exampleOf returns the first example of the stored route, if any.
+// The caller must hold the read lock.
+func (t *Tree[T]) exampleOf(nv *NodeValue[T]) string {
+	path := findExactPath(t.root, nv.pattern)
+	if path == nil {
+		return ""
+	}
+
+	if examples := t.examples(path[len(path)-1], 1); len(examples) > 0 {
+		return examples[0]
+	}
+
+	return ""
+}
+
+func writeMarkdownRoute(sb *strings.Builder, r markdownRoute) {
+	fmt.Fprintf(sb, "\n## `%s`\n", r.Pattern)
+
+	if r.Description != "" {
+		fmt.Fprintf(sb, "\n%s\n", r.Description)
+	}
+
+	if len(r.Params) > 0 {
+		sb.WriteString("\nParams:\n\n")
+
+		for _, p := range r.Params {
+			fmt.Fprintf(sb, "- `%s`", p.Name)
+
+			if p.Matcher != "" {
+				fmt.Fprintf(sb, " – matcher `%s`", p.Matcher)
+			}
+
+			if p.CatchAll {
+				sb.WriteString(" – catch-all")
+			}
+
+			sb.WriteString("\n")
+		}
+	}
+
+	if r.example != "" {
+		fmt.Fprintf(sb, "\nExample: `%s`\n", r.example)
+	}
+
+	meta := markdownMeta(r)
+
+	if len(meta) > 0 {
+		sb.WriteString("\nMetadata:\n\n")
+
+		for _, m := range meta {
+			fmt.Fprintf(sb, "- %s\n", m)
+		}
+	}
+}
+
+// markdownMeta returns the list items of the metadata of the route.
+func markdownMeta(r markdownRoute) []string {
+	meta := make([]string, 0)
+
+	if r.aliasOf != "" {
+		meta = append(meta, fmt.Sprintf("alias of `%s`", r.aliasOf))
+	}
+
+	if r.Protected {
+		meta = append(meta, "protected")
+	}
+
+	if r.Source != "" {
+		meta = append(meta, fmt.Sprintf("source: %s", r.Source))
+	}
+
+	if r.Timeout > 0 {
+		meta = append(meta, fmt.Sprintf("timeout: %s", r.Timeout))
+	}
+
+	if r.Retry != nil {
+		meta = append(meta, fmt.Sprintf("retry: %d attempts, %s backoff, on %v", r.Retry.Attempts, r.Retry.Backoff, r.Retry.On))
+	}
+
+	if r.Sunset != nil {
+		meta = append(meta, fmt.Sprintf("sunset: %s", r.Sunset.Format(time.RFC3339)))
+	}
+
+	keys := make([]string, 0, len(r.Labels))
+
+	for k := range r.Labels {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, r.Labels[k]))
+	}
+
+	return meta
+}
@@ -0,0 +1,105 @@
+package rtree
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExportMarkdown(t *testing.T) {
+	tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+	spec, err := NewRoute("/api/users/{id}").Meta("team", "users").Meta("owner", "alice").Build()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertRoute(spec, getRoute(), WithDescription("Returns the user."), WithTimeout(5*time.Second)); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	inserts := []func() error{
+		func() error {
+			return tree.Insert("/{lang:lang}/files/{path...}", getRoute(), WithDescription("Serves the files."), WithSource("files.go:12"))
+		},
+		func() error {
+			return tree.InsertProtected("/health", getRoute(), WithRetry(RetryPolicy{Attempts: 2, Backoff: time.Second, On: []int{http.StatusBadGateway}}))
+		},
+		func() error {
+			return tree.Insert("/v1/users", getRoute(), WithSunset(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)))
+		},
+		func() error { return tree.Alias("/api/users/{id}", "/api/people/{id}") },
+	}
+
+	for _, insert := range inserts {
+		if err := insert(); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	expected := "# Endpoints\n" +
+		"\n## `/api/people/{id}`\n" +
+		"\nParams:\n\n" +
+		"- `id`\n" +
+		"\nExample: `/api/people/51915`\n" +
+		"\nMetadata:\n\n" +
+		"- alias of `/api/users/{id}`\n" +
+		"\n## `/api/users/{id}`\n" +
+		"\nReturns the user.\n" +
+		"\nParams:\n\n" +
+		"- `id`\n" +
+		"\nExample: `/api/users/98379`\n" +
+		"\nMetadata:\n\n" +
+		"- timeout: 5s\n" +
+		"- owner: alice\n" +
+		"- team: users\n" +
+		"\n## `/health`\n" +
+		"\nMetadata:\n\n" +
+		"- protected\n" +
+		"- retry: 2 attempts, 1s backoff, on [502]\n" +
+		"\n## `/v1/users`\n" +
+		"\nMetadata:\n\n" +
+		"- sunset: 2025-01-01T00:00:00Z\n" +
+		"\n## `/{lang:lang}/files/{path...}`\n" +
+		"\nServes the files.\n" +
+		"\nParams:\n\n" +
+		"- `lang` – matcher `lang`\n" +
+		"- `path` – catch-all\n" +
+		"\nMetadata:\n\n" +
+		"- source: files.go:12\n"
+
+	var buf bytes.Buffer
+
+	if err := tree.ExportMarkdown(&buf); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s\n", expected, got)
+	}
+
+	if got := tree.Find("/api/users/1").Description(); got != "Returns the user." {
+		t.Errorf("expected description: Returns the user.; got: %s\n", got)
+	}
+}
+
+func TestExportMarkdownEmpty(t *testing.T) {
+	var (
+		nilTree *Tree[*Route]
+		buf     bytes.Buffer
+	)
+
+	if err := nilTree.ExportMarkdown(&buf); !errors.Is(err, ErrTreeIsNil) {
+		t.Fatalf("expected error: %v; got: %v\n", ErrTreeIsNil, err)
+	}
+
+	if err := New[*Route]().ExportMarkdown(&buf); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := buf.String(); got != "# Endpoints\n" {
+		t.Fatalf("expected only the title; got: %q\n", got)
+	}
+}
@@ -0,0 +1,94 @@
+package rtree
+
+import "strings"
+
+const matcherSeparator = ':'
+
+// SegmentMatcher is the extension point of the matching engine.
+// A matcher decides whether a given path segment is acceptable for a
+// path param, and returns the value that should be captured for it.
+//
+// Matchers are referenced from the patterns by their registered name,
+// eg. /{lang:lang} means: path param called „lang” which is matched
+// by the matcher registered as „lang”.
+type SegmentMatcher interface {
+	Match(segment string) (ok bool, capture string)
+}
+
+// SegmentMatcherFunc is an adapter to allow the use of ordinary
+// functions as segment matchers.
+type SegmentMatcherFunc func(segment string) (bool, string)
+
+// Match calls f(segment).
+func (f SegmentMatcherFunc) Match(segment string) (bool, string) {
+	return f(segment)
+}
+
+// WithSegmentMatcher registers a custom matcher under the given name,
+// so patterns could reference it in the form of {param:name}.
+func WithSegmentMatcher[T storeValue](name string, m SegmentMatcher) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if t.matchers == nil {
+			t.matchers = make(map[string]SegmentMatcher)
+		}
+
+		t.matchers[name] = m
+	}
+}
+
+// splitParam splits the content of a path param – without the curly
+// brackets – into the name of the param and the name of its matcher.
+func splitParam(param string) (string, string) {
+	idx := strings.IndexRune(param, matcherSeparator)
+
+	if idx == -1 {
+		return param, ""
+	}
+
+	return param[:idx], param[idx+1:]
+}
+
+// matchSegments extracts the params of given key, and runs the param
+// policies, the constraints of the route and the registered matchers on
+// them. If any of them rejects its segment – or the referenced matcher
+// is not registered at all, or the segment is empty with WithNonEmptyParams
+// – the second return value is false.
+//
+// The segments are the split key, which could be shifted – see matchParamsIn.
+func (t *Tree[T]) matchSegments(nv *NodeValue[T], segments []string, shift int) (matchedParams, bool) {
+	mp := matchParamsIn(nv.params, segments, shift, t.segmentDelimiter())
+
+	checks := nv.checks
+
+	// The values created by newNodeValue are compiled at their insertion.
+	if checks == nil && len(nv.params) > 0 {
+		checks = t.compileParams(nv)
+	}
+
+	for i := range checks {
+		pc := &checks[i]
+
+		if pc.nonEmpty && mp[pc.key] == "" {
+			return nil, false
+		}
+
+		if pc.policy != nil && !pc.policy.check(mp[pc.key]) {
+			return nil, false
+		}
+
+		if pc.unknown {
+			return nil, false
+		}
+
+		for _, m := range pc.matchers {
+			ok, capture := m.Match(mp[pc.key])
+			if !ok {
+				return nil, false
+			}
+
+			mp[pc.key] = capture
+		}
+	}
+
+	return mp, true
+}
@@ -0,0 +1,141 @@
+package rtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var langMatcher = SegmentMatcherFunc(func(segment string) (bool, string) {
+	switch strings.ToLower(segment) {
+	case "en", "hu", "de":
+		return true, strings.ToLower(segment)
+	}
+	return false, ""
+})
+
+func TestSplitParam(t *testing.T) {
+	type testCase struct {
+		name            string
+		input           string
+		expectedName    string
+		expectedMatcher string
+	}
+
+	tt := []testCase{
+		{
+			name:            "no matcher in param",
+			input:           "id",
+			expectedName:    "id",
+			expectedMatcher: "",
+		},
+		{
+			name:            "matcher in param",
+			input:           "lang:lang",
+			expectedName:    "lang",
+			expectedMatcher: "lang",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gotName, gotMatcher := splitParam(tc.input)
+
+			if gotName != tc.expectedName {
+				t.Errorf("expected name: %s; got: %s\n", tc.expectedName, gotName)
+			}
+
+			if gotMatcher != tc.expectedMatcher {
+				t.Errorf("expected matcher: %s; got: %s\n", tc.expectedMatcher, gotMatcher)
+			}
+		})
+	}
+}
+
+func TestFindWithSegmentMatcher(t *testing.T) {
+	type testCase struct {
+		name           string
+		getTree        getTreeFn
+		searchKey      string
+		expectedName   string
+		expectedParams matchedParams
+	}
+
+	tt := []testCase{
+		{
+			name: "no match, if the matcher rejects the segment",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+				if err := tree.Insert("/{lang:lang}/docs", &Route{name: "docs"}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				return tree
+			},
+			searchKey:    "/fr/docs",
+			expectedName: "",
+		},
+		{
+			name: "match, with the captured value of the matcher",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+				if err := tree.Insert("/{lang:lang}/docs", &Route{name: "docs"}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				return tree
+			},
+			searchKey:      "/EN/docs",
+			expectedName:   "docs",
+			expectedParams: matchedParams{"lang": "en"},
+		},
+		{
+			name: "falls back to other branch, if the matcher rejects the segment",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+				if err := tree.Insert("/{lang:lang}/docs", &Route{name: "docs"}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				if err := tree.Insert("/{page}/docs", &Route{name: "page"}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				return tree
+			},
+			searchKey:      "/intro/docs",
+			expectedName:   "page",
+			expectedParams: matchedParams{"page": "intro"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := tc.getTree(t)
+
+			node := tree.Find(tc.searchKey)
+
+			if tc.expectedName == "" {
+				if node != nil {
+					t.Error("expected not to find, but got route")
+				}
+				return
+			}
+
+			if node == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := node.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+
+			if got := node.GetParams(); !reflect.DeepEqual(tc.expectedParams, got) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package rtree
+
+import (
+	"sort"
+	"unsafe"
+)
+
+type byteRange struct {
+	start uintptr
+	end   uintptr
+}
+
+// MemoryFootprint returns an estimation of the resident memory of the
+// tree in bytes. It involves the nodes, the stored values' wrappers, the
+// children and param slices and the bytes of the keys. Since the keys of
+// the nodes share their backing strings, overlapping bytes are only
+// counted once. The memory referenced by the stored values is not involved.
+func (t *Tree[T]) MemoryFootprint() int {
+	if err := checkTree(t); err != nil {
+		return 0
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var (
+		total  = 0
+		ranges = make([]byteRange, 0)
+	)
+
+	addString := func(s string) {
+		if s == "" {
+			return
+		}
+
+		start := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+
+		ranges = append(ranges, byteRange{start: start, end: start + uintptr(len(s))})
+	}
+
+	var rec func(n *Node[T])
+
+	rec = func(n *Node[T]) {
+		total += int(unsafe.Sizeof(*n))
+		total += cap(n.children) * int(unsafe.Sizeof(n))
+		total += cap(n.ops) * int(unsafe.Sizeof(keyOp{}))
+
+		if n.index != nil {
+			total += int(unsafe.Sizeof(*n.index))
+		}
+
+		addString(n.key)
+
+		if nv := n.value; nv != nil {
+			total += int(unsafe.Sizeof(*nv))
+			total += cap(nv.params) * int(unsafe.Sizeof(paramInfo{}))
+			total += cap(nv.checks) * int(unsafe.Sizeof(paramCheck{}))
+
+			addString(nv.pattern)
+			addString(nv.external)
+
+			for _, pi := range nv.params {
+				addString(pi.key)
+				addString(pi.matcher)
+			}
+		}
+
+		for _, ch := range n.children {
+			rec(ch)
+		}
+	}
+
+	if t.root != nil {
+		rec(t.root)
+	}
+
+	return total + countDistinctBytes(ranges)
+}
+
+// countDistinctBytes returns the number of bytes covered by the
+// union of the given ranges.
+func countDistinctBytes(ranges []byteRange) int {
+	if len(ranges) == 0 {
+		return 0
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	var (
+		total   = 0
+		current = ranges[0]
+	)
+
+	for _, r := range ranges[1:] {
+		if r.start <= current.end {
+			if r.end > current.end {
+				current.end = r.end
+			}
+			continue
+		}
+
+		total += int(current.end - current.start)
+		current = r
+	}
+
+	return total + int(current.end-current.start)
+}
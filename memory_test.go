@@ -0,0 +1,79 @@
+package rtree
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestMemoryFootprint(t *testing.T) {
+	var empty *Tree[*Route]
+
+	if got := empty.MemoryFootprint(); got != 0 {
+		t.Errorf("expected footprint: %d; got: %d\n", 0, got)
+	}
+
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/users/{id}", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	single := tree.MemoryFootprint()
+
+	// The key bytes are only counted once, despite being referenced by
+	// the node key, the pattern and the param name.
+	expected := int(unsafe.Sizeof(Node[*Route]{})) +
+		cap(compileKey("/api/users/{id}"))*int(unsafe.Sizeof(keyOp{})) +
+		int(unsafe.Sizeof(NodeValue[*Route]{})) +
+		int(unsafe.Sizeof(paramInfo{})) +
+		int(unsafe.Sizeof(paramCheck{})) +
+		len("/api/users/{id}")
+
+	if single != expected {
+		t.Errorf("expected footprint: %d; got: %d\n", expected, single)
+	}
+
+	if err := tree.Insert("/api/products", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := tree.MemoryFootprint(); got <= single {
+		t.Errorf("expected footprint to grow; got: %d <= %d\n", got, single)
+	}
+}
+
+func TestInsertSharesBackingString(t *testing.T) {
+	tree := New[*Route]()
+
+	// The caller's buffer must not be retained by the tree.
+	buffer := strings.Repeat("x", 1024) + "/api/users/{id}"
+
+	if err := tree.Insert(buffer[1024:], getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/api/products", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var (
+		users   = tree.Find("/api/users/1")
+		pattern = users.GetPattern()
+
+		start = uintptr(unsafe.Pointer(unsafe.StringData(pattern)))
+		end   = start + uintptr(len(pattern))
+	)
+
+	if bufStart := uintptr(unsafe.Pointer(unsafe.StringData(buffer))); start >= bufStart && start < bufStart+uintptr(len(buffer)) {
+		t.Error("expected the pattern not to share the buffer of the caller")
+	}
+
+	for _, n := range []*Node[*Route]{tree.root, tree.root.children[0]} {
+		p := uintptr(unsafe.Pointer(unsafe.StringData(n.key)))
+
+		if p < start || p >= end {
+			t.Errorf("expected node key %q to share the backing string of the pattern", n.key)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package rtree
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MethodTrees is a method-aware route table, layered as one tree per
+// HTTP method. Its trees are published together, so a lookup never sees
+// the tree of one method from a route table and the tree of an other
+// method from the next one. The zero value is an empty table ready to
+// use, while a nil table has no routes, and it could not be changed.
+type MethodTrees[T storeValue] struct {
+	// mu serializes the writers, while the readers only load the table.
+	mu      sync.Mutex
+	current atomic.Pointer[MethodTable[T]]
+	opts    []OptionFunc[T]
+	config  methodConfig
+}
+
+// MethodOption configures the lookups of MethodTrees, see SetOptions.
+type MethodOption[T storeValue] func(*methodConfig)
+
+// methodConfig is the behaviour of the lookups of the method tables.
+type methodConfig struct {
+	headFallback bool
+	autoOptions  bool
+}
+
+// WithHeadFallback makes the HEAD lookups without a HEAD route of their
+// own fall back to the GET routes, as HEAD is GET without the body.
+func WithHeadFallback[T storeValue]() MethodOption[T] {
+	return func(c *methodConfig) {
+		c.headFallback = true
+	}
+}
+
+// WithAutoOptions makes OPTIONS allowed for every known path, so the
+// OPTIONS requests without a route of their own could be answered by
+// the Allow set returned by FindMethod – which contains OPTIONS then.
+func WithAutoOptions[T storeValue]() MethodOption[T] {
+	return func(c *methodConfig) {
+		c.autoOptions = true
+	}
+}
+
+// MethodTable is an immutable set of per-method trees, so the lookups
+// done on the same table are consistent with each other. The trees
+// themselves could still be mutated, see MethodTrees.Insert.
+type MethodTable[T storeValue] struct {
+	trees  map[string]*Tree[T]
+	config methodConfig
+
+	// index is the path index of the trees, built by the
+	// first lookup of the allowed methods, see Allowed.
+	mu    sync.Mutex
+	index atomic.Pointer[methodIndex[T]]
+}
+
+// NewMethodTrees creates an empty method-aware route table. The given
+// options are applied to the trees created by Insert.
+func NewMethodTrees[T storeValue](opts ...OptionFunc[T]) *MethodTrees[T] {
+	mt := &MethodTrees[T]{
+		opts: opts,
+	}
+
+	mt.current.Store(&MethodTable[T]{trees: make(map[string]*Tree[T])})
+
+	return mt
+}
+
+// SetOptions changes the behaviour of the lookups. It publishes
+// a new table with the same trees, so the lookups in flight
+// finish with the previous behaviour.
+func (mt *MethodTrees[T]) SetOptions(opts ...MethodOption[T]) {
+	if mt == nil {
+		return
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	for _, o := range opts {
+		o(&mt.config)
+	}
+
+	mt.current.Store(&MethodTable[T]{trees: mt.Table().copyTrees(), config: mt.config})
+}
+
+// Table returns the current table. The table of the zero
+// value is created by the first call, while a nil MethodTrees
+// returns a new empty table on every call.
+func (mt *MethodTrees[T]) Table() *MethodTable[T] {
+	if mt == nil {
+		return &MethodTable[T]{}
+	}
+
+	if table := mt.current.Load(); table != nil {
+		return table
+	}
+
+	// Only the first of the concurrent callers publishes its table.
+	mt.current.CompareAndSwap(nil, &MethodTable[T]{trees: make(map[string]*Tree[T])})
+
+	return mt.current.Load()
+}
+
+// Insert stores the key-value pair in the tree of the given method,
+// creating the tree if needed. The change is visible immediately, so
+// a set of related changes of several methods should rather be built
+// in new trees, and published together by Publish.
+func (mt *MethodTrees[T]) Insert(method, key string, value T, opts ...RouteOption) error {
+	return mt.tree(method).Insert(key, value, opts...)
+}
+
+// tree returns the tree of the given method, creating it if needed.
+// A nil MethodTrees has no trees, so the returned tree is nil as well.
+func (mt *MethodTrees[T]) tree(method string) *Tree[T] {
+	if mt == nil {
+		return nil
+	}
+
+	method = normalizeMethod(method)
+
+	if t := mt.Table().trees[method]; t != nil {
+		return t
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	// Someone could have created it in the meantime.
+	table := mt.Table()
+	if t := table.trees[method]; t != nil {
+		return t
+	}
+
+	t := New(mt.opts...)
+
+	trees := table.copyTrees()
+	trees[method] = t
+
+	mt.current.Store(&MethodTable[T]{trees: trees, config: mt.config})
+
+	return t
+}
+
+// Publish replaces all the per-method trees in a single atomic step.
+// The lookups in flight finish on the previous table. The methods
+// missing from the given trees – or mapped to nil – have no routes in
+// the new table. The trees must not be published in an other table.
+func (mt *MethodTrees[T]) Publish(trees map[string]*Tree[T]) {
+	if mt == nil {
+		return
+	}
+
+	table := &MethodTable[T]{
+		trees: make(map[string]*Tree[T], len(trees)),
+	}
+
+	for method, t := range trees {
+		if t != nil {
+			table.trees[normalizeMethod(method)] = t
+		}
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	table.config = mt.config
+	mt.current.Store(table)
+}
+
+// Find searches for the key in the tree of the given method.
+func (mt *MethodTrees[T]) Find(method, key string) *FoundNode[T] {
+	return mt.Table().Find(method, key)
+}
+
+// Find searches for the key in the tree of the given method. With
+// WithHeadFallback, a HEAD lookup without match searches the GET tree.
+func (mt *MethodTable[T]) Find(method, key string) *FoundNode[T] {
+	if mt == nil {
+		return nil
+	}
+
+	method = normalizeMethod(method)
+
+	fn := mt.trees[method].Find(key)
+
+	if fn == nil && method == http.MethodHead && mt.config.headFallback {
+		return mt.trees[http.MethodGet].Find(key)
+	}
+
+	return fn
+}
+
+// Tree returns the tree of the given method, or nil if there is none.
+func (mt *MethodTable[T]) Tree(method string) *Tree[T] {
+	if mt == nil {
+		return nil
+	}
+
+	return mt.trees[normalizeMethod(method)]
+}
+
+// Methods returns the sorted methods with a tree.
+func (mt *MethodTable[T]) Methods() []string {
+	if mt == nil {
+		return make([]string, 0)
+	}
+
+	methods := make([]string, 0, len(mt.trees))
+
+	for method := range mt.trees {
+		methods = append(methods, method)
+	}
+
+	sort.Strings(methods)
+
+	return methods
+}
+
+func (mt *MethodTable[T]) copyTrees() map[string]*Tree[T] {
+	trees := make(map[string]*Tree[T], len(mt.trees)+1)
+
+	for method, t := range mt.trees {
+		trees[method] = t
+	}
+
+	return trees
+}
+
+// normalizeMethod returns the canonical – upper case – form of the method.
+func normalizeMethod(method string) string {
+	return strings.ToUpper(method)
+}
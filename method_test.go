@@ -0,0 +1,251 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestMethodTrees(t *testing.T) {
+	mt := NewMethodTrees[*Route]()
+
+	inserts := []struct {
+		method string
+		key    string
+		name   string
+	}{
+		{"GET", "/users/{id}", "get user"},
+		{"get", "/users", "list users"},
+		{"POST", "/users", "create user"},
+	}
+
+	for _, ins := range inserts {
+		if err := mt.Insert(ins.method, ins.key, &Route{name: ins.name}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	type testCase struct {
+		method       string
+		key          string
+		expectedName string
+	}
+
+	tt := []testCase{
+		{method: "GET", key: "/users/1", expectedName: "get user"},
+		{method: "Get", key: "/users", expectedName: "list users"},
+		{method: "POST", key: "/users", expectedName: "create user"},
+		{method: "POST", key: "/users/1", expectedName: ""},
+		{method: "DELETE", key: "/users/1", expectedName: ""},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.method+" "+tc.key, func(t *testing.T) {
+			fn := mt.Find(tc.method, tc.key)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil || fn.GetValue().name != tc.expectedName {
+				t.Fatalf("expected %s; got: %v\n", tc.expectedName, fn)
+			}
+		})
+	}
+
+	if methods := mt.Table().Methods(); !reflect.DeepEqual(methods, []string{"GET", "POST"}) {
+		t.Errorf("expected methods: [GET POST]; got: %v\n", methods)
+	}
+}
+
+func TestMethodTreesPublish(t *testing.T) {
+	mt := NewMethodTrees[*Route]()
+
+	// Every version has a GET and a PUT route with the same name.
+	version := func(v int) map[string]*Tree[*Route] {
+		trees := make(map[string]*Tree[*Route])
+
+		for _, method := range []string{"GET", "PUT"} {
+			tree := New[*Route]()
+
+			if err := tree.Insert("/config", &Route{name: strconv.Itoa(v)}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			trees[method] = tree
+		}
+
+		return trees
+	}
+
+	mt.Publish(version(0))
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				table := mt.Table()
+
+				get, put := table.Find("GET", "/config"), table.Find("PUT", "/config")
+
+				if get == nil || put == nil || get.GetValue().name != put.GetValue().name {
+					t.Errorf("expected the same version of both methods; got: %v, %v\n", get, put)
+					return
+				}
+			}
+		}()
+	}
+
+	for v := 1; v <= 100; v++ {
+		mt.Publish(version(v))
+	}
+
+	close(done)
+	wg.Wait()
+
+	if fn := mt.Find("GET", "/config"); fn == nil || fn.GetValue().name != "100" {
+		t.Fatalf("expected the last version; got: %v\n", fn)
+	}
+
+	// The methods missing from the published trees are gone.
+	mt.Publish(map[string]*Tree[*Route]{"get": version(101)["GET"], "PUT": nil})
+
+	if methods := mt.Table().Methods(); !reflect.DeepEqual(methods, []string{"GET"}) {
+		t.Errorf("expected methods: [GET]; got: %v\n", methods)
+	}
+}
+
+func TestMethodTreesHeadAndOptions(t *testing.T) {
+	mt := NewMethodTrees[*Route]()
+
+	for _, ins := range []struct{ method, key string }{
+		{"GET", "/users/{id}"},
+		{"DELETE", "/users/{id}"},
+		{"HEAD", "/files/{id}"},
+		{"GET", "/files/{id}"},
+	} {
+		if err := mt.Insert(ins.method, ins.key, &Route{name: ins.method}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// Without the options, HEAD and OPTIONS are like any other method.
+	if fn, allowed := mt.FindMethod("HEAD", "/users/1"); fn != nil || !reflect.DeepEqual(allowed, []string{"DELETE", "GET"}) {
+		t.Fatalf("expected no match with allowed [DELETE GET]; got: %v %v\n", fn, allowed)
+	}
+
+	table := mt.Table()
+
+	mt.SetOptions(WithHeadFallback[*Route](), WithAutoOptions[*Route]())
+
+	// The previous table keeps its behaviour.
+	if fn := table.Find("HEAD", "/users/1"); fn != nil {
+		t.Errorf("expected no match in the previous table; got: %s\n", fn.GetValue().name)
+	}
+
+	type testCase struct {
+		method          string
+		key             string
+		expectedName    string
+		expectedAllowed []string
+	}
+
+	tt := []testCase{
+		{method: "HEAD", key: "/users/1", expectedName: "GET"},
+		{method: "HEAD", key: "/files/1", expectedName: "HEAD"},
+		{method: "OPTIONS", key: "/users/1", expectedAllowed: []string{"DELETE", "GET", "HEAD", "OPTIONS"}},
+		{method: "POST", key: "/files/1", expectedAllowed: []string{"GET", "HEAD", "OPTIONS"}},
+		{method: "OPTIONS", key: "/unknown", expectedAllowed: []string{}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.method+" "+tc.key, func(t *testing.T) {
+			fn, allowed := mt.FindMethod(tc.method, tc.key)
+
+			if tc.expectedName != "" {
+				if fn == nil || fn.GetValue().name != tc.expectedName {
+					t.Fatalf("expected %s; got: %v\n", tc.expectedName, fn)
+				}
+				return
+			}
+
+			if fn != nil {
+				t.Fatalf("expected no match; got: %s\n", fn.GetValue().name)
+			}
+
+			if !reflect.DeepEqual(allowed, tc.expectedAllowed) {
+				t.Errorf("expected allowed: %v; got: %v\n", tc.expectedAllowed, allowed)
+			}
+		})
+	}
+
+	// The options are kept by the published tables as well.
+	mt.Publish(map[string]*Tree[*Route]{"GET": mt.Table().Tree("GET")})
+
+	if fn := mt.Find("HEAD", "/users/1"); fn == nil {
+		t.Errorf("expected the HEAD fallback after publishing\n")
+	}
+}
+
+func TestMethodTreesNilAndZeroValue(t *testing.T) {
+	var nilTrees *MethodTrees[*Route]
+
+	if err := nilTrees.Insert("GET", "/users", getRoute()); !errors.Is(err, ErrTreeIsNil) {
+		t.Errorf("expected error: %v; got: %v\n", ErrTreeIsNil, err)
+	}
+
+	if fn, allowed := nilTrees.FindMethod("GET", "/users"); fn != nil || len(allowed) != 0 {
+		t.Errorf("expected no match of a nil table; got: %v %v\n", fn, allowed)
+	}
+
+	var nilTable *MethodTable[*Route]
+
+	if methods := nilTable.Methods(); len(methods) != 0 {
+		t.Errorf("expected no methods of a nil table; got: %v\n", methods)
+	}
+
+	var (
+		zeroTrees MethodTrees[*Route]
+		wg        sync.WaitGroup
+	)
+
+	// The table of the zero value is created by the first of the callers.
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			if err := zeroTrees.Insert("GET", "/users/"+strconv.Itoa(i), getRoute()); err != nil {
+				t.Errorf("not expected error, but got: %v\n", err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := 0; i < 8; i++ {
+		if fn := zeroTrees.Find("GET", "/users/"+strconv.Itoa(i)); fn == nil {
+			t.Errorf("expected match of /users/%d\n", i)
+		}
+	}
+}
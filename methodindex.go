@@ -0,0 +1,253 @@
+package rtree
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// methodRoutes are the routes of a single pattern in the
+// per-method trees of a table, by their methods.
+type methodRoutes[T storeValue] struct {
+	routes map[string]*NodeValue[T]
+}
+
+// methodIndex is a single tree of all the patterns of a method table,
+// so the methods of a path are found by a single traversal, instead
+// of searching the tree of every method.
+type methodIndex[T storeValue] struct {
+	tree *Tree[*methodRoutes[T]]
+	// generations are the generations of the trees – in the
+	// order of the methods –, that the index was built of.
+	generations []uint64
+}
+
+// FindMethod searches for the key in the tree of the given method, and if
+// there is no match, it returns the sorted methods, that have a route for
+// the key. So an empty list means, that the path is unknown – 404 Not
+// Found –, while the others are 405 Method Not Allowed, with the methods
+// to be listed in the Allow header. With WithAutoOptions, an OPTIONS lookup
+// of a known path without an OPTIONS route results in the Allow set to be
+// answered with – containing OPTIONS itself.
+func (mt *MethodTrees[T]) FindMethod(method, key string) (*FoundNode[T], []string) {
+	return mt.Table().FindMethod(method, key)
+}
+
+// FindMethod searches for the key in the tree of the given method, and if
+// there is no match, it returns the sorted methods, that have a route for
+// the key. See MethodTrees.FindMethod.
+func (mt *MethodTable[T]) FindMethod(method, key string) (*FoundNode[T], []string) {
+	if fn := mt.Find(method, key); fn != nil {
+		return fn, nil
+	}
+
+	return nil, mt.Allowed(key)
+}
+
+// Allowed returns the sorted methods, that have a route for the key –
+// honouring the segment matchers and the trailing slash policy of their
+// trees –, by a single traversal of the path index of the table. The
+// index is rebuilt by the first call after any of the trees changed.
+// The HEAD and OPTIONS methods are added according to the options
+// of the table, see WithHeadFallback and WithAutoOptions.
+func (mt *MethodTable[T]) Allowed(key string) []string {
+	allowed := mt.allowed(key)
+
+	if len(allowed) == 0 {
+		return allowed
+	}
+
+	var implicit []string
+
+	if mt.config.headFallback && containsMethod(allowed, http.MethodGet) && !containsMethod(allowed, http.MethodHead) {
+		implicit = append(implicit, http.MethodHead)
+	}
+
+	if mt.config.autoOptions && !containsMethod(allowed, http.MethodOptions) {
+		implicit = append(implicit, http.MethodOptions)
+	}
+
+	if len(implicit) > 0 {
+		allowed = append(allowed, implicit...)
+		sort.Strings(allowed)
+	}
+
+	return allowed
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowed returns the sorted methods of the trees, that have a route for the key.
+func (mt *MethodTable[T]) allowed(key string) []string {
+	methods := mt.Methods()
+	if len(methods) == 0 || key == "" {
+		return nil
+	}
+
+	var (
+		idx = mt.currentIndex(methods)
+		// The trees of a table are expected to share their options,
+		// so any of them could tell how the keys are to be searched.
+		ref = mt.trees[methods[0]]
+	)
+
+	allowed := mt.allowedIn(idx, ref, key)
+
+	if len(allowed) == 0 && ref.trailingSlash != TrailingSlashStrict && len(key) > 1 && key[len(key)-1] == slash {
+		allowed = mt.allowedIn(idx, ref, key[:len(key)-1])
+	}
+
+	return allowed
+}
+
+// allowedIn collects the methods of every route in the index matching
+// the key. Unlike the lookups, the search does not stop at the first
+// match, since the routes of the methods could be different, eg. GET
+// /users/new and POST /users/{id} both match /users/new.
+func (mt *MethodTable[T]) allowedIn(idx *methodIndex[T], ref *Tree[T], key string) []string {
+	if idx.tree.root == nil || ref.runeMatching && !utf8.ValidString(key) {
+		return nil
+	}
+
+	var (
+		segments = strings.Split(key, string(ref.segmentDelimiter()))
+		found    = make(map[string]struct{})
+	)
+
+	accept := func(n *Node[*methodRoutes[T]]) bool {
+		for method, nv := range n.value.value.routes {
+			if _, exists := found[method]; exists {
+				continue
+			}
+
+			if mt.trees[method].allows(nv, segments) {
+				found[method] = struct{}{}
+			}
+		}
+
+		return false
+	}
+
+	findRec(idx.tree.root, ref.searchKey(key), false, &search[*methodRoutes[T]]{
+		accept: accept,
+		budget: &budget{limit: ref.backtrackBudget},
+	})
+
+	allowed := make([]string, 0, len(found))
+
+	for method := range found {
+		allowed = append(allowed, method)
+	}
+
+	sort.Strings(allowed)
+
+	return allowed
+}
+
+// allows reports whether any value of the pattern of the given value
+// – see WithGuard – matches the segments. The guards are evaluated on
+// no attributes, just like by Find, so a path of guarded routes only
+// is not known, rather than its method is not allowed.
+func (t *Tree[T]) allows(nv *NodeValue[T], segments []string) bool {
+	values := []*NodeValue[T]{nv}
+
+	for _, v := range nv.variants {
+		values = append(values, v.value)
+	}
+
+	for _, v := range values {
+		if v.meta.inactive() || !v.meta.guard.Eval(nil) {
+			continue
+		}
+
+		if _, ok := t.matchSegments(v, segments, 0); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// currentIndex returns the path index of the current state of the trees.
+func (mt *MethodTable[T]) currentIndex(methods []string) *methodIndex[T] {
+	if idx := mt.index.Load(); idx != nil && idx.current(mt, methods) {
+		return idx
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	// Someone could have rebuilt it in the meantime.
+	if idx := mt.index.Load(); idx != nil && idx.current(mt, methods) {
+		return idx
+	}
+
+	idx := mt.buildIndex(methods)
+
+	mt.index.Store(idx)
+
+	return idx
+}
+
+// current reports whether none of the trees changed since the index was built.
+func (idx *methodIndex[T]) current(mt *MethodTable[T], methods []string) bool {
+	for i, method := range methods {
+		if mt.trees[method].Generation() != idx.generations[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (mt *MethodTable[T]) buildIndex(methods []string) *methodIndex[T] {
+	var (
+		idx = &methodIndex[T]{
+			tree:        &Tree[*methodRoutes[T]]{},
+			generations: make([]uint64, len(methods)),
+		}
+
+		patterns = make(map[string]*methodRoutes[T])
+	)
+
+	for i, method := range methods {
+		t := mt.trees[method]
+
+		t.mu.RLock()
+
+		// The generation is read before the routes, so a mutation
+		// running at the same time could only make the index stale.
+		idx.generations[i] = t.Generation()
+
+		if t.root != nil {
+			for _, l := range getAllLeafRec(t.root) {
+				mr, exists := patterns[l.value.pattern]
+				if !exists {
+					mr = &methodRoutes[T]{routes: make(map[string]*NodeValue[T])}
+					patterns[l.value.pattern] = mr
+				}
+
+				mr.routes[method] = l.value
+			}
+		}
+
+		t.mu.RUnlock()
+	}
+
+	for pattern, mr := range patterns {
+		// The patterns are in their stored form already, and
+		// they are unique, so they could be stored as they are.
+		_ = idx.tree.store(createNewNodeValue(pattern, mr, getPathParams(pattern)))
+	}
+
+	return idx
+}
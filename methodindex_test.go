@@ -0,0 +1,192 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindMethod(t *testing.T) {
+	mt := NewMethodTrees(
+		WithSegmentMatcher[*Route]("lang", langMatcher),
+		WithTrailingSlash[*Route](TrailingSlashIgnore),
+	)
+
+	inserts := []struct {
+		method string
+		key    string
+	}{
+		{"GET", "/users/{id}"},
+		{"GET", "/users/new"},
+		{"POST", "/users/{id}"},
+		{"DELETE", "/users/{uid}"},
+		{"PUT", "/{lang:lang}/docs"},
+		{"GET", "/files/{path...}"},
+	}
+
+	for _, ins := range inserts {
+		if err := mt.Insert(ins.method, ins.key, &Route{name: ins.method + " " + ins.key}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	type testCase struct {
+		name            string
+		method          string
+		key             string
+		expectedName    string
+		expectedAllowed []string
+	}
+
+	tt := []testCase{
+		{
+			name:         "match",
+			method:       "POST",
+			key:          "/users/new",
+			expectedName: "POST /users/{id}",
+		},
+		{
+			name:            "methods of the different routes",
+			method:          "PATCH",
+			key:             "/users/new",
+			expectedAllowed: []string{"DELETE", "GET", "POST"},
+		},
+		{
+			name:            "methods of the catch-all",
+			method:          "POST",
+			key:             "/files/a/b",
+			expectedAllowed: []string{"GET"},
+		},
+		{
+			name:            "segment matchers decide",
+			method:          "GET",
+			key:             "/en/docs",
+			expectedAllowed: []string{"PUT"},
+		},
+		{
+			name:            "segment matchers reject",
+			method:          "GET",
+			key:             "/fr/docs",
+			expectedAllowed: []string{},
+		},
+		{
+			name:            "trailing slash policy",
+			method:          "PUT",
+			key:             "/users/1/",
+			expectedAllowed: []string{"DELETE", "GET", "POST"},
+		},
+		{
+			name:            "unknown path",
+			method:          "GET",
+			key:             "/posts/1",
+			expectedAllowed: []string{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn, allowed := mt.FindMethod(tc.method, tc.key)
+
+			if tc.expectedName != "" {
+				if fn == nil || fn.GetValue().name != tc.expectedName || allowed != nil {
+					t.Fatalf("expected %s; got: %v %v\n", tc.expectedName, fn, allowed)
+				}
+				return
+			}
+
+			if fn != nil {
+				t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+			}
+
+			if !reflect.DeepEqual(allowed, tc.expectedAllowed) {
+				t.Errorf("expected allowed: %v; got: %v\n", tc.expectedAllowed, allowed)
+			}
+		})
+	}
+}
+
+func TestAllowedIndexRebuild(t *testing.T) {
+	mt := NewMethodTrees[*Route]()
+
+	if err := mt.Insert("GET", "/users", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	table := mt.Table()
+
+	if allowed := table.Allowed("/users"); !reflect.DeepEqual(allowed, []string{"GET"}) {
+		t.Fatalf("expected allowed: [GET]; got: %v\n", allowed)
+	}
+
+	idx := table.index.Load()
+
+	// A lookup without change reuses the index.
+	table.Allowed("/users")
+
+	if table.index.Load() != idx {
+		t.Errorf("expected the index to be reused\n")
+	}
+
+	// A change of a tree – even a direct one – is seen by the next lookup.
+	if err := table.Tree("GET").Delete("/users"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if allowed := table.Allowed("/users"); len(allowed) != 0 {
+		t.Errorf("expected no allowed methods; got: %v\n", allowed)
+	}
+
+	if allowed := NewMethodTrees[*Route]().Table().Allowed("/users"); allowed != nil {
+		t.Errorf("expected no allowed methods of an empty table; got: %v\n", allowed)
+	}
+}
+
+func TestAllowedGuards(t *testing.T) {
+	mt := NewMethodTrees[*Route]()
+
+	beta, err := ParseGuard(`cookie.beta == "on"`)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	inserts := []struct {
+		method string
+		key    string
+		opts   []RouteOption
+	}{
+		{"GET", "/beta", []RouteOption{WithGuard(beta)}},
+		{"GET", "/users/{id}", []RouteOption{WithGuard(beta)}},
+		{"GET", "/users/{id}", nil},
+		{"POST", "/users/{id}", []RouteOption{WithGuard(beta)}},
+	}
+
+	for _, ins := range inserts {
+		if err := mt.Insert(ins.method, ins.key, &Route{}, ins.opts...); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []struct {
+		name     string
+		key      string
+		expected []string
+	}{
+		{
+			name:     "guarded routes only",
+			key:      "/beta",
+			expected: []string{},
+		},
+		{
+			name:     "guarded and unguarded values of a pattern",
+			key:      "/users/1",
+			expected: []string{"GET"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if allowed := mt.Table().Allowed(tc.key); !reflect.DeepEqual(allowed, tc.expected) {
+				t.Errorf("expected allowed: %v; got: %v\n", tc.expected, allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package rtree
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var errChiPattern = fmt.Errorf("%w: unsupported chi pattern", errBadPathParamSyntax)
+
+// RouteDefinition is a route registered in an other router, eg. an
+// element of the Routes of a gin engine, or a route visited by chi.Walk.
+type RouteDefinition struct {
+	Method string
+	Path   string
+	// Handler is the handler of the route, if the router has one in
+	// the form of an http.Handler – chi has, while gin does not.
+	Handler http.Handler
+}
+
+// ChiWalkFunc returns a walk function of chi, which appends the visited
+// routes to defs, so the routes of a chi router could be imported by
+// ImportChi without this package depending on chi:
+//
+//	var defs []rtree.RouteDefinition
+//	err := chi.Walk(r, rtree.ChiWalkFunc(&defs))
+func ChiWalkFunc(defs *[]RouteDefinition) func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+	return func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		// The middlewares of the route are applied the same way, as chi does.
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			handler = middlewares[i](handler)
+		}
+
+		*defs = append(*defs, RouteDefinition{Method: method, Path: route, Handler: handler})
+
+		return nil
+	}
+}
+
+// ImportColon registers the routes of httprouter or gin – in the syntax
+// of /files/:dir/*path – into the method trees, with the values returned
+// by value. The patterns are converted to the native syntax, so the trees
+// must use that. The report has an entry of every route, keyed by its
+// method and path, and the import does not stop at the first failure.
+func ImportColon[T storeValue](mt *MethodTrees[T], routes []RouteDefinition, value func(RouteDefinition) T) InsertReport {
+	return importRoutes(mt, routes, value, func(path string) (RouteSpec, error) {
+		return NewRoute(colonPattern(path)).Build()
+	})
+}
+
+// ImportChi registers the routes of chi into the method trees, with the
+// values returned by value, see ChiWalkFunc. The patterns are converted to
+// the native syntax – so the trees must use that –: the regular expressions
+// of the params become the constraints of the routes, and the trailing
+// wildcard becomes a catch-all param named *, as chi names it. Unlike the
+// wildcard, the catch-all does not match an empty rest: /static/* of chi
+// matches /static/, while the converted route does not – a route of
+// /static, with a trailing slash policy other than TrailingSlashStrict,
+// would match it, see WithTrailingSlash. The report has an entry of every
+// route, keyed by its method and path, and the import does not stop at
+// the first failure.
+func ImportChi[T storeValue](mt *MethodTrees[T], routes []RouteDefinition, value func(RouteDefinition) T) InsertReport {
+	return importRoutes(mt, routes, value, chiRoute)
+}
+
+func importRoutes[T storeValue](mt *MethodTrees[T], routes []RouteDefinition, value func(RouteDefinition) T, convert func(path string) (RouteSpec, error)) InsertReport {
+	report := make(InsertReport, 0, len(routes))
+
+	for _, r := range routes {
+		res := InsertResult{
+			Key: normalizeMethod(r.Method) + " " + r.Path,
+		}
+
+		spec, err := convert(r.Path)
+		if err == nil {
+			err = mt.tree(r.Method).InsertRoute(spec, value(r))
+		}
+
+		res.Err = err
+		res.Status = insertStatusOf(err)
+
+		report = append(report, res)
+	}
+
+	return report
+}
+
+// chiRoute converts the pattern of chi to a route spec.
+func chiRoute(path string) (RouteSpec, error) {
+	if path == "" {
+		return RouteSpec{}, ErrKeyIsEmpty
+	}
+
+	var (
+		sb          strings.Builder
+		constraints = make(map[string]SegmentMatcher)
+	)
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '*':
+			// The wildcard of chi could only end the pattern.
+			if i != len(path)-1 || (i > 0 && path[i-1] != slash) {
+				return RouteSpec{}, fmt.Errorf("%w: %s", errChiPattern, path)
+			}
+
+			sb.WriteString(string(curlyStart) + "*" + catchAllSuffix + string(curlyEnd))
+		case c == '{':
+			end := chiParamEnd(path, i)
+			if end == -1 {
+				return RouteSpec{}, fmt.Errorf("%w: %s", errChiPattern, path)
+			}
+
+			name, expr, hasExpr := strings.Cut(path[i+1:end], ":")
+
+			if hasExpr {
+				m, err := regexpMatcher(expr)
+				if err != nil {
+					return RouteSpec{}, fmt.Errorf("%w: %s: %w", errChiPattern, path, err)
+				}
+
+				constraints[name] = m
+			}
+
+			sb.WriteString(string(curlyStart) + name + string(curlyEnd))
+
+			i = end
+		default:
+			// The static parts are copied as they are, so their
+			// multi-byte chars are not split by the escaping.
+			end := strings.IndexAny(path[i:], "*{")
+			if end == -1 {
+				end = len(path) - i
+			}
+
+			sb.WriteString(escapeKey(path[i : i+end]))
+
+			i += end - 1
+		}
+	}
+
+	b := NewRoute(sb.String())
+
+	for name, m := range constraints {
+		b.Constraint(name, m)
+	}
+
+	return b.Build()
+}
+
+// chiParamEnd returns the index of the bracket closing the param
+// starting at the given index – the regular expressions could have
+// brackets of their own –, or -1 if there is none.
+func chiParamEnd(path string, start int) int {
+	depth := 0
+
+	for i := start; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// regexpMatcher returns the matcher of the regular expression of a
+// chi param, which – as chi does – has to match the whole segment.
+func regexpMatcher(expr string) (SegmentMatcher, error) {
+	if !strings.HasPrefix(expr, "^") {
+		expr = "^" + expr
+	}
+
+	if !strings.HasSuffix(expr, "$") {
+		expr += "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return SegmentMatcherFunc(func(segment string) (bool, string) {
+		return re.MatchString(segment), segment
+	}), nil
+}
@@ -0,0 +1,146 @@
+package rtree
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestImportColon(t *testing.T) {
+	var (
+		mt     = NewMethodTrees[string]()
+		routes = []RouteDefinition{
+			{Method: "GET", Path: "/users/:id"},
+			{Method: "POST", Path: "/users"},
+			{Method: "GET", Path: "/files/:dir/*path"},
+			{Method: "GET", Path: "/users/:id"},
+			{Method: "GET", Path: "users"},
+		}
+	)
+
+	report := ImportColon(mt, routes, func(r RouteDefinition) string { return r.Method + " " + r.Path })
+
+	statuses := make([]InsertStatus, 0, len(report))
+
+	for _, res := range report {
+		statuses = append(statuses, res.Status)
+	}
+
+	expected := []InsertStatus{StatusInserted, StatusInserted, StatusInserted, StatusConflict, StatusSyntaxError}
+
+	if !reflect.DeepEqual(expected, statuses) {
+		t.Fatalf("expected statuses: %v; got: %v\n", expected, statuses)
+	}
+
+	if report[0].Key != "GET /users/:id" {
+		t.Errorf("expected key: GET /users/:id; got: %s\n", report[0].Key)
+	}
+
+	fn := mt.Find("GET", "/files/docs/a/b.txt")
+	if fn == nil {
+		t.Fatal("expected match; got nil")
+	}
+
+	if fn.GetPattern() != "/files/{dir}/{path...}" || fn.GetValue() != "GET /files/:dir/*path" {
+		t.Errorf("unexpected match: %s -> %s\n", fn.GetPattern(), fn.GetValue())
+	}
+
+	if expected := (matchedParams{"dir": "docs", "path": "a/b.txt"}); !reflect.DeepEqual(expected, fn.GetParams()) {
+		t.Errorf("expected params: %v; got: %v\n", expected, fn.GetParams())
+	}
+}
+
+func TestImportChi(t *testing.T) {
+	var (
+		defs []RouteDefinition
+		walk = ChiWalkFunc(&defs)
+		ok   = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	)
+
+	header := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Middleware", "applied")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	visited := []struct {
+		method, route string
+		mws           []func(http.Handler) http.Handler
+	}{
+		{method: "GET", route: "/articles/{id:[0-9]+}"},
+		{method: "GET", route: "/articles/{slug}", mws: []func(http.Handler) http.Handler{header}},
+		{method: "GET", route: "/dates/{date:\\d{4}-\\d{2}}"},
+		{method: "GET", route: "/static/*"},
+		{method: "GET", route: "/café/{id}"},
+		{method: "GET", route: "/bad/{id:[}"},
+		{method: "GET", route: "/bad/*/more"},
+	}
+
+	for _, v := range visited {
+		if err := walk(v.method, v.route, ok, v.mws...); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	var (
+		mt     = NewMethodTrees[http.Handler]()
+		report = ImportChi(mt, defs, func(r RouteDefinition) http.Handler { return r.Handler })
+	)
+
+	statuses := make([]InsertStatus, 0, len(report))
+
+	for _, res := range report {
+		statuses = append(statuses, res.Status)
+	}
+
+	expected := []InsertStatus{StatusInserted, StatusInserted, StatusInserted, StatusInserted, StatusInserted, StatusSyntaxError, StatusSyntaxError}
+
+	if !reflect.DeepEqual(expected, statuses) {
+		t.Fatalf("expected statuses: %v; got: %v (%v)\n", expected, statuses, report.Failed())
+	}
+
+	tt := []struct {
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}{
+		{key: "/articles/42", expectedPattern: "/articles/{id}", expectedParams: matchedParams{"id": "42"}},
+		{key: "/articles/hello", expectedPattern: "/articles/{slug}", expectedParams: matchedParams{"slug": "hello"}},
+		{key: "/dates/2024-05", expectedPattern: "/dates/{date}", expectedParams: matchedParams{"date": "2024-05"}},
+		{key: "/dates/may", expectedPattern: ""},
+		{key: "/static/css/main.css", expectedPattern: "/static/{*...}", expectedParams: matchedParams{"*": "css/main.css"}},
+		// Unlike the wildcard of chi, the catch-all does not match an empty rest.
+		{key: "/static/", expectedPattern: ""},
+		{key: "/café/1", expectedPattern: "/café/{id}", expectedParams: matchedParams{"id": "1"}},
+	}
+
+	for _, tc := range tt {
+		fn := mt.Find("GET", tc.key)
+
+		if tc.expectedPattern == "" {
+			if fn != nil {
+				t.Errorf("%s: expected no match; got: %s\n", tc.key, fn.GetPattern())
+			}
+			continue
+		}
+
+		if fn == nil || fn.GetPattern() != tc.expectedPattern {
+			t.Errorf("%s: expected pattern: %s; got: %v\n", tc.key, tc.expectedPattern, fn)
+			continue
+		}
+
+		if !reflect.DeepEqual(tc.expectedParams, fn.GetParams()) {
+			t.Errorf("%s: expected params: %v; got: %v\n", tc.key, tc.expectedParams, fn.GetParams())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+
+	mt.Find("GET", "/articles/hello").GetValue().ServeHTTP(rec, httptest.NewRequest("GET", "/articles/hello", nil))
+
+	if got := rec.Header().Get("X-Middleware"); got != "applied" {
+		t.Errorf("expected the middleware of the route to be applied; got: %q\n", got)
+	}
+}
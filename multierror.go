@@ -0,0 +1,45 @@
+package rtree
+
+import "strings"
+
+// MultiError is the error of the operations, that go on after their
+// failures – eg. the batch inserts and the loaders –, holding each of
+// the failures. Since it unwraps to them, they could be matched by
+// errors.Is and errors.As, instead of parsing the concatenated message.
+type MultiError struct {
+	Errs []error
+}
+
+// newMultiError returns the MultiError of the given errors, without
+// the nil ones, or nil if there is no error at all.
+func newMultiError(errs []error) error {
+	failures := make([]error, 0, len(errs))
+
+	for _, err := range errs {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &MultiError{Errs: failures}
+}
+
+// Error returns the messages of the failures, one per line.
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errs))
+
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the failures, so errors.Is and errors.As examine each of them.
+func (e *MultiError) Unwrap() []error {
+	return e.Errs
+}
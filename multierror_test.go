@@ -0,0 +1,108 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMultiError(t *testing.T) {
+	var codec Codec[string] = JSONCodec[string]{}
+
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for k, v := range map[string]string{
+		"/api/users":     `"users"`,
+		"/api/{":         `"broken"`,
+		"/api/products":  `{`,
+		"/api/orders/{}": `"orders"`,
+	} {
+		if err := store.Save(k, []byte(v)); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	type testCase struct {
+		name string
+		run  func() error
+		// expected are the failures in order, nil matching any of them.
+		expected []error
+	}
+
+	tt := []testCase{
+		{
+			name: "no failures",
+			run: func() error {
+				return New[string]().InsertAll([]Entry[string]{{Key: "/a"}, {Key: "/a"}}).Err()
+			},
+			expected: nil,
+		},
+		{
+			name: "batch insert",
+			run: func() error {
+				tree := New[string]()
+
+				if err := tree.Insert("/api/users", "users"); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				return tree.InsertAll([]Entry[string]{
+					{Key: "/api/users", Value: "users"},
+					{Key: "/api/{id", Value: "broken"},
+					{Key: "/api/products", Value: "products"},
+				}).Err()
+			},
+			expected: []error{errKeyIsAlreadyStored, errBadPathParamSyntax},
+		},
+		{
+			name: "loader",
+			run: func() error {
+				return New(WithStore[string](store, codec)).Load()
+			},
+			expected: []error{errEmptyParamName, nil, errBadPathParamSyntax},
+		},
+		{
+			name: "validator",
+			run: func() error {
+				return New[string]().ValidatePatterns([]string{"/api/{id:unknown}", "/api/users", "api"})
+			},
+			expected: []error{errUnknownMatcher, errMissingSlashPrefix},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.run()
+
+			if tc.expected == nil {
+				if err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				return
+			}
+
+			var me *MultiError
+
+			if !errors.As(err, &me) {
+				t.Fatalf("expected a MultiError; got: %v\n", err)
+			}
+
+			if len(me.Errs) != len(tc.expected) {
+				t.Fatalf("expected %d failures; got: %d\n", len(tc.expected), len(me.Errs))
+			}
+
+			for i, expected := range tc.expected {
+				if expected != nil && !errors.Is(me.Errs[i], expected) {
+					t.Errorf("expected failure: %v; got: %v\n", expected, me.Errs[i])
+				}
+
+				if expected != nil && !errors.Is(err, expected) {
+					t.Errorf("expected the MultiError to match: %v\n", expected)
+				}
+			}
+		})
+	}
+}
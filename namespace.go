@@ -0,0 +1,88 @@
+package rtree
+
+import "sync"
+
+// Namespaces manages independent trees keyed by tenant ID,
+// with a shared fallback tree for the routes common to all tenants.
+type Namespaces[T storeValue] struct {
+	mu       sync.RWMutex
+	trees    map[string]*Tree[T]
+	fallback *Tree[T]
+	opts     []OptionFunc[T]
+}
+
+// NewNamespaces creates a new container. The given options are
+// applied to the fallback tree and to every tenant tree as well.
+func NewNamespaces[T storeValue](opts ...OptionFunc[T]) *Namespaces[T] {
+	return &Namespaces[T]{
+		trees:    make(map[string]*Tree[T]),
+		fallback: New(opts...),
+		opts:     opts,
+	}
+}
+
+// Fallback returns the shared tree, which is searched in case
+// the tree of the tenant has no match.
+func (ns *Namespaces[T]) Fallback() *Tree[T] {
+	return ns.fallback
+}
+
+// Tenant returns the tree of the given tenant, creating it if needed.
+func (ns *Namespaces[T]) Tenant(tenant string) *Tree[T] {
+	ns.mu.RLock()
+	t, exists := ns.trees[tenant]
+	ns.mu.RUnlock()
+
+	if exists {
+		return t
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	// Someone could have created it in the meantime.
+	if t, exists := ns.trees[tenant]; exists {
+		return t
+	}
+
+	t = New(ns.opts...)
+	ns.trees[tenant] = t
+
+	return t
+}
+
+// Remove drops the tree of the given tenant.
+func (ns *Namespaces[T]) Remove(tenant string) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	delete(ns.trees, tenant)
+}
+
+// Tenants returns the IDs of all the tenants with a tree.
+func (ns *Namespaces[T]) Tenants() []string {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	tenants := make([]string, 0, len(ns.trees))
+
+	for id := range ns.trees {
+		tenants = append(tenants, id)
+	}
+
+	return tenants
+}
+
+// FindTenant searches for the path in the tree of the tenant first,
+// then in the fallback tree. Unknown tenants only get the fallback.
+func (ns *Namespaces[T]) FindTenant(tenant, path string) *FoundNode[T] {
+	ns.mu.RLock()
+	t := ns.trees[tenant]
+	ns.mu.RUnlock()
+
+	if fn := t.Find(path); fn != nil {
+		return fn
+	}
+
+	return ns.fallback.Find(path)
+}
@@ -0,0 +1,102 @@
+package rtree
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestNamespacesFindTenant(t *testing.T) {
+	type testCase struct {
+		name         string
+		tenant       string
+		path         string
+		expectedName string
+	}
+
+	ns := NewNamespaces[*Route]()
+
+	inserts := []struct {
+		tree *Tree[*Route]
+		key  string
+		name string
+	}{
+		{tree: ns.Fallback(), key: "/health", name: "health"},
+		{tree: ns.Fallback(), key: "/api/users/{id}", name: "shared-users"},
+		{tree: ns.Tenant("acme"), key: "/api/users/{id}", name: "acme-users"},
+		{tree: ns.Tenant("acme"), key: "/api/reports", name: "acme-reports"},
+		{tree: ns.Tenant("globex"), key: "/api/billing", name: "globex-billing"},
+	}
+
+	for _, ins := range inserts {
+		if err := ins.tree.Insert(ins.key, &Route{name: ins.name}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:         "tenant route wins over fallback",
+			tenant:       "acme",
+			path:         "/api/users/1",
+			expectedName: "acme-users",
+		},
+		{
+			name:         "tenant miss resolves to fallback",
+			tenant:       "globex",
+			path:         "/api/users/1",
+			expectedName: "shared-users",
+		},
+		{
+			name:         "unknown tenant only gets fallback",
+			tenant:       "initech",
+			path:         "/health",
+			expectedName: "health",
+		},
+		{
+			name:         "routes of other tenants are not visible",
+			tenant:       "globex",
+			path:         "/api/reports",
+			expectedName: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := ns.FindTenant(tc.tenant, tc.path)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Error("expected not to find, but got route")
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+		})
+	}
+}
+
+func TestNamespacesTenants(t *testing.T) {
+	ns := NewNamespaces[*Route]()
+
+	if ns.Tenant("acme") != ns.Tenant("acme") {
+		t.Error("expected the same tree for the same tenant")
+	}
+
+	ns.Tenant("globex")
+	ns.Tenant("initech")
+	ns.Remove("initech")
+
+	got := ns.Tenants()
+	sort.Strings(got)
+
+	if len(got) != 2 || got[0] != "acme" || got[1] != "globex" {
+		t.Errorf("expected tenants: [acme globex]; got: %v\n", got)
+	}
+}
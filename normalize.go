@@ -0,0 +1,147 @@
+package rtree
+
+import "strings"
+
+// Normalize returns the canonical form of the pattern written in the
+// given syntax, so the patterns stored by external systems could be
+// compared consistently. It is the pattern, that a tree of the syntax
+// stores for it – in the native curly syntax, so the params of the colon
+// syntax are converted, while its literal brackets are escaped –, but the
+// surrounding spaces and the trailing slash are trimmed beforehand. The
+// percent-encodings of the static parts are normalized: the unreserved
+// chars are decoded, while the hex digits of the rest are upper-cased, eg.:
+//
+//	Normalize(" /api/%7eusers/:id/ ", SyntaxColon) → "/api/~users/{id}"
+//
+// The error is returned, if the pattern is not valid in the syntax.
+// Unlike Insert, it does not check the matchers of the params.
+func Normalize(pattern string, syntax PatternSyntax) (string, error) {
+	t, err := NewChecked(WithPatternSyntax[struct{}](syntax))
+	if err != nil {
+		return "", err
+	}
+
+	pattern = strings.TrimSpace(pattern)
+
+	if !syntax.isTopic() && len(pattern) > 1 {
+		pattern = strings.TrimRight(pattern, string(slash))
+	}
+
+	if pattern == "" {
+		return "", ErrKeyIsEmpty
+	}
+
+	// The matchers are registered per tree, so their names are not checked.
+	pattern = t.normalizePattern(pattern)
+
+	if err := t.checkKey(pattern); err != nil {
+		return "", err
+	}
+
+	return pattern, nil
+}
+
+// normalizePatternPercent normalizes the percent-encodings of the
+// static parts of the pattern, leaving the params untouched.
+func normalizePatternPercent(pattern string) string {
+	if strings.IndexByte(pattern, '%') == -1 {
+		return pattern
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(pattern))
+
+	for pattern != "" {
+		start := indexUnescaped(pattern, curlyStart)
+		if start == -1 {
+			sb.WriteString(normalizePercent(pattern))
+			break
+		}
+
+		end := indexUnescaped(pattern[start:], curlyEnd)
+		if end == -1 {
+			end = len(pattern) - start - 1
+		}
+
+		sb.WriteString(normalizePercent(pattern[:start]))
+		sb.WriteString(pattern[start : start+end+1])
+
+		pattern = pattern[start+end+1:]
+	}
+
+	return sb.String()
+}
+
+// normalizePercent decodes the percent-encoded unreserved chars
+// of s – as RFC 3986 allows –, and upper-cases the hex digits of
+// the other encodings. The malformed encodings are kept as they are.
+func normalizePercent(s string) string {
+	if strings.IndexByte(s, '%') == -1 {
+		return s
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if !isPercentEncoded(s, i) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		if isDecodedPercent(s, i) {
+			sb.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+		} else {
+			sb.WriteByte('%')
+			sb.WriteByte(upperHex(s[i+1]))
+			sb.WriteByte(upperHex(s[i+2]))
+		}
+
+		i += 2
+	}
+
+	return sb.String()
+}
+
+// isPercentEncoded reports whether a well-formed percent-encoding
+// starts at the given index of s.
+func isPercentEncoded(s string, i int) bool {
+	return s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2])
+}
+
+// isDecodedPercent reports whether the percent-encoding starting
+// at the given index of s is decoded by normalizePercent.
+func isDecodedPercent(s string, i int) bool {
+	return isPercentEncoded(s, i) && isUnreserved(unhex(s[i+1])<<4|unhex(s[i+2]))
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
+
+func upperHex(c byte) byte {
+	if c >= 'a' && c <= 'f' {
+		return c - 'a' + 'A'
+	}
+
+	return c
+}
+
+// isUnreserved reports whether the char is unreserved in the URLs.
+func isUnreserved(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
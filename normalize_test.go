@@ -0,0 +1,227 @@
+package rtree
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	type testCase struct {
+		name          string
+		pattern       string
+		syntax        PatternSyntax
+		expected      string
+		expectedError error
+	}
+
+	tt := []testCase{
+		{
+			name:          "empty pattern",
+			pattern:       "  ",
+			expectedError: ErrKeyIsEmpty,
+		},
+		{
+			name:     "already canonical",
+			pattern:  "/api/users/{id}",
+			expected: "/api/users/{id}",
+		},
+		{
+			name:     "root",
+			pattern:  " / ",
+			expected: "/",
+		},
+		{
+			name:     "spaces and trailing slash",
+			pattern:  " /api/users/ ",
+			expected: "/api/users",
+		},
+		{
+			name:     "colon syntax",
+			pattern:  "/api/users/:id/files/*path/",
+			syntax:   SyntaxColon,
+			expected: "/api/users/{id}/files/{path...}",
+		},
+		{
+			name:     "literal brackets of the colon syntax",
+			pattern:  "/api/{v}/:id",
+			syntax:   SyntaxColon,
+			expected: `/api/\{v\}/{id}`,
+		},
+		{
+			name:     "colon in the native syntax",
+			pattern:  "/api/:id",
+			expected: "/api/:id",
+		},
+		{
+			name:     "topic syntax",
+			pattern:  " sport/+/%7e/ ",
+			syntax:   SyntaxMQTT,
+			expected: "sport/{1}/%7e/",
+		},
+		{
+			name:     "percent-encodings",
+			pattern:  "/api/%7eusers/caf%c3%a9/%zz",
+			expected: "/api/~users/caf%C3%A9/%zz",
+		},
+		{
+			name:     "params are untouched",
+			pattern:  "/api/%2d/{id:%ab}",
+			expected: "/api/-/{id:%ab}",
+		},
+		{
+			name:          "missing slash",
+			pattern:       "api/users",
+			expectedError: errMissingSlashPrefix,
+		},
+		{
+			name:          "bad param syntax",
+			pattern:       "/api/{id",
+			expectedError: errBadPathParamSyntax,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Normalize(tc.pattern, tc.syntax)
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.expectedError, err)
+			}
+
+			if got != tc.expected {
+				t.Errorf("expected pattern: %q; got: %q\n", tc.expected, got)
+			}
+
+			if err != nil {
+				return
+			}
+
+			// A tree of the syntax stores the same pattern.
+			tree := New[string](WithPatternSyntax[string](tc.syntax))
+
+			pattern := strings.TrimSpace(tc.pattern)
+			if !tc.syntax.isTopic() && len(pattern) > 1 {
+				pattern = strings.TrimRight(pattern, "/")
+			}
+
+			if stored := tree.normalizePattern(pattern); stored != got {
+				t.Errorf("expected %q to be stored as %q; got: %q\n", tc.pattern, got, stored)
+			}
+		})
+	}
+}
+
+func TestPercentNormalizedRoutes(t *testing.T) {
+	tree := New[string]()
+
+	for k, v := range map[string]string{
+		"/%7eusers/{id}": "users",
+		"/caf%c3%a9":     "cafe",
+	} {
+		if err := tree.Insert(k, v); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := tree.Insert("/caf%C3%A9", "cafe"); !errors.Is(err, errKeyIsAlreadyStored) {
+		t.Errorf("expected error: %v; got: %v\n", errKeyIsAlreadyStored, err)
+	}
+
+	for key, expected := range map[string]string{
+		"/~users/1":   "users",
+		"/%7Eusers/1": "users",
+		"/caf%C3%A9":  "cafe",
+		"/caf%c3%a9":  "cafe",
+	} {
+		fn := tree.Find(key)
+		if fn == nil {
+			t.Fatalf("expected to find %s, but got <nil>\n", key)
+		}
+
+		if got := fn.GetValue(); got != expected {
+			t.Errorf("expected value of %s: %q; got: %q\n", key, expected, got)
+		}
+	}
+
+	if fn := tree.Find("/caf%c3%a9/x"); fn != nil {
+		t.Errorf("expected <nil>; got: %v\n", fn)
+	}
+}
+
+func TestPercentNormalizedLookups(t *testing.T) {
+	const (
+		key  = "/fil%65s/a%2fb"
+		name = "a%2fb"
+	)
+
+	tree := New[string](WithLookupCache[string](8))
+
+	for k, v := range map[string]string{
+		"/files/{name}": "files",
+		"/svc":          "svc",
+		"/off":          "off",
+	} {
+		if err := tree.Insert(k, v); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	fn := tree.Find(key)
+	if fn == nil {
+		t.Fatalf("expected to find %s, but got <nil>\n", key)
+	}
+
+	if got := fn.GetParams()["name"]; got != name {
+		t.Errorf("expected param of Find: %q; got: %q\n", name, got)
+	}
+
+	h := tree.Resolve(key)
+	if h == nil {
+		t.Fatalf("expected to resolve %s, but got <nil>\n", key)
+	}
+
+	if got := h.Params(key)["name"]; got != name {
+		t.Errorf("expected param of Resolve: %q; got: %q\n", name, got)
+	}
+
+	fm := writeFlatFile(t, tree).Find(key)
+	if fm == nil {
+		t.Fatalf("expected to find %s in the flat tree, but got <nil>\n", key)
+	}
+
+	if got := fm.Params["name"]; got != name {
+		t.Errorf("expected param of the flat tree: %q; got: %q\n", name, got)
+	}
+
+	if got := tree.Warm([]string{key, "/sv%63"}); got != 2 {
+		t.Errorf("expected warmed: 2; got: %d\n", got)
+	}
+
+	lm := tree.FindLongestMatch("/sv%63/x/y")
+	if lm == nil {
+		t.Fatal("expected to find the longest match, but got <nil>")
+	}
+
+	if lm.BasePath() != "/sv%63" || lm.Rest() != "/x/y" {
+		t.Errorf("expected base path and rest: /sv%%63, /x/y; got: %s, %s\n", lm.BasePath(), lm.Rest())
+	}
+
+	if err := tree.Disable("/off"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if _, err := tree.FindE("/%6fff"); !errors.Is(err, ErrRouteDisabled) {
+		t.Errorf("expected error: %v; got: %v\n", ErrRouteDisabled, err)
+	}
+
+	mt := NewMethodTrees[string]()
+
+	if err := mt.Insert("GET", "/files/{name}", "files"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := mt.Table().Allowed(key); len(got) != 1 || got[0] != "GET" {
+		t.Errorf("expected allowed: [GET]; got: %v\n", got)
+	}
+}
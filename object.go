@@ -0,0 +1,23 @@
+package rtree
+
+// WithObjectKeys makes the tree index arbitrary keys – eg. the object
+// keys of a bucket, like photos/{year}/{name} –, instead of URLs: the
+// keys do not have to start with a slash, and they could end with one.
+// The keys are still split into segments by the slashes, and the syntax
+// of the params is the same. The keys with and without a trailing slash
+// are distinct, so the trailing slash policy should be left strict.
+func WithObjectKeys[T storeValue]() OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.objectKeys = true
+	}
+}
+
+// checkKey checks the given – already normalized – pattern, as a URL,
+// or only the syntax of its params in the object key mode.
+func (t *Tree[T]) checkKey(key string) error {
+	if t.objectKeys {
+		return checkPathParams(key)
+	}
+
+	return checkUrl(key)
+}
@@ -0,0 +1,129 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestObjectKeys(t *testing.T) {
+	type testCase struct {
+		name            string
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	tree := New(WithObjectKeys[*Route]())
+
+	patterns := []string{
+		"photos/{year}/{name}",
+		"docs/",
+		"docs/readme.md",
+		"backups/{path...}",
+		"/legacy/{id}",
+	}
+
+	for _, p := range patterns {
+		if err := tree.Insert(p, &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:            "params without leading slash",
+			key:             "photos/2024/cat.jpg",
+			expectedPattern: "photos/{year}/{name}",
+			expectedParams:  matchedParams{"year": "2024", "name": "cat.jpg"},
+		},
+		{
+			name:            "trailing slash",
+			key:             "docs/",
+			expectedPattern: "docs/",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "static key",
+			key:             "docs/readme.md",
+			expectedPattern: "docs/readme.md",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "catch-all",
+			key:             "backups/2024/01/db.tar",
+			expectedPattern: "backups/{path...}",
+			expectedParams:  matchedParams{"path": "2024/01/db.tar"},
+		},
+		{
+			name:            "leading slash",
+			key:             "/legacy/5",
+			expectedPattern: "/legacy/{id}",
+			expectedParams:  matchedParams{"id": "5"},
+		},
+		{
+			name: "no trailing slash is an other key",
+			key:  "docs",
+		},
+		{
+			name: "no match",
+			key:  "videos/1",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.Find(tc.key)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+			}
+		})
+	}
+
+	if fn := tree.FindSegments([]string{"photos", "2024", "dog.jpg"}); fn == nil || fn.GetParams()["name"] != "dog.jpg" {
+		t.Fatalf("expected match of the segments; got: %v\n", fn)
+	}
+
+	// The empty root is merged away, once a single branch remains.
+	for _, p := range patterns[1:] {
+		if err := tree.Delete(p); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if tree.root.key != patterns[0] {
+		t.Fatalf("expected root key: %s; got: %s\n", patterns[0], tree.root.key)
+	}
+
+	if fn := tree.Find("photos/2024/cat.jpg"); fn == nil {
+		t.Fatalf("expected match; got <nil>\n")
+	}
+}
+
+func TestObjectKeysValidation(t *testing.T) {
+	for _, key := range []string{"bucket/object", "bucket/prefix/"} {
+		if err := New[*Route]().Insert(key, &Route{}); err == nil {
+			t.Errorf("expected error of the url %s; got <nil>\n", key)
+		}
+	}
+
+	if err := New(WithObjectKeys[*Route]()).Insert("bucket/{id", &Route{}); !errors.Is(err, errBadPathParamSyntax) {
+		t.Errorf("expected error: %v; got: %v\n", errBadPathParamSyntax, err)
+	}
+}
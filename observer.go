@@ -0,0 +1,115 @@
+package rtree
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// FindObserver is called after every lookup with the searched key,
+// whether there was a match and the duration of the lookup.
+type FindObserver func(key string, matched bool, d time.Duration)
+
+// Metrics counts the lookups of a tree. It is safe for concurrent use,
+// so it could be read – eg. by a metrics exporter – while the tree serves.
+type Metrics struct {
+	lookups   atomic.Uint64
+	misses    atomic.Uint64
+	cacheHits atomic.Uint64
+	duration  atomic.Int64
+}
+
+// Lookups returns the number of the lookups.
+func (m *Metrics) Lookups() uint64 {
+	return m.lookups.Load()
+}
+
+// Misses returns the number of the lookups without match.
+func (m *Metrics) Misses() uint64 {
+	return m.misses.Load()
+}
+
+// CacheHits returns the number of the lookups served by the lookup cache.
+func (m *Metrics) CacheHits() uint64 {
+	return m.cacheHits.Load()
+}
+
+// Duration returns the total duration of the lookups.
+func (m *Metrics) Duration() time.Duration {
+	return time.Duration(m.duration.Load())
+}
+
+// observe records a lookup. It is a no-op on a nil receiver.
+func (m *Metrics) observe(matched bool, d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.lookups.Add(1)
+	m.duration.Add(int64(d))
+
+	if !matched {
+		m.misses.Add(1)
+	}
+}
+
+// cacheHit records a lookup served by the cache. It is a no-op on a nil receiver.
+func (m *Metrics) cacheHit() {
+	if m != nil {
+		m.cacheHits.Add(1)
+	}
+}
+
+// WithFindObserver sets the observer of the lookups, so the
+// users could feed their own metrics systems.
+func WithFindObserver[T storeValue](fn FindObserver) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.findObserver = fn
+	}
+}
+
+// WithMetrics makes the tree count its lookups in the given metrics.
+// The same metrics could be shared by several trees.
+func WithMetrics[T storeValue](m *Metrics) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if m == nil {
+			t.invalidOption("metrics", m)
+			return
+		}
+
+		t.metrics = m
+	}
+}
+
+// observe runs the given lookup, and reports it to the observer and
+// the metrics of the tree – if there is any. The lookup – but not the
+// observer – holds the read lock, if the tree is configured so.
+func (t *Tree[T]) observe(key string, lookup func(string) *FoundNode[T]) *FoundNode[T] {
+	if t.readLocking {
+		unlocked := lookup
+
+		lookup = func(key string) *FoundNode[T] {
+			t.mu.RLock()
+			defer t.mu.RUnlock()
+
+			return unlocked(key)
+		}
+	}
+
+	if t.findObserver == nil && t.metrics == nil {
+		return lookup(key)
+	}
+
+	start := time.Now()
+
+	fn := lookup(key)
+
+	d := time.Since(start)
+
+	t.metrics.observe(fn != nil, d)
+
+	if t.findObserver != nil {
+		t.findObserver(key, fn != nil, d)
+	}
+
+	return fn
+}
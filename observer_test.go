@@ -0,0 +1,76 @@
+package rtree
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithFindObserver(t *testing.T) {
+	type observation struct {
+		key     string
+		matched bool
+	}
+
+	type testCase struct {
+		name     string
+		lookup   func(tree *Tree[*Route])
+		expected []observation
+	}
+
+	tt := []testCase{
+		{
+			name:     "Find with match",
+			lookup:   func(tree *Tree[*Route]) { tree.Find("/api/users/1") },
+			expected: []observation{{key: "/api/users/1", matched: true}},
+		},
+		{
+			name:     "Find without match",
+			lookup:   func(tree *Tree[*Route]) { tree.Find("/api/products/1") },
+			expected: []observation{{key: "/api/products/1", matched: false}},
+		},
+		{
+			name:     "FindLongestMatch with match",
+			lookup:   func(tree *Tree[*Route]) { tree.FindLongestMatch("/api/service/foo") },
+			expected: []observation{{key: "/api/service/foo", matched: true}},
+		},
+		{
+			name:     "FindWithFallback reported once",
+			lookup:   func(tree *Tree[*Route]) { tree.FindWithFallback("/api/products/1", "/api/service") },
+			expected: []observation{{key: "/api/products/1", matched: true}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []observation
+
+			observer := func(key string, matched bool, d time.Duration) {
+				if d < 0 {
+					t.Errorf("expected non-negative duration; got: %v\n", d)
+				}
+
+				got = append(got, observation{key: key, matched: matched})
+			}
+
+			tree := New(WithFindObserver[*Route](observer))
+
+			for _, r := range []string{"/api/users/{id}", "/api/service"} {
+				if err := tree.Insert(r, getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			tc.lookup(tree)
+
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected observations: %v; got: %v\n", tc.expected, got)
+			}
+
+			for i, o := range tc.expected {
+				if got[i] != o {
+					t.Errorf("expected observation: %v; got: %v\n", o, got[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package rtree
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	errConflictingOptions = fmt.Errorf("[rtree %s]: conflicting options", version)
+	errInvalidOption      = fmt.Errorf("[rtree %s]: invalid option", version)
+)
+
+// TrailingSlashPolicy decides how the lookups treat a trailing slash of
+// the key, that has no match as it is, eg. /api/users/ for /api/users.
+type TrailingSlashPolicy uint8
+
+const (
+	// TrailingSlashStrict never matches a key with a trailing slash
+	// – except the root –, since the stored keys could not have one.
+	TrailingSlashStrict TrailingSlashPolicy = iota
+	// TrailingSlashIgnore matches the key without its trailing slash.
+	TrailingSlashIgnore
+	// TrailingSlashRedirect matches the key without its trailing slash
+	// as well, but the result is a permanent redirect to that key.
+	TrailingSlashRedirect
+)
+
+// PatternSyntax is the syntax of the params in the inserted patterns.
+type PatternSyntax uint8
+
+const (
+	// SyntaxCurly is the native syntax: /files/{dir}/{path...}.
+	SyntaxCurly PatternSyntax = iota
+	// SyntaxColon is the syntax of httprouter and gin: /files/:dir/*path.
+	// Only the segments starting with a colon or an asterisk are params,
+	// so the curly brackets of the other segments are literal ones.
+	// The patterns are stored – and reported – in the native syntax.
+	SyntaxColon
+	// SyntaxMQTT is the syntax of the MQTT topic filters: sport/+/player/#.
+	// A + segment is a single level param, named by the index of its level,
+	// while the # – as the last segment – matches any number of levels,
+	// even none: sport/# matches sport as well. It is captured by the
+	// param named #. The keys do not have to start with a slash.
+	SyntaxMQTT
+	// SyntaxAMQP is the same as SyntaxMQTT, but the single level param
+	// is the * segment, as in the AMQP topic exchanges. The delimiter of
+	// their levels – the dot – should be set by WithDelimiters.
+	SyntaxAMQP
+)
+
+// NewChecked is the same as New, but it fails if the options are
+// invalid or mutually exclusive – eg. the same policy is set twice
+// with different values –, while New silently applies the last one.
+func NewChecked[T storeValue](opts ...OptionFunc[T]) (*Tree[T], error) {
+	t := New(opts...)
+
+	if len(t.optionErrs) > 0 {
+		return nil, newMultiError(t.optionErrs)
+	}
+
+	return t, nil
+}
+
+// setOption records the value of the given exclusive option,
+// and reports the conflict, if it was already set to an other value.
+func (t *Tree[T]) setOption(name string, value any) {
+	if t.options == nil {
+		t.options = make(map[string]any)
+	}
+
+	if prev, exists := t.options[name]; exists && prev != value {
+		t.optionErrs = append(t.optionErrs, fmt.Errorf("%w: %s is set to both %v and %v", errConflictingOptions, name, prev, value))
+	}
+
+	t.options[name] = value
+}
+
+// invalidOption reports the invalid value of the given option.
+func (t *Tree[T]) invalidOption(name string, value any) {
+	t.optionErrs = append(t.optionErrs, fmt.Errorf("%w: %s could not be %v", errInvalidOption, name, value))
+}
+
+// WithCaseInsensitive makes the static parts of the patterns match
+// regardless of the ASCII case, eg. /API/Users matches /api/users.
+// The patterns are stored in lower case, while the captured params
+// keep the case of the key.
+func WithCaseInsensitive[T storeValue]() OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.caseInsensitive = true
+	}
+}
+
+// WithTrailingSlash sets the trailing slash policy of the lookups.
+// By default it is TrailingSlashStrict.
+func WithTrailingSlash[T storeValue](policy TrailingSlashPolicy) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if policy > TrailingSlashRedirect {
+			t.invalidOption("trailing slash policy", policy)
+			return
+		}
+
+		t.setOption("trailing slash policy", policy)
+		t.trailingSlash = policy
+	}
+}
+
+// WithReadLocking makes the lookups hold the read lock of the tree. By
+// default only the mutations are locked, so a lookup running at the same
+// time as a mutation is a data race – its behavior is undefined, and the
+// race detector reports it. Without this option, the tree must not be
+// mutated while it is searched; with it, a lookup either sees a mutation
+// fully or not at all.
+func WithReadLocking[T storeValue]() OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.readLocking = true
+	}
+}
+
+// WithPatternSyntax sets the syntax of the inserted patterns,
+// and of the patterns given to Delete and Alias as well.
+// By default it is SyntaxCurly.
+func WithPatternSyntax[T storeValue](syntax PatternSyntax) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if syntax > SyntaxAMQP {
+			t.invalidOption("pattern syntax", syntax)
+			return
+		}
+
+		t.setOption("pattern syntax", syntax)
+		t.syntax = syntax
+
+		// The topics are not URLs.
+		if syntax.isTopic() {
+			t.objectKeys = true
+		}
+	}
+}
+
+// normalizePattern converts the given pattern to the stored form,
+// according to the delimiters, the syntax and the case policy of the
+// tree. The percent-encodings of the URLs are normalized, see Normalize.
+func (t *Tree[T]) normalizePattern(pattern string) string {
+	pattern = t.convertSyntax(t.canonicalPattern(pattern))
+
+	if !t.objectKeys {
+		pattern = normalizePatternPercent(pattern)
+	}
+
+	if t.caseInsensitive {
+		pattern = foldPattern(pattern)
+	}
+
+	return pattern
+}
+
+// convertSyntax converts the given pattern to the native syntax.
+func (t *Tree[T]) convertSyntax(pattern string) string {
+	if t == nil || t.syntax == SyntaxCurly {
+		return pattern
+	}
+
+	if t.syntax.isTopic() {
+		return t.convertTopic(pattern)
+	}
+
+	return colonPattern(pattern)
+}
+
+// colonPattern converts the pattern of SyntaxColon to the native syntax.
+func colonPattern(pattern string) string {
+	segments := strings.Split(pattern, string(slash))
+
+	for i, s := range segments {
+		switch {
+		case len(s) > 1 && s[0] == ':':
+			segments[i] = string(curlyStart) + s[1:] + string(curlyEnd)
+		case len(s) > 1 && s[0] == '*':
+			segments[i] = string(curlyStart) + s[1:] + catchAllSuffix + string(curlyEnd)
+		default:
+			segments[i] = escapeKey(s)
+		}
+	}
+
+	return strings.Join(segments, string(slash))
+}
+
+// foldPattern lowers the ASCII letters of the static parts of the pattern.
+func foldPattern(pattern string) string {
+	var (
+		b           = []byte(pattern)
+		insideParam = false
+	)
+
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == escapeChar:
+			// The escaped char is never a letter.
+			i++
+		case b[i] == curlyStart:
+			insideParam = true
+		case b[i] == curlyEnd:
+			insideParam = false
+		case !insideParam:
+			b[i] = lowerASCII(b[i])
+		}
+	}
+
+	return string(b)
+}
+
+// foldKey lowers the ASCII letters of the searched key, if the
+// tree is case-insensitive. Since only ASCII bytes are changed,
+// a valid UTF-8 key remains valid.
+func (t *Tree[T]) foldKey(key string) string {
+	if !t.caseInsensitive {
+		return key
+	}
+
+	for i := 0; i < len(key); i++ {
+		if lowerASCII(key[i]) != key[i] {
+			return asciiLower(key)
+		}
+	}
+
+	return key
+}
+
+func asciiLower(s string) string {
+	b := []byte(s)
+
+	for i := range b {
+		b[i] = lowerASCII(b[i])
+	}
+
+	return string(b)
+}
+
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
+
+// findTrailingSlash applies the trailing slash policy of the tree,
+// after the given key had no match.
+func (t *Tree[T]) findTrailingSlash(key string, order ResolutionOrder) *FoundNode[T] {
+	if t.trailingSlash == TrailingSlashStrict || len(key) < 2 || key[len(key)-1] != slash {
+		return nil
+	}
+
+	trimmed := key[:len(key)-1]
+
+	n, params := t.cachedLookup(trimmed, order)
+	if n == nil {
+		return nil
+	}
+
+	fn := t.newFoundNode(n, params)
+
+	if t.trailingSlash == TrailingSlashRedirect {
+		// The metadata is shared by every lookup of the
+		// route, so the redirect is set on a copy of it.
+		meta := *fn.meta
+		meta.redirect = &redirect{
+			target: escapeKey(trimmed),
+			code:   http.StatusMovedPermanently,
+		}
+		fn.meta = &meta
+	}
+
+	return fn
+}
@@ -0,0 +1,482 @@
+package rtree
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestNewChecked(t *testing.T) {
+	type testCase struct {
+		name        string
+		opts        []OptionFunc[*Route]
+		expectedErr error
+	}
+
+	tt := []testCase{
+		{
+			name:        "no options",
+			opts:        nil,
+			expectedErr: nil,
+		},
+		{
+			name: "independent options",
+			opts: []OptionFunc[*Route]{
+				WithCaseInsensitive[*Route](),
+				WithTrailingSlash[*Route](TrailingSlashRedirect),
+				WithReadLocking[*Route](),
+				WithLookupCache[*Route](16),
+				WithMetrics[*Route](&Metrics{}),
+				WithPatternSyntax[*Route](SyntaxColon),
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "same option with the same value",
+			opts: []OptionFunc[*Route]{
+				WithTrailingSlash[*Route](TrailingSlashIgnore),
+				WithTrailingSlash[*Route](TrailingSlashIgnore),
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "ignored and redirected trailing slash",
+			opts: []OptionFunc[*Route]{
+				WithTrailingSlash[*Route](TrailingSlashIgnore),
+				WithTrailingSlash[*Route](TrailingSlashRedirect),
+			},
+			expectedErr: errConflictingOptions,
+		},
+		{
+			name: "two syntaxes",
+			opts: []OptionFunc[*Route]{
+				WithPatternSyntax[*Route](SyntaxColon),
+				WithPatternSyntax[*Route](SyntaxCurly),
+			},
+			expectedErr: errConflictingOptions,
+		},
+		{
+			name: "two resolution orders",
+			opts: []OptionFunc[*Route]{
+				WithResolutionOrder[*Route](StaticFirst),
+				WithResolutionOrder[*Route](CatchAllFirst),
+			},
+			expectedErr: errConflictingOptions,
+		},
+		{
+			name: "two default routes",
+			opts: []OptionFunc[*Route]{
+				WithDefaultRoute[*Route]("/a"),
+				WithDefaultRoute[*Route]("/b"),
+			},
+			expectedErr: errConflictingOptions,
+		},
+		{
+			name:        "unknown trailing slash policy",
+			opts:        []OptionFunc[*Route]{WithTrailingSlash[*Route](TrailingSlashPolicy(9))},
+			expectedErr: errInvalidOption,
+		},
+		{
+			name:        "zero cache size",
+			opts:        []OptionFunc[*Route]{WithLookupCache[*Route](0)},
+			expectedErr: errInvalidOption,
+		},
+		{
+			name:        "negative prefix cache size",
+			opts:        []OptionFunc[*Route]{WithPrefixCache[*Route](-1)},
+			expectedErr: errInvalidOption,
+		},
+		{
+			name:        "negative backtrack budget",
+			opts:        []OptionFunc[*Route]{WithBacktrackBudget[*Route](-1)},
+			expectedErr: errInvalidOption,
+		},
+		{
+			name:        "nil metrics",
+			opts:        []OptionFunc[*Route]{WithMetrics[*Route](nil)},
+			expectedErr: errInvalidOption,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := NewChecked(tc.opts...)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if (tree == nil) != (tc.expectedErr != nil) {
+				t.Fatalf("expected tree only without error; got: %v\n", tree)
+			}
+		})
+	}
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	type testCase struct {
+		name            string
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	tree := New(WithCaseInsensitive[*Route]())
+
+	for _, key := range []string{"/API/Users/{userID}", "/api/Files/\\{raw\\}"} {
+		if err := tree.Insert(key, &Route{name: key}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := tree.Insert("/api/USERS/{userID}", &Route{}); !errors.Is(err, errKeyIsAlreadyStored) {
+		t.Fatalf("expected error: %v; got: %v\n", errKeyIsAlreadyStored, err)
+	}
+
+	tt := []testCase{
+		{
+			name:            "lower case key",
+			key:             "/api/users/AbC",
+			expectedPattern: "/api/users/{userID}",
+			expectedParams:  matchedParams{"userID": "AbC"},
+		},
+		{
+			name:            "mixed case key",
+			key:             "/aPi/USERS/AbC",
+			expectedPattern: "/api/users/{userID}",
+			expectedParams:  matchedParams{"userID": "AbC"},
+		},
+		{
+			name:            "escaped brackets",
+			key:             "/API/FILES/{RAW}",
+			expectedPattern: "/api/files/\\{raw\\}",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "no match",
+			key:             "/api/groups/1",
+			expectedPattern: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.Find(tc.key)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+			}
+		})
+	}
+
+	if err := tree.Delete("/Api/USERS/{userID}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.Find("/api/users/1"); fn != nil {
+		t.Fatalf("expected no match after delete; got: %s\n", fn.GetPattern())
+	}
+}
+
+func TestTrailingSlash(t *testing.T) {
+	type testCase struct {
+		name             string
+		policy           TrailingSlashPolicy
+		key              string
+		expectedPattern  string
+		expectedRedirect string
+	}
+
+	tt := []testCase{
+		{
+			name:            "strict, without slash",
+			policy:          TrailingSlashStrict,
+			key:             "/api/users/1",
+			expectedPattern: "/api/users/{id}",
+		},
+		{
+			name:            "strict, with slash",
+			policy:          TrailingSlashStrict,
+			key:             "/api/users/1/",
+			expectedPattern: "",
+		},
+		{
+			name:            "ignore, with slash",
+			policy:          TrailingSlashIgnore,
+			key:             "/api/users/1/",
+			expectedPattern: "/api/users/{id}",
+		},
+		{
+			name:            "ignore, with two slashes",
+			policy:          TrailingSlashIgnore,
+			key:             "/api/users/1//",
+			expectedPattern: "",
+		},
+		{
+			name:            "ignore, root",
+			policy:          TrailingSlashIgnore,
+			key:             "/",
+			expectedPattern: "",
+		},
+		{
+			name:             "redirect, with slash",
+			policy:           TrailingSlashRedirect,
+			key:              "/api/users/1/",
+			expectedPattern:  "/api/users/{id}",
+			expectedRedirect: "/api/users/1",
+		},
+		{
+			name:             "redirect, with literal bracket",
+			policy:           TrailingSlashRedirect,
+			key:              "/api/users/{1}/",
+			expectedPattern:  "/api/users/{id}",
+			expectedRedirect: "/api/users/{1}",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(WithTrailingSlash[*Route](tc.policy))
+
+			if err := tree.Insert("/api/users/{id}", &Route{}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			fn := tree.Find(tc.key)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+
+			target, code := fn.Redirect()
+
+			if target != tc.expectedRedirect {
+				t.Errorf("expected redirect: %q; got: %q\n", tc.expectedRedirect, target)
+			}
+
+			if tc.expectedRedirect != "" && code != http.StatusMovedPermanently {
+				t.Errorf("expected code: %d; got: %d\n", http.StatusMovedPermanently, code)
+			}
+		})
+	}
+
+	t.Run("redirect does not alter the route", func(t *testing.T) {
+		tree := New(WithTrailingSlash[*Route](TrailingSlashRedirect))
+
+		if err := tree.Insert("/api", &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		if fn := tree.Find("/api/"); fn == nil || !fn.IsRedirect() {
+			t.Fatalf("expected redirect; got: %v\n", fn)
+		}
+
+		if fn := tree.Find("/api"); fn == nil || fn.IsRedirect() {
+			t.Fatalf("expected plain match; got: %v\n", fn)
+		}
+	})
+}
+
+func TestPatternSyntax(t *testing.T) {
+	type testCase struct {
+		name            string
+		pattern         string
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	tt := []testCase{
+		{
+			name:            "named param",
+			pattern:         "/users/:id",
+			key:             "/users/5",
+			expectedPattern: "/users/{id}",
+			expectedParams:  matchedParams{"id": "5"},
+		},
+		{
+			name:            "catch-all param",
+			pattern:         "/files/:dir/*path",
+			key:             "/files/docs/a/b.txt",
+			expectedPattern: "/files/{dir}/{path...}",
+			expectedParams:  matchedParams{"dir": "docs", "path": "a/b.txt"},
+		},
+		{
+			name:            "literal brackets",
+			pattern:         "/raw/{id}",
+			key:             "/raw/{id}",
+			expectedPattern: "/raw/\\{id\\}",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "colon inside segment is literal",
+			pattern:         "/a:b/:c",
+			key:             "/a:b/x",
+			expectedPattern: "/a:b/{c}",
+			expectedParams:  matchedParams{"c": "x"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(WithPatternSyntax[*Route](SyntaxColon))
+
+			if err := tree.Insert(tc.pattern, &Route{}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			fn := tree.Find(tc.key)
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+			}
+
+			if err := tree.Delete(tc.pattern); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		})
+	}
+}
+
+func TestLookupCache(t *testing.T) {
+	var (
+		m    = &Metrics{}
+		tree = New(WithLookupCache[*Route](2), WithMetrics[*Route](m))
+	)
+
+	if err := tree.Insert("/users/{id}", &Route{name: "users"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		fn := tree.Find("/users/1")
+		if fn == nil {
+			t.Fatalf("expected match; got <nil>\n")
+		}
+
+		// The cached params must not be shared with the callers.
+		fn.GetParams()["id"] = "changed"
+	}
+
+	if fn := tree.Find("/users/1"); fn.GetParams()["id"] != "1" {
+		t.Fatalf("expected param: 1; got: %s\n", fn.GetParams()["id"])
+	}
+
+	if m.CacheHits() != 3 {
+		t.Fatalf("expected cache hits: 3; got: %d\n", m.CacheHits())
+	}
+
+	// A cached miss is invalidated by the insertion.
+	if fn := tree.Find("/groups/1"); fn != nil {
+		t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+	}
+
+	if err := tree.Insert("/groups/{id}", &Route{name: "groups"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.Find("/groups/1"); fn == nil || fn.value.name != "groups" {
+		t.Fatalf("expected the groups route; got: %v\n", fn)
+	}
+
+	// The replaced value is never served from the cache.
+	if err := tree.Upsert("/users/{id}", &Route{name: "members"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.Find("/users/1"); fn == nil || fn.value.name != "members" {
+		t.Fatalf("expected the replaced value; got: %v\n", fn)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	var (
+		m    = &Metrics{}
+		tree = New(WithMetrics[*Route](m))
+	)
+
+	if err := tree.Insert("/users/{id}", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tree.Find("/users/1")
+	tree.Find("/users")
+	tree.FindLongestMatch("/users/1/posts")
+
+	if m.Lookups() != 3 {
+		t.Errorf("expected lookups: 3; got: %d\n", m.Lookups())
+	}
+
+	// FindLongestMatch does not match the params.
+	if m.Misses() != 2 {
+		t.Errorf("expected misses: 2; got: %d\n", m.Misses())
+	}
+}
+
+func TestReadLocking(t *testing.T) {
+	tree := New(WithReadLocking[*Route]())
+
+	if err := tree.Insert("/users/{id}", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if fn := tree.Find("/users/1"); fn == nil {
+					t.Errorf("expected match; got <nil>\n")
+					return
+				}
+			}
+		}()
+	}
+
+	for j := 0; j < 100; j++ {
+		if err := tree.Upsert("/users/{id}", &Route{}); err != nil {
+			t.Errorf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	wg.Wait()
+}
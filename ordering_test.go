@@ -0,0 +1,128 @@
+package rtree
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// permutations returns all the permutations of the given routes.
+func permutations(routes []string) [][]string {
+	if len(routes) <= 1 {
+		return [][]string{append([]string{}, routes...)}
+	}
+
+	res := make([][]string, 0)
+
+	for i := range routes {
+		rest := make([]string, 0, len(routes)-1)
+		rest = append(rest, routes[:i]...)
+		rest = append(rest, routes[i+1:]...)
+
+		for _, p := range permutations(rest) {
+			res = append(res, append([]string{routes[i]}, p...))
+		}
+	}
+
+	return res
+}
+
+// dumpTree returns the keys of the nodes in pre-order, indented by depth.
+func dumpTree[T storeValue](n *Node[T], depth int, sb *strings.Builder) {
+	if n == nil {
+		return
+	}
+
+	fmt.Fprintf(sb, "%s%s\n", strings.Repeat(" ", depth), n.key)
+
+	for _, ch := range n.children {
+		dumpTree(ch, depth+1, sb)
+	}
+}
+
+func TestWildcardSiblingOrdering(t *testing.T) {
+	routes := []string{
+		"/a/{x}/c",
+		"/a/{u}/c",
+		"/a/{y}/d",
+		"/a/{z}/{w}",
+		"/a/{v}/c/e",
+		"/a/b",
+	}
+
+	type testCase struct {
+		key             string
+		expectedPattern string
+	}
+
+	tt := []testCase{
+		// Both /a/{u}/c and /a/{x}/c are just as specific,
+		// so the one with the smaller key wins.
+		{key: "/a/1/c", expectedPattern: "/a/{u}/c"},
+		{key: "/a/1/d", expectedPattern: "/a/{y}/d"},
+		{key: "/a/1/q", expectedPattern: "/a/{z}/{w}"},
+		{key: "/a/1/c/e", expectedPattern: "/a/{v}/c/e"},
+		{key: "/a/b", expectedPattern: "/a/b"},
+		{key: "/a/1/c/f", expectedPattern: ""},
+	}
+
+	var expectedDump string
+
+	for _, perm := range permutations(routes) {
+		tree := New[*Route]()
+
+		for _, r := range perm {
+			if err := tree.Insert(r, &Route{name: r}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		var sb strings.Builder
+
+		dumpTree(tree.root, 0, &sb)
+
+		if expectedDump == "" {
+			expectedDump = sb.String()
+		}
+
+		if got := sb.String(); got != expectedDump {
+			t.Fatalf("expected the same tree for %v; expected:\n%s\ngot:\n%s", perm, expectedDump, got)
+		}
+
+		for _, tc := range tt {
+			fn := tree.Find(tc.key)
+
+			got := ""
+			if fn != nil {
+				got = fn.GetPattern()
+			}
+
+			if got != tc.expectedPattern {
+				t.Fatalf("expected pattern of %s for %v: %q; got: %q\n", tc.key, perm, tc.expectedPattern, got)
+			}
+		}
+	}
+}
+
+func TestWildcardSiblingOrderingAfterDelete(t *testing.T) {
+	tree := New[*Route]()
+
+	for _, r := range []string{"/a/{x}/c", "/a/{y}/cc", "/a/{y}/{z}"} {
+		if err := tree.Insert(r, &Route{name: r}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// The merge makes the sibling of /a/{x}/c more specific.
+	if err := tree.Delete("/a/{y}/{z}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := tree.Find("/a/1/c").GetPattern(); got != "/a/{x}/c" {
+		t.Errorf("expected pattern: %s; got: %s\n", "/a/{x}/c", got)
+	}
+
+	if got := tree.root.children[0].key; got != "y}/cc" {
+		t.Errorf("expected first child: %s; got: %s\n", "y}/cc", got)
+	}
+}
@@ -0,0 +1,143 @@
+// Package otelrtree wraps the lookups of an rtree.Tree in OpenTelemetry
+// spans, so the routing decisions of a gateway are visible in its traces.
+package otelrtree
+
+import (
+	"context"
+
+	"github.com/balazskvancz/rtree"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer of the adapter.
+const instrumentationName = "github.com/balazskvancz/rtree/otelrtree"
+
+// The names of the spans.
+const (
+	spanFind             = "rtree.Find"
+	spanFindLongestMatch = "rtree.FindLongestMatch"
+)
+
+// The attributes of the spans.
+const (
+	// AttrMatched reports whether the lookup found a route.
+	AttrMatched = attribute.Key("rtree.matched")
+	// AttrPattern is the pattern of the matched route.
+	AttrPattern = attribute.Key("rtree.route.pattern")
+	// AttrRouteID is the stable ID of the matched route.
+	AttrRouteID = attribute.Key("rtree.route.id")
+	// AttrParamsCount is the number of the matched params.
+	AttrParamsCount = attribute.Key("rtree.params.count")
+	// AttrBacktracks is the number of the dead ends of the search.
+	AttrBacktracks = attribute.Key("rtree.backtracks")
+)
+
+// Option configures the adapter.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	backtracks bool
+}
+
+// WithTracerProvider sets the provider of the tracer.
+// By default it is the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+// WithBacktracks sets whether the spans of Find get the number of the
+// backtracks. Counting them takes a second, traced search of the key, so
+// it is only done for the recording spans. By default it is enabled.
+func WithBacktracks(enabled bool) Option {
+	return func(c *config) {
+		c.backtracks = enabled
+	}
+}
+
+// Tree is a traced view of an rtree.Tree. It is safe for concurrent use,
+// as far as the wrapped tree is.
+type Tree[T any] struct {
+	tree       *rtree.Tree[T]
+	tracer     trace.Tracer
+	backtracks bool
+}
+
+// Wrap returns the traced view of the given tree.
+func Wrap[T any](tree *rtree.Tree[T], opts ...Option) *Tree[T] {
+	c := &config{
+		backtracks: true,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	if c.provider == nil {
+		c.provider = otel.GetTracerProvider()
+	}
+
+	return &Tree[T]{
+		tree:       tree,
+		tracer:     c.provider.Tracer(instrumentationName),
+		backtracks: c.backtracks,
+	}
+}
+
+// Unwrap returns the wrapped tree.
+func (t *Tree[T]) Unwrap() *rtree.Tree[T] {
+	return t.tree
+}
+
+// Find is the same as the Find of the tree, but the lookup is
+// recorded in a span, which is the child of the one in ctx.
+func (t *Tree[T]) Find(ctx context.Context, key string) *rtree.FoundNode[T] {
+	_, span := t.tracer.Start(ctx, spanFind, trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	fn := t.tree.Find(key)
+
+	setMatch(span, fn)
+
+	if t.backtracks && span.IsRecording() {
+		span.SetAttributes(AttrBacktracks.Int(t.tree.Explain(key).Backtracks()))
+	}
+
+	return fn
+}
+
+// FindLongestMatch is the same as the FindLongestMatch of the tree, but
+// the lookup is recorded in a span, which is the child of the one in ctx.
+func (t *Tree[T]) FindLongestMatch(ctx context.Context, key string) *rtree.FoundNode[T] {
+	_, span := t.tracer.Start(ctx, spanFindLongestMatch, trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	fn := t.tree.FindLongestMatch(key)
+
+	setMatch(span, fn)
+
+	return fn
+}
+
+// setMatch sets the attributes of the result of the lookup.
+func setMatch[T any](span trace.Span, fn *rtree.FoundNode[T]) {
+	if !span.IsRecording() {
+		return
+	}
+
+	if fn == nil {
+		span.SetAttributes(AttrMatched.Bool(false))
+		return
+	}
+
+	span.SetAttributes(
+		AttrMatched.Bool(true),
+		AttrPattern.String(fn.GetPattern()),
+		AttrRouteID.Int64(int64(fn.RouteID())),
+		AttrParamsCount.Int(len(fn.GetParams())),
+	)
+}
@@ -0,0 +1,166 @@
+package otelrtree
+
+import (
+	"context"
+	"testing"
+
+	"github.com/balazskvancz/rtree"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTree(t *testing.T) *rtree.Tree[string] {
+	t.Helper()
+
+	tree := rtree.New[string]()
+
+	for _, p := range []string{"/api/{resource}/get", "/api/products/get", "/static"} {
+		if err := tree.Insert(p, p); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	return tree
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestTree(t *testing.T) {
+	type testCase struct {
+		name            string
+		find            func(tree *Tree[string]) *rtree.FoundNode[string]
+		expectedSpan    string
+		expectedMatched bool
+		expectedPattern string
+		expectedParams  int64
+		// expectedBacktracks is -1, if the span has no such attribute.
+		expectedBacktracks int64
+	}
+
+	tt := []testCase{
+		{
+			name: "static match",
+			find: func(tree *Tree[string]) *rtree.FoundNode[string] {
+				return tree.Find(context.Background(), "/api/products/get")
+			},
+			expectedSpan:       spanFind,
+			expectedMatched:    true,
+			expectedPattern:    "/api/products/get",
+			expectedBacktracks: 0,
+		},
+		{
+			name: "wildcard match after static miss",
+			find: func(tree *Tree[string]) *rtree.FoundNode[string] {
+				return tree.Find(context.Background(), "/api/users/get")
+			},
+			expectedSpan:       spanFind,
+			expectedMatched:    true,
+			expectedPattern:    "/api/{resource}/get",
+			expectedParams:     1,
+			expectedBacktracks: 1,
+		},
+		{
+			name: "miss",
+			find: func(tree *Tree[string]) *rtree.FoundNode[string] {
+				return tree.Find(context.Background(), "/api/products/list")
+			},
+			expectedSpan:       spanFind,
+			expectedMatched:    false,
+			expectedBacktracks: 3,
+		},
+		{
+			name: "longest match",
+			find: func(tree *Tree[string]) *rtree.FoundNode[string] {
+				return tree.FindLongestMatch(context.Background(), "/static/css/main.css")
+			},
+			expectedSpan:       spanFindLongestMatch,
+			expectedMatched:    true,
+			expectedPattern:    "/static",
+			expectedBacktracks: -1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				recorder = tracetest.NewSpanRecorder()
+				provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+				tree     = Wrap(newTree(t), WithTracerProvider(provider))
+			)
+
+			fn := tc.find(tree)
+
+			if (fn != nil) != tc.expectedMatched {
+				t.Fatalf("expected matched: %v; got: %v\n", tc.expectedMatched, fn)
+			}
+
+			spans := recorder.Ended()
+
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span; got: %d\n", len(spans))
+			}
+
+			if spans[0].Name() != tc.expectedSpan {
+				t.Errorf("expected span: %s; got: %s\n", tc.expectedSpan, spans[0].Name())
+			}
+
+			attrs := spanAttributes(spans[0])
+
+			if got := attrs[AttrMatched].AsBool(); got != tc.expectedMatched {
+				t.Errorf("expected matched attribute: %v; got: %v\n", tc.expectedMatched, got)
+			}
+
+			if got := attrs[AttrPattern].AsString(); got != tc.expectedPattern {
+				t.Errorf("expected pattern attribute: %s; got: %s\n", tc.expectedPattern, got)
+			}
+
+			if tc.expectedMatched {
+				if got := attrs[AttrRouteID].AsInt64(); got != int64(fn.RouteID()) {
+					t.Errorf("expected route id attribute: %d; got: %d\n", fn.RouteID(), got)
+				}
+
+				if got := attrs[AttrParamsCount].AsInt64(); got != tc.expectedParams {
+					t.Errorf("expected params count attribute: %d; got: %d\n", tc.expectedParams, got)
+				}
+			}
+
+			backtracks, exists := attrs[AttrBacktracks]
+
+			if tc.expectedBacktracks == -1 {
+				if exists {
+					t.Errorf("not expected backtracks attribute; got: %d\n", backtracks.AsInt64())
+				}
+				return
+			}
+
+			if got := backtracks.AsInt64(); !exists || got != tc.expectedBacktracks {
+				t.Errorf("expected backtracks attribute: %d; got: %d\n", tc.expectedBacktracks, got)
+			}
+		})
+	}
+}
+
+func TestTreeWithoutBacktracks(t *testing.T) {
+	var (
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		tree     = Wrap(newTree(t), WithTracerProvider(provider), WithBacktracks(false))
+	)
+
+	if fn := tree.Find(context.Background(), "/api/users/get"); fn == nil {
+		t.Fatal("expected match; got nil")
+	}
+
+	if _, exists := spanAttributes(recorder.Ended()[0])[AttrBacktracks]; exists {
+		t.Error("not expected backtracks attribute")
+	}
+}
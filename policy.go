@@ -0,0 +1,83 @@
+package rtree
+
+import "unicode/utf8"
+
+// ParamPolicy restricts the values of the path params. A value violating
+// the policy fails the match – so the search goes on among the other
+// branches – instead of flowing into the handlers.
+type ParamPolicy struct {
+	// MaxLen is the maximum length of the value in bytes, 0 means no limit.
+	MaxLen int
+	// Allowed reports whether a rune could be part of the value, nil
+	// means all the runes are allowed. If it is set, invalid UTF-8
+	// sequences are never allowed.
+	Allowed func(r rune) bool
+}
+
+// WithParamPolicy sets the policy of all the path params of the tree.
+func WithParamPolicy[T storeValue](maxLen int, allowed func(r rune) bool) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.paramPolicy = &ParamPolicy{
+			MaxLen:  maxLen,
+			Allowed: allowed,
+		}
+	}
+}
+
+// WithParamPolicyFor sets the policy of the path params with the given
+// name, overriding the policy of the tree for them.
+func WithParamPolicyFor[T storeValue](name string, maxLen int, allowed func(r rune) bool) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if t.paramPolicies == nil {
+			t.paramPolicies = make(map[string]ParamPolicy)
+		}
+
+		t.paramPolicies[name] = ParamPolicy{
+			MaxLen:  maxLen,
+			Allowed: allowed,
+		}
+	}
+}
+
+// WithNonEmptyParams makes the params reject the empty segments, so eg.
+// /users//posts does not match /users/{id}/posts, and the search goes on
+// among the other branches. By default an empty segment is captured as
+// an empty string. The catch-all params could still capture nothing,
+// where their syntax allows it – eg. the zero levels of the topics.
+func WithNonEmptyParams[T storeValue]() OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.nonEmptyParams = true
+	}
+}
+
+// checkParamPolicy reports whether the value of the
+// given param is acceptable by its policy.
+func (t *Tree[T]) checkParamPolicy(name, value string) bool {
+	if policy, exists := t.paramPolicies[name]; exists {
+		return policy.check(value)
+	}
+
+	if t.paramPolicy != nil {
+		return t.paramPolicy.check(value)
+	}
+
+	return true
+}
+
+func (p ParamPolicy) check(value string) bool {
+	if p.MaxLen > 0 && len(value) > p.MaxLen {
+		return false
+	}
+
+	if p.Allowed == nil {
+		return true
+	}
+
+	for _, r := range value {
+		if r == utf8.RuneError || !p.Allowed(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,165 @@
+package rtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestParamPolicy(t *testing.T) {
+	type testCase struct {
+		name         string
+		opts         []OptionFunc[*Route]
+		key          string
+		expectedName string
+	}
+
+	printable := func(r rune) bool { return !unicode.IsControl(r) }
+	digits := func(r rune) bool { return r >= '0' && r <= '9' }
+
+	tt := []testCase{
+		{
+			name:         "no policy, everything matches",
+			opts:         nil,
+			key:          "/users/" + strings.Repeat("x", 1024),
+			expectedName: "user",
+		},
+		{
+			name:         "too long value fails the match",
+			opts:         []OptionFunc[*Route]{WithParamPolicy[*Route](64, nil)},
+			key:          "/users/" + strings.Repeat("x", 1024),
+			expectedName: "",
+		},
+		{
+			name:         "control character fails the match",
+			opts:         []OptionFunc[*Route]{WithParamPolicy[*Route](64, printable)},
+			key:          "/users/foo\x00bar",
+			expectedName: "",
+		},
+		{
+			name:         "acceptable value matches",
+			opts:         []OptionFunc[*Route]{WithParamPolicy[*Route](64, printable)},
+			key:          "/users/foo",
+			expectedName: "user",
+		},
+		{
+			name:         "per param policy overrides the tree policy",
+			opts:         []OptionFunc[*Route]{WithParamPolicy[*Route](2, nil), WithParamPolicyFor[*Route]("id", 0, digits)},
+			key:          "/users/12345",
+			expectedName: "user",
+		},
+		{
+			name:         "per param policy does not apply to other params",
+			opts:         []OptionFunc[*Route]{WithParamPolicyFor[*Route]("id", 0, digits)},
+			key:          "/users/me/profile",
+			expectedName: "profile",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			routes := map[string]string{
+				"/users/{id}":           "user",
+				"/users/{name}/profile": "profile",
+			}
+
+			for _, r := range []string{"/users/{id}", "/users/{name}/profile"} {
+				if err := tree.Insert(r, &Route{name: routes[r]}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			fn := tree.Find(tc.key)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Error("expected not to find, but got route")
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+		})
+	}
+}
+
+func TestNonEmptyParams(t *testing.T) {
+	type testCase struct {
+		name            string
+		opts            []OptionFunc[*Route]
+		key             string
+		expectedPattern string
+		expectedParams  map[string]string
+	}
+
+	tt := []testCase{
+		{
+			name:            "empty segment is captured by default",
+			key:             "/users//posts",
+			expectedPattern: "/users/{id}/posts",
+			expectedParams:  map[string]string{"id": ""},
+		},
+		{
+			name:            "empty segment falls through to the other branches",
+			opts:            []OptionFunc[*Route]{WithNonEmptyParams[*Route]()},
+			key:             "/users//posts",
+			expectedPattern: "/users/{rest...}",
+			expectedParams:  map[string]string{"rest": "/posts"},
+		},
+		{
+			name:            "non-empty segment still matches",
+			opts:            []OptionFunc[*Route]{WithNonEmptyParams[*Route]()},
+			key:             "/users/1/posts",
+			expectedPattern: "/users/{id}/posts",
+			expectedParams:  map[string]string{"id": "1"},
+		},
+		{
+			name: "empty segment without other branches",
+			opts: []OptionFunc[*Route]{WithNonEmptyParams[*Route]()},
+			key:  "/teams//members",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			for _, r := range []string{"/users/{id}/posts", "/users/{rest...}", "/teams/{id}/members"} {
+				if err := tree.Insert(r, getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			fn := tree.Find(tc.key)
+			h := tree.Resolve(tc.key)
+
+			if tc.expectedPattern == "" {
+				if fn != nil || h != nil {
+					t.Fatalf("expected no match of %s\n", tc.key)
+				}
+				return
+			}
+
+			if fn == nil || h == nil {
+				t.Fatalf("expected match: %s; got none\n", tc.expectedPattern)
+			}
+
+			if fn.GetPattern() != tc.expectedPattern || h.Pattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s and %s\n", tc.expectedPattern, fn.GetPattern(), h.Pattern())
+			}
+
+			if !reflect.DeepEqual(map[string]string(fn.params), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.params)
+			}
+		})
+	}
+}
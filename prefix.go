@@ -0,0 +1,188 @@
+package rtree
+
+import "strings"
+
+// WithSegmentBoundaryPrefixes makes FindLongestMatch only accept prefixes
+// ending at a segment boundary, so a stored /api/prod matches
+// /api/prod/list-all but does not match /api/production-x.
+func WithSegmentBoundaryPrefixes[T storeValue]() OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.boundaryPrefixes = true
+	}
+}
+
+// prefixCacheSegments is the number of the leading segments,
+// that key the entries of the prefix cache.
+const prefixCacheSegments = 2
+
+// WithPrefixCache caches the results of FindLongestMatch by the first
+// two segments of the keys, eg. /api/users, so the lookups of the keys
+// under the same service prefix are map hits. A prefix is only served
+// from the cache, if there are no routes below it, otherwise its keys
+// are still searched in the tree. Every mutation invalidates the whole
+// cache, and once it is full, it is emptied. The size must be positive.
+func WithPrefixCache[T storeValue](size int) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		if size <= 0 {
+			t.invalidOption("prefix cache size", size)
+			return
+		}
+
+		t.setOption("prefix cache size", size)
+		t.prefixCache = &lookupCache[T]{
+			size:    size,
+			entries: make(map[string]cacheEntry[T], size),
+		}
+	}
+}
+
+// cachedLongestMatch returns the longest match of the – already escaped
+// and folded – key, using the prefix cache, if the tree has one.
+func (t *Tree[T]) cachedLongestMatch(key string) *Node[T] {
+	if t.prefixCache == nil || t.windowed.Load() {
+		return findLongestMatchRec(t.root, key, t.boundaryPrefixes)
+	}
+
+	var (
+		prefix     = leadingSegments(key, prefixCacheSegments)
+		generation = t.Generation()
+	)
+
+	if e, ok := t.prefixCache.get(prefix, generation); ok {
+		if e.deeper {
+			return findLongestMatchRec(t.root, key, t.boundaryPrefixes)
+		}
+
+		t.metrics.cacheHit()
+		return e.node
+	}
+
+	// Without routes below the prefix, the rest of the
+	// key could not change the match of the prefix.
+	if hasLeafBeyond(t.root, prefix) {
+		t.prefixCache.put(prefix, cacheEntry[T]{generation: generation, deeper: true})
+
+		return findLongestMatchRec(t.root, key, t.boundaryPrefixes)
+	}
+
+	n := findLongestMatchRec(t.root, prefix, t.boundaryPrefixes)
+
+	t.prefixCache.put(prefix, cacheEntry[T]{generation: generation, node: n})
+
+	return n
+}
+
+// leadingSegments returns the prefix of the key, that
+// holds its first count segments, eg. /api/users of
+// /api/users/1 – or the whole key, if it is shorter.
+func leadingSegments(key string, count int) string {
+	i := 0
+	if strings.HasPrefix(key, string(slash)) {
+		i = 1
+	}
+
+	for ; count > 0; count-- {
+		idx := strings.IndexByte(key[i:], slash)
+		if idx == -1 {
+			return key
+		}
+
+		i += idx + 1
+	}
+
+	return key[:i-1]
+}
+
+// hasLeafBeyond reports whether there is a leaf under
+// the given node, whose key is longer than the prefix.
+func hasLeafBeyond[T storeValue](n *Node[T], prefix string) bool {
+	if n == nil {
+		return false
+	}
+
+	lcp := longestCommonPrefix(n.key, prefix)
+
+	// The prefix ends inside of the node key.
+	if lcp == len(prefix) && lcp < len(n.key) {
+		return hasLeaf(n)
+	}
+
+	if lcp < len(n.key) {
+		return false
+	}
+
+	for _, ch := range n.children {
+		if lcp == len(prefix) && hasLeaf(ch) {
+			return true
+		}
+
+		if lcp < len(prefix) && hasLeafBeyond(ch, prefix[lcp:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasLeaf reports whether the node or any of its descendants is a leaf.
+func hasLeaf[T storeValue](n *Node[T]) bool {
+	if n.IsLeaf() {
+		return true
+	}
+
+	for _, ch := range n.children {
+		if hasLeaf(ch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newLongestMatch returns the found node of the longest match of the key,
+// with the matched prefix of the key, and the rest of it.
+func (t *Tree[T]) newLongestMatch(n *Node[T], key string) *FoundNode[T] {
+	fn := t.newFoundNode(n, make(matchedParams))
+
+	// The stored pattern of the leaf is the matched prefix of the
+	// searched key, so its length is mapped back to the original key.
+	var (
+		canonical = t.canonicalKey(key)
+		escaped   = 0
+		i         = 0
+	)
+
+	for ; i < len(canonical) && escaped < len(n.value.pattern); i++ {
+		// A decoded percent-encoding is a single byte of the searched key.
+		if !t.objectKeys && isDecodedPercent(canonical, i) {
+			i += 2
+		}
+
+		escaped++
+
+		if isEscapable(canonical[i]) {
+			escaped++
+		}
+	}
+
+	fn.base = key[:i]
+	fn.rest = key[i:]
+
+	return fn
+}
+
+// BasePath returns the prefix of the key, that the route was matched
+// by – eg. /api/products of /api/products/list-all –, if the route was
+// found as the longest match of the key, by FindLongestMatch or by
+// FindOrLongest. A proxy could strip it from the forwarded path. The
+// case and the delimiters of the key are kept.
+func (fn *FoundNode[T]) BasePath() string {
+	return fn.base
+}
+
+// Rest returns the rest of the key after the BasePath, eg.
+// /list-all of /api/products/list-all. It is empty, if the
+// whole key matched, or if the route was not a longest match.
+func (fn *FoundNode[T]) Rest() string {
+	return fn.rest
+}
@@ -0,0 +1,254 @@
+package rtree
+
+import "testing"
+
+func TestFindLongestMatchSegmentBoundary(t *testing.T) {
+	type testCase struct {
+		name         string
+		opts         []OptionFunc[*Route]
+		key          string
+		expectedName string
+	}
+
+	boundary := []OptionFunc[*Route]{WithSegmentBoundaryPrefixes[*Route]()}
+
+	tt := []testCase{
+		{
+			name:         "without option, prefix inside a segment matches",
+			opts:         nil,
+			key:          "/api/production-x",
+			expectedName: "prod",
+		},
+		{
+			name:         "with option, falls back to shorter prefix on boundary",
+			opts:         boundary,
+			key:          "/api/production-x",
+			expectedName: "api",
+		},
+		{
+			name:         "with option, exact key matches",
+			opts:         boundary,
+			key:          "/api/prod",
+			expectedName: "prod",
+		},
+		{
+			name:         "with option, prefix on boundary matches",
+			opts:         boundary,
+			key:          "/api/prod/list-all",
+			expectedName: "prod",
+		},
+		{
+			name:         "with option, no prefix on boundary",
+			opts:         boundary,
+			key:          "/apix/prod",
+			expectedName: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			if err := tree.Insert("/api/prod", &Route{name: "prod"}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if err := tree.Insert("/api", &Route{name: "api"}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			fn := tree.FindLongestMatch(tc.key)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Errorf("expected not to find, but got: %s", fn.GetValue().name)
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetValue().name; got != tc.expectedName {
+				t.Errorf("expected value: %s; got: %s\n", tc.expectedName, got)
+			}
+		})
+	}
+}
+
+func TestPrefixCache(t *testing.T) {
+	type testCase struct {
+		name         string
+		opts         []OptionFunc[*Route]
+		keys         []string
+		expectedHits uint64
+	}
+
+	keys := []string{
+		"/api/prod", "/api/prod/list-all", "/api/production-x", "/api/users/1",
+		"/api/users/admin", "/api/users/admin/1", "/apix/prod", "/", "/api", "/files/a/b/c",
+	}
+
+	tt := []testCase{
+		{
+			name:         "repeated keys",
+			keys:         keys,
+			expectedHits: 6,
+		},
+		{
+			name:         "repeated keys with segment boundary",
+			opts:         []OptionFunc[*Route]{WithSegmentBoundaryPrefixes[*Route]()},
+			keys:         keys,
+			expectedHits: 6,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				m      = &Metrics{}
+				cached = New(append([]OptionFunc[*Route]{WithPrefixCache[*Route](16), WithMetrics[*Route](m)}, tc.opts...)...)
+				plain  = New(tc.opts...)
+			)
+
+			for _, key := range []string{"/api/prod", "/api", "/api/users/admin", "/files"} {
+				for _, tree := range []*Tree[*Route]{cached, plain} {
+					if err := tree.Insert(key, &Route{name: key}); err != nil {
+						t.Fatalf("not expected error, but got: %v\n", err)
+					}
+				}
+			}
+
+			// The second round is served from the cache, but
+			// it must match the same routes as the first one.
+			for i := 0; i < 2; i++ {
+				for _, key := range tc.keys {
+					var (
+						expected = plain.FindLongestMatch(key)
+						got      = cached.FindLongestMatch(key)
+					)
+
+					if (expected == nil) != (got == nil) || expected != nil && expected.GetPattern() != got.GetPattern() {
+						t.Fatalf("expected the match of %s: %v; got: %v\n", key, expected, got)
+					}
+				}
+			}
+
+			if m.CacheHits() != tc.expectedHits {
+				t.Fatalf("expected cache hits: %d; got: %d\n", tc.expectedHits, m.CacheHits())
+			}
+		})
+	}
+}
+
+func TestPrefixCacheInvalidation(t *testing.T) {
+	tree := New(WithPrefixCache[*Route](4))
+
+	if err := tree.Insert("/api", &Route{name: "api"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.FindLongestMatch("/api/users/1"); fn == nil || fn.GetValue().name != "api" {
+		t.Fatalf("expected the api route; got: %v\n", fn)
+	}
+
+	if err := tree.Insert("/api/users", &Route{name: "users"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.FindLongestMatch("/api/users/1"); fn == nil || fn.GetValue().name != "users" {
+		t.Fatalf("expected the users route; got: %v\n", fn)
+	}
+
+	if err := tree.Delete("/api/users"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.FindLongestMatch("/api/users/1"); fn == nil || fn.GetValue().name != "api" {
+		t.Fatalf("expected the api route; got: %v\n", fn)
+	}
+}
+
+func TestLongestMatchBasePath(t *testing.T) {
+	type testCase struct {
+		name         string
+		opts         []OptionFunc[*Route]
+		key          string
+		expectedBase string
+		expectedRest string
+	}
+
+	tt := []testCase{
+		{
+			name:         "base path of the service",
+			key:          "/api/products/list-all",
+			expectedBase: "/api/products",
+			expectedRest: "/list-all",
+		},
+		{
+			name:         "whole key",
+			key:          "/api/products",
+			expectedBase: "/api/products",
+			expectedRest: "",
+		},
+		{
+			name:         "case of the key is kept",
+			opts:         []OptionFunc[*Route]{WithCaseInsensitive[*Route]()},
+			key:          "/API/Products/List",
+			expectedBase: "/API/Products",
+			expectedRest: "/List",
+		},
+		{
+			name:         "escaped brackets",
+			key:          "/legacy/{v}/x",
+			expectedBase: "/legacy/{v}",
+			expectedRest: "/x",
+		},
+		{
+			name:         "prefix cache",
+			opts:         []OptionFunc[*Route]{WithPrefixCache[*Route](16)},
+			key:          "/api/products/list-all",
+			expectedBase: "/api/products",
+			expectedRest: "/list-all",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			for _, p := range []string{"/api/products", `/legacy/\{v\}`} {
+				if err := tree.Insert(p, getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			fn := tree.FindLongestMatch(tc.key)
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if fn.BasePath() != tc.expectedBase || fn.Rest() != tc.expectedRest {
+				t.Errorf("expected: %q + %q; got: %q + %q\n", tc.expectedBase, tc.expectedRest, fn.BasePath(), fn.Rest())
+			}
+		})
+	}
+
+	// The exact matches have no base path, while the fallback of
+	// FindOrLongest reports it the same way.
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/products", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := tree.Find("/api/products"); fn.BasePath() != "" || fn.Rest() != "" {
+		t.Errorf("expected no base path of an exact match; got: %q\n", fn.BasePath())
+	}
+
+	fn, kind := tree.FindOrLongest("/api/products/list-all")
+	if kind != MatchLongest || fn.BasePath() != "/api/products" || fn.Rest() != "/list-all" {
+		t.Errorf("expected the base path of FindOrLongest: %q; got: %v\n", "/api/products", fn)
+	}
+}
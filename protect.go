@@ -0,0 +1,31 @@
+package rtree
+
+import "fmt"
+
+var errRouteIsProtected = fmt.Errorf("[rtree %s]: route is protected", version)
+
+// InsertProtected stores a route, that could not be removed by Delete
+// or replaced by Upsert – eg. health or metrics routes, which must not
+// be clobbered by loading the configuration of a tenant. Only ForceDelete
+// and ForceUpsert could change it.
+func (t *Tree[T]) InsertProtected(key string, value T, opts ...RouteOption) error {
+	return t.insert(key, value, opts, func(nv *NodeValue[T]) {
+		nv.meta.protected = true
+	})
+}
+
+// IsProtected reports whether the route is protected.
+func (nv *NodeValue[T]) IsProtected() bool {
+	return nv.meta.protected
+}
+
+// ForceDelete is the same as Delete, but it removes protected routes as well.
+func (t *Tree[T]) ForceDelete(key string) error {
+	return t.delete(key, true)
+}
+
+// ForceUpsert is the same as Upsert, but it replaces the value of
+// protected routes as well. The replaced route remains protected.
+func (t *Tree[T]) ForceUpsert(key string, value T, opts ...RouteOption) error {
+	return t.upsert(key, value, opts, true)
+}
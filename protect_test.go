@@ -0,0 +1,98 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProtectedRoutes(t *testing.T) {
+	type testCase struct {
+		name   string
+		change func(tree *Tree[*Route]) error
+		err    error
+	}
+
+	tt := []testCase{
+		{
+			name:   "error on delete",
+			change: func(tree *Tree[*Route]) error { return tree.Delete("/health") },
+			err:    errRouteIsProtected,
+		},
+		{
+			name:   "error on upsert",
+			change: func(tree *Tree[*Route]) error { return tree.Upsert("/health", &Route{name: "tenant"}) },
+			err:    errRouteIsProtected,
+		},
+		{
+			name:   "error on cascading delete",
+			change: func(tree *Tree[*Route]) error { return tree.DeleteCascade("/health") },
+			err:    errRouteIsProtected,
+		},
+		{
+			name:   "error on insert",
+			change: func(tree *Tree[*Route]) error { return tree.Insert("/health", &Route{name: "tenant"}) },
+			err:    errKeyIsAlreadyStored,
+		},
+		{
+			name:   "no error on forced delete",
+			change: func(tree *Tree[*Route]) error { return tree.ForceDelete("/health") },
+			err:    nil,
+		},
+		{
+			name:   "no error on forced upsert",
+			change: func(tree *Tree[*Route]) error { return tree.ForceUpsert("/health", &Route{name: "tenant"}) },
+			err:    nil,
+		},
+		{
+			name:   "no error on changing unprotected routes",
+			change: func(tree *Tree[*Route]) error { return tree.Delete("/api/users") },
+			err:    nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			if err := tree.InsertProtected("/health", &Route{name: "platform"}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if err := tree.Insert("/api/users", getRoute()); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if err := tc.change(tree); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+
+			if tc.err == nil {
+				return
+			}
+
+			if got := tree.Find("/health").GetValue().name; got != "platform" {
+				t.Errorf("expected value: %s; got: %s\n", "platform", got)
+			}
+		})
+	}
+}
+
+func TestForceUpsertKeepsProtection(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.InsertProtected("/metrics", &Route{name: "v1"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.ForceUpsert("/metrics", &Route{name: "v2"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := tree.Find("/metrics").GetValue().name; got != "v2" {
+		t.Errorf("expected value: %s; got: %s\n", "v2", got)
+	}
+
+	if err := tree.Delete("/metrics"); !errors.Is(err, errRouteIsProtected) {
+		t.Errorf("expected error: %v; got: %v\n", errRouteIsProtected, err)
+	}
+}
@@ -0,0 +1,99 @@
+package rtree
+
+import (
+	"sync"
+	"time"
+)
+
+// now is the clock of the token buckets and of the active
+// windows of the routes, overridable in tests.
+var now = time.Now
+
+// RateLimit describes the allowed request rate of a route.
+// Rate is the number of requests allowed per second, while Burst
+// is the maximum number of requests allowed at once.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimit attaches a rate-limit descriptor to the route.
+func WithRateLimit(rate float64, burst int) RouteOption {
+	return func(rm *routeMeta) {
+		rm.rateLimit = &RateLimit{
+			Rate:  rate,
+			Burst: burst,
+		}
+		rm.bucket = &tokenBucket{
+			limit:  *rm.rateLimit,
+			tokens: float64(burst),
+			last:   now(),
+		}
+	}
+}
+
+// WithRateLimiting enables the token-bucket subsystem, so every
+// successful lookup consumes a token of the matched route, and
+// reports whether the request is allowed by its rate-limit.
+func WithRateLimiting[T storeValue]() OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.rateLimiting = true
+	}
+}
+
+// RateLimit returns the rate-limit descriptor of the matched route,
+// or nil if the route has none.
+func (fn *FoundNode[T]) RateLimit() *RateLimit {
+	if fn.meta == nil || fn.meta.rateLimit == nil {
+		return nil
+	}
+
+	rl := *fn.meta.rateLimit
+
+	return &rl
+}
+
+// Allowed returns whether the request is allowed by the rate-limit of
+// the matched route. It is always true, if the rate-limiting is not
+// enabled on the tree, or the route has no rate-limit.
+func (fn *FoundNode[T]) Allowed() bool {
+	return fn.allowed
+}
+
+// allow consumes a token of the route, if rate-limiting is enabled.
+func (t *Tree[T]) allow(rm *routeMeta) bool {
+	if !t.rateLimiting || rm.bucket == nil {
+		return true
+	}
+
+	return rm.bucket.take()
+}
+
+func (tb *tokenBucket) take() bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	current := now()
+
+	tb.tokens += current.Sub(tb.last).Seconds() * tb.limit.Rate
+	tb.last = current
+
+	if burst := float64(tb.limit.Burst); tb.tokens > burst {
+		tb.tokens = burst
+	}
+
+	if tb.tokens < 1 {
+		return false
+	}
+
+	tb.tokens--
+
+	return true
+}
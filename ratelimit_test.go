@@ -0,0 +1,84 @@
+package rtree
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	type testCase struct {
+		name       string
+		opts       []OptionFunc[*Route]
+		routeOpts  []RouteOption
+		advance    time.Duration
+		expected   []bool
+		expectedRL *RateLimit
+	}
+
+	tt := []testCase{
+		{
+			name:       "no rate-limit on route, always allowed",
+			opts:       []OptionFunc[*Route]{WithRateLimiting[*Route]()},
+			routeOpts:  nil,
+			expected:   []bool{true, true, true},
+			expectedRL: nil,
+		},
+		{
+			name:       "rate-limiting is not enabled, always allowed",
+			opts:       nil,
+			routeOpts:  []RouteOption{WithRateLimit(1, 1)},
+			expected:   []bool{true, true, true},
+			expectedRL: &RateLimit{Rate: 1, Burst: 1},
+		},
+		{
+			name:       "denied after burst is consumed",
+			opts:       []OptionFunc[*Route]{WithRateLimiting[*Route]()},
+			routeOpts:  []RouteOption{WithRateLimit(1, 2)},
+			expected:   []bool{true, true, false},
+			expectedRL: &RateLimit{Rate: 1, Burst: 2},
+		},
+		{
+			name:       "tokens are refilled over time",
+			opts:       []OptionFunc[*Route]{WithRateLimiting[*Route]()},
+			routeOpts:  []RouteOption{WithRateLimit(1, 1)},
+			advance:    time.Second,
+			expected:   []bool{true, true, true},
+			expectedRL: &RateLimit{Rate: 1, Burst: 1},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			current := time.Now()
+
+			now = func() time.Time { return current }
+			defer func() { now = time.Now }()
+
+			tree := New(tc.opts...)
+
+			if err := tree.Insert("/api/users/{id}", getRoute(), tc.routeOpts...); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			for i, exp := range tc.expected {
+				fn := tree.Find("/api/users/1")
+
+				if fn == nil {
+					t.Fatal("expected to find, but got <nil>")
+				}
+
+				if fn.Allowed() != exp {
+					t.Errorf("lookup %d: expected allowed: %v; got: %v\n", i, exp, fn.Allowed())
+				}
+
+				rl := fn.RateLimit()
+
+				if (rl == nil) != (tc.expectedRL == nil) || (rl != nil && *rl != *tc.expectedRL) {
+					t.Errorf("expected rate-limit: %v; got: %v\n", tc.expectedRL, rl)
+				}
+
+				current = current.Add(tc.advance)
+			}
+		})
+	}
+}
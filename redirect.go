@@ -0,0 +1,79 @@
+package rtree
+
+import (
+	"fmt"
+	"net/http"
+)
+
+var errBadRedirect = fmt.Errorf("[rtree %s]: bad redirect", version)
+
+type redirect struct {
+	target string
+	code   int
+}
+
+// InsertRedirect stores a route, whose lookups result in a redirect to
+// the target pattern with the given 3xx status code. The params of the
+// target are filled by the matched params, so every param of the target
+// must be the param of the key as well, eg. /old/{id} -> /new/{id}.
+func (t *Tree[T]) InsertRedirect(key, target string, code int, opts ...RouteOption) error {
+	if code < http.StatusMultipleChoices || code > 399 {
+		return fmt.Errorf("%w: status code %d", errBadRedirect, code)
+	}
+
+	if target == "" {
+		return fmt.Errorf("%w: %v", errBadRedirect, ErrKeyIsEmpty)
+	}
+
+	target = t.convertSyntax(target)
+
+	if err := checkUrl(target); err != nil {
+		return fmt.Errorf("%w: %v", errBadRedirect, err)
+	}
+
+	params := make(map[string]struct{})
+
+	for _, pi := range getPathParams(t.convertSyntax(key)) {
+		params[pi.key] = struct{}{}
+	}
+
+	for _, pi := range getPathParams(target) {
+		if _, exists := params[pi.key]; !exists {
+			return fmt.Errorf("%w: unknown param of target: %s", errBadRedirect, pi.key)
+		}
+	}
+
+	var zero T
+
+	return t.insert(key, zero, opts, func(nv *NodeValue[T]) {
+		nv.meta.redirect = &redirect{
+			target: target,
+			code:   code,
+		}
+	})
+}
+
+// IsRedirect reports whether the matched route is a redirect.
+func (fn *FoundNode[T]) IsRedirect() bool {
+	return fn.meta != nil && fn.meta.redirect != nil
+}
+
+// Redirect returns the target of the matched redirect route – with its
+// params filled by the matched params – and the status code of the
+// redirect. It returns an empty target and 0, if the route is not a redirect.
+func (fn *FoundNode[T]) Redirect() (string, int) {
+	if !fn.IsRedirect() {
+		return "", 0
+	}
+
+	rd := fn.meta.redirect
+
+	// Every param of the target is checked at insertion,
+	// so the expansion could only fail on bad values.
+	target, err := expand(rd.target, fn.params, false)
+	if err != nil {
+		return "", 0
+	}
+
+	return target, rd.code
+}
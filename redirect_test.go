@@ -0,0 +1,105 @@
+package rtree
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestInsertRedirect(t *testing.T) {
+	type testCase struct {
+		name   string
+		key    string
+		target string
+		code   int
+		err    error
+	}
+
+	tt := []testCase{
+		{
+			name:   "error on non-redirect status code",
+			key:    "/old/{id}",
+			target: "/new/{id}",
+			code:   http.StatusOK,
+			err:    errBadRedirect,
+		},
+		{
+			name:   "error on bad target",
+			key:    "/old/{id}",
+			target: "new/{id}",
+			code:   http.StatusMovedPermanently,
+			err:    errBadRedirect,
+		},
+		{
+			name:   "error on unknown param of target",
+			key:    "/old/{id}",
+			target: "/new/{slug}",
+			code:   http.StatusMovedPermanently,
+			err:    errBadRedirect,
+		},
+		{
+			name:   "error on bad key",
+			key:    "/old/{id",
+			target: "/new",
+			code:   http.StatusMovedPermanently,
+			err:    errBadPathParamSyntax,
+		},
+		{
+			name:   "no error on proper redirect",
+			key:    "/old/{resource}/{id}",
+			target: "/new/{id}",
+			code:   http.StatusMovedPermanently,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			if err := tree.InsertRedirect(tc.key, tc.target, tc.code); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestFindRedirect(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.InsertRedirect("/old/{resource}/{id}", "/new/{id}/{resource}", http.StatusPermanentRedirect); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/new/{id}/{resource}", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	fn := tree.Find("/old/products/5")
+	if fn == nil {
+		t.Fatal("expected to find, but got <nil>")
+	}
+
+	if !fn.IsRedirect() {
+		t.Fatal("expected redirect")
+	}
+
+	target, code := fn.Redirect()
+
+	if target != "/new/5/products" || code != http.StatusPermanentRedirect {
+		t.Errorf("expected redirect: %s %d; got: %s %d\n", "/new/5/products", http.StatusPermanentRedirect, target, code)
+	}
+
+	normal := tree.Find(target)
+	if normal == nil {
+		t.Fatal("expected to find, but got <nil>")
+	}
+
+	if normal.IsRedirect() {
+		t.Error("expected normal route not to be a redirect")
+	}
+
+	if target, code := normal.Redirect(); target != "" || code != 0 {
+		t.Errorf("expected no redirect; got: %s %d\n", target, code)
+	}
+}
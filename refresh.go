@@ -0,0 +1,41 @@
+package rtree
+
+import "context"
+
+// SnapshotSource fetches the current route table of a dynamic
+// source – eg. a service discovery backend –, see Refresh.
+type SnapshotSource func(ctx context.Context) (Snapshot, error)
+
+// Refresh revalidates the route table against the dynamic source, by
+// making the tree identical to the fetched snapshot. It is the stale-
+// while-revalidate mode of the snapshot fed trees: if the source could
+// not be reached, or its snapshot could not be decoded, the tree is left
+// untouched, and if the snapshot failed halfway through, it is rolled
+// back – see Apply –, so the tree keeps serving the last good table.
+// The error is returned, while the staleness of the table is told by the
+// LastSyncedAt of Stats, which is only updated by the successful refreshes.
+func (t *Tree[T]) Refresh(ctx context.Context, source SnapshotSource, codec Codec[T]) error {
+	if t == nil {
+		return ErrTreeIsNil
+	}
+
+	snap, err := source(ctx)
+	if err != nil {
+		return err
+	}
+
+	return t.revalidate(t.Restore(snap, codec))
+}
+
+// revalidate marks the tree as synced, if its update did not fail.
+func (t *Tree[T]) revalidate(err error) error {
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.syncedAt = now()
+	t.mu.Unlock()
+
+	return nil
+}
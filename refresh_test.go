@@ -0,0 +1,189 @@
+package rtree
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRefresh(t *testing.T) {
+	var (
+		codec   Codec[string] = JSONCodec[string]{}
+		synced                = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		errDown               = errors.New("discovery is down")
+	)
+
+	now = func() time.Time { return synced }
+	defer func() { now = time.Now }()
+
+	good := Snapshot{
+		Entries: []SnapshotEntry{
+			{Pattern: "/api/products", Value: []byte(`"products"`)},
+			{Pattern: "/api/users/{id}", Value: []byte(`"users"`)},
+		},
+	}
+
+	type testCase struct {
+		name     string
+		snap     Snapshot
+		err      error
+		isErr    bool
+		expected string
+	}
+
+	tt := []testCase{
+		{
+			name:  "unreachable source",
+			err:   errDown,
+			isErr: true,
+		},
+		{
+			name: "undecodable value",
+			snap: Snapshot{
+				Entries: []SnapshotEntry{
+					{Pattern: "/health", Value: []byte(`{`)},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "failure halfway through",
+			snap: Snapshot{
+				Entries: []SnapshotEntry{
+					{Pattern: "/api/{", Value: []byte(`"broken"`)},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "new table",
+			snap: Snapshot{
+				Entries: []SnapshotEntry{
+					{Pattern: "/api/users/{id}", Value: []byte(`"users-v2"`)},
+				},
+			},
+			expected: "users-v2",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[string]()
+
+			if err := tree.Refresh(context.Background(), func(context.Context) (Snapshot, error) {
+				return good, nil
+			}, codec); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			hash := tree.Hash()
+			synced = synced.Add(time.Minute)
+
+			err := tree.Refresh(context.Background(), func(context.Context) (Snapshot, error) {
+				return tc.snap, tc.err
+			}, codec)
+
+			if tc.isErr {
+				if err == nil {
+					t.Fatal("expected error, but got <nil>")
+				}
+
+				if tree.Hash() != hash {
+					t.Error("expected the last good table to be kept")
+				}
+
+				if got := tree.Stats().LastSyncedAt; !got.Equal(synced.Add(-time.Minute)) {
+					t.Errorf("expected last synced at: %v; got: %v\n", synced.Add(-time.Minute), got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if got := tree.Find("/api/users/1").GetValue(); got != tc.expected {
+				t.Errorf("expected value: %s; got: %s\n", tc.expected, got)
+			}
+
+			if tree.Find("/api/products") != nil {
+				t.Error("expected the removed route to be gone")
+			}
+
+			if got := tree.Stats().LastSyncedAt; !got.Equal(synced) {
+				t.Errorf("expected last synced at: %v; got: %v\n", synced, got)
+			}
+		})
+	}
+
+	if got := New[string]().Stats().LastSyncedAt; !got.IsZero() {
+		t.Errorf("expected zero last synced at of a new tree; got: %v\n", got)
+	}
+}
+
+func TestRefreshRollback(t *testing.T) {
+	var codec Codec[string] = JSONCodec[string]{}
+
+	guard, err := ParseGuard(`header.beta == "on"`)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tree := New[string](WithReadLocking[string]())
+
+	if err := tree.Insert("/api/products", "products"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/api/users/{id}", "users", WithGuard(guard)); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Alias("/api/users/{id}", "/legacy/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// The protected route fails the refresh after the
+	// products are deleted and the users are replaced.
+	if err := tree.InsertProtected("/zone", "zone"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	hash := tree.Hash()
+
+	err = tree.Refresh(context.Background(), func(context.Context) (Snapshot, error) {
+		return Snapshot{
+			Entries: []SnapshotEntry{
+				{Pattern: "/api/users/{id}", Value: []byte(`"users-v2"`)},
+				{Pattern: "/legacy/{id}", Value: []byte(`"users-v2"`)},
+				{Pattern: "/zone", Value: []byte(`"zone-v2"`)},
+			},
+		}, nil
+	}, codec)
+
+	if !errors.Is(err, errRouteIsProtected) {
+		t.Fatalf("expected error: %v; got: %v\n", errRouteIsProtected, err)
+	}
+
+	if tree.Hash() != hash {
+		t.Error("expected the last good table to be kept")
+	}
+
+	if got := tree.Find("/api/products").GetValue(); got != "products" {
+		t.Errorf("expected the deleted route to be put back; got: %s\n", got)
+	}
+
+	if tree.Find("/api/users/1") != nil {
+		t.Error("expected the guard of the replaced route to be put back")
+	}
+
+	if got := tree.FindAttrs("/api/users/1", map[string]string{"header.beta": "on"}).GetValue(); got != "users" {
+		t.Errorf("expected value: users; got: %s\n", got)
+	}
+
+	if got := tree.Find("/legacy/1").GetValue(); got != "users" {
+		t.Errorf("expected the alias to resolve the restored route; got: %s\n", got)
+	}
+}
@@ -0,0 +1,165 @@
+package rtree
+
+import (
+	"math/rand"
+	"strings"
+	"unicode/utf8"
+)
+
+// Handle is the matched leaf of a key, whose params and value are only
+// computed on demand. It is returned by Resolve, and it is not safe for
+// concurrent use.
+type Handle[T storeValue] struct {
+	tree   *Tree[T]
+	node   *Node[T]
+	target *NodeValue[T]
+
+	// params are the matched params, if they had
+	// to be matched already to accept the leaf.
+	params matchedParams
+}
+
+// Resolve searches for the key the same way as TryFind, but the params are
+// not matched – unless their matchers or policies have to decide the match
+// –, so a pipeline could reject the request – eg. by its authorization –
+// before paying for them. The trailing slash policy and the default route
+// are not applied, and the observer of the lookups is not notified.
+// It returns nil, if there is no match.
+func (t *Tree[T]) Resolve(key string) *Handle[T] {
+	if checkTree(t) != nil || key == "" {
+		return nil
+	}
+
+	if t.readLocking {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+	}
+
+	// The topics match their zero levels by a lookup of their own,
+	// which – being rare – is not worth deferring.
+	if t.syntax.isTopic() {
+		n, params, _ := t.lookup(key, nil, t.resolution)
+		if n == nil {
+			return nil
+		}
+
+		return &Handle[T]{tree: t, node: n, target: t.resolve(n.value), params: params}
+	}
+
+	n, params := t.resolveLookup(key)
+	if n == nil {
+		return nil
+	}
+
+	return &Handle[T]{tree: t, node: n, target: t.resolve(n.value), params: params}
+}
+
+// resolveLookup is the same as lookup, but it only matches the params of
+// the leaves, that have checks to run on them. It returns the matched leaf,
+// and its params – or nil, if they are not matched yet.
+func (t *Tree[T]) resolveLookup(key string) (*Node[T], matchedParams) {
+	if t.runeMatching && !utf8.ValidString(key) {
+		return nil, nil
+	}
+
+	var (
+		b      = &budget{limit: t.backtrackBudget}
+		params matchedParams
+		filter func(*NodeValue[T]) bool
+	)
+
+	accept := func(n *Node[T]) bool {
+		if filter != nil && !filter(n.value) {
+			return false
+		}
+
+		if n.value.meta.inactive() || !n.value.meta.guard.Eval(nil) {
+			return false
+		}
+
+		if !t.hasChecks(n.value) {
+			params = nil
+			return true
+		}
+
+		mp, ok := t.matchSegments(n.value, strings.Split(key, string(t.segmentDelimiter())), 0)
+		if ok {
+			params = mp
+		}
+		return ok
+	}
+
+	escaped := t.searchKey(key)
+
+	// The catch-all routes are searched in a first pass of their own.
+	if t.resolution == CatchAllFirst {
+		filter = (*NodeValue[T]).hasCatchAll
+
+		n := findRec(t.root, escaped, false, &search[T]{accept: accept, budget: b})
+		if n != nil && n.value != nil {
+			return n, params
+		}
+
+		if b.exceeded {
+			return nil, nil
+		}
+
+		filter = nil
+	}
+
+	n := findRec(t.root, escaped, false, &search[T]{accept: accept, budget: b})
+	if n == nil || n.value == nil {
+		return nil, nil
+	}
+
+	return n, params
+}
+
+// hasChecks reports whether the params of the route have to be
+// matched to decide, whether the route matches a key at all.
+func (t *Tree[T]) hasChecks(nv *NodeValue[T]) bool {
+	checks := nv.checks
+
+	// The values created by newNodeValue are compiled at their insertion.
+	if checks == nil && len(nv.params) > 0 {
+		checks = t.compileParams(nv)
+	}
+
+	for i := range checks {
+		pc := &checks[i]
+
+		if pc.policy != nil || pc.unknown || pc.nonEmpty || len(pc.matchers) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Pattern returns the pattern of the matched route.
+func (h *Handle[T]) Pattern() string {
+	return h.node.value.Pattern()
+}
+
+// Value returns the value of the matched route – or of the route it
+// is an alias of. The value of a split route is picked on every call.
+func (h *Handle[T]) Value() T {
+	return h.target.pick(rand.Intn)
+}
+
+// Params returns the params of the matched route in the key, which
+// must be the same, as the one given to Resolve. They are only matched
+// on the first call, unless they were matched by Resolve already.
+func (h *Handle[T]) Params(key string) matchedParams {
+	if h.params == nil {
+		h.params = matchParamsIn(h.node.value.params, strings.Split(key, string(h.tree.segmentDelimiter())), 0, h.tree.segmentDelimiter())
+	}
+
+	return h.params
+}
+
+// Found returns the full result of the lookup, as it would have been
+// returned by TryFind, with the params of the key matched.
+func (h *Handle[T]) Found(key string) *FoundNode[T] {
+	return h.tree.newFoundNode(h.node, h.Params(key))
+}
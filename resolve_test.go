@@ -0,0 +1,134 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestResolveEquivalence checks, that the handles give
+// the same results, as TryFind.
+func TestResolveEquivalence(t *testing.T) {
+	patterns := []string{
+		"/", "/api/users", "/api/users/{id}", "/api/users/{id}/posts", "/{lang:lang}/docs",
+		"/{page}/docs", "/static/{path...}", "/static/css", "/svc/{name}/{version}",
+	}
+
+	keys := []string{
+		"/", "/api/users", "/api/users/1", "/api/users/1/posts", "/en/docs", "/de/docs",
+		"/static/css", "/static/css/site.css", "/svc/orders/v2", "/svc/orders", "/missing/x/y", "",
+	}
+
+	for _, opts := range [][]OptionFunc[*Route]{
+		nil,
+		{WithResolutionOrder[*Route](CatchAllFirst)},
+		{WithCaseInsensitive[*Route](), WithReadLocking[*Route]()},
+	} {
+		tree := New(append(opts, WithSegmentMatcher[*Route]("lang", langMatcher))...)
+
+		for _, p := range patterns {
+			if err := tree.Insert(p, &Route{name: p}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		if err := tree.Alias("/api/users", "/people"); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		for _, key := range append(keys, "/people") {
+			var (
+				expected, _ = tree.TryFind(key)
+				h           = tree.Resolve(key)
+			)
+
+			if (expected == nil) != (h == nil) {
+				t.Errorf("%s: expected: %v; got: %v\n", key, expected, h)
+				continue
+			}
+
+			if expected == nil {
+				continue
+			}
+
+			if h.Pattern() != expected.GetPattern() || h.Value() != expected.GetValue() {
+				t.Errorf("%s: expected: %s; got: %s\n", key, expected.GetPattern(), h.Pattern())
+			}
+
+			if !reflect.DeepEqual(h.Params(key), expected.GetParams()) {
+				t.Errorf("%s: expected params: %v; got: %v\n", key, expected.GetParams(), h.Params(key))
+			}
+
+			if fn := h.Found(key); fn.GetPattern() != expected.GetPattern() || !reflect.DeepEqual(fn.GetParams(), expected.GetParams()) {
+				t.Errorf("%s: expected found: %v; got: %v\n", key, expected, fn)
+			}
+		}
+	}
+}
+
+func TestResolveDefersParams(t *testing.T) {
+	tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+	for _, p := range []string{"/api/users/{id}", "/{lang:lang}/docs"} {
+		if err := tree.Insert(p, &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	h := tree.Resolve("/api/users/1")
+
+	if h == nil || h.params != nil {
+		t.Fatalf("expected the params not to be matched; got: %v\n", h)
+	}
+
+	if id := h.Params("/api/users/1")["id"]; id != "1" {
+		t.Errorf("expected id: 1; got: %s\n", id)
+	}
+
+	// The matcher has to decide the match, so its params are matched by Resolve.
+	h = tree.Resolve("/en/docs")
+
+	if h == nil || h.params["lang"] != "en" {
+		t.Errorf("expected the params to be matched; got: %v\n", h)
+	}
+}
+
+func TestResolveMiss(t *testing.T) {
+	tree := New(WithDefaultRoute[*Route]("/404"), WithTrailingSlash[*Route](TrailingSlashIgnore))
+
+	for _, p := range []string{"/404", "/api/users"} {
+		if err := tree.Insert(p, &Route{}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// Neither the default route, nor the trailing slash policy applies.
+	for _, key := range []string{"/api/posts", "/api/users/", ""} {
+		if h := tree.Resolve(key); h != nil {
+			t.Errorf("%s: expected no match; got: %s\n", key, h.Pattern())
+		}
+	}
+
+	var nilTree *Tree[*Route]
+
+	if h := nilTree.Resolve("/api/users"); h != nil {
+		t.Errorf("expected no match in a <nil> tree; got: %s\n", h.Pattern())
+	}
+}
+
+func TestResolveTopic(t *testing.T) {
+	tree := New(WithPatternSyntax[*Route](SyntaxMQTT))
+
+	if err := tree.Insert("sport/#", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	h := tree.Resolve("sport")
+
+	if h == nil {
+		t.Fatalf("expected match; got <nil>\n")
+	}
+
+	if got, exists := h.Params("sport")["#"]; !exists || got != "" {
+		t.Errorf("expected empty #; got: %q (%t)\n", got, exists)
+	}
+}
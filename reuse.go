@@ -0,0 +1,46 @@
+package rtree
+
+// FindReuse is the same as Find, but the result is written to the given
+// FoundNode instead of a newly allocated one, and it reports whether there
+// was a match. A router serving many requests could keep a FoundNode per
+// worker – or in a sync.Pool –, so its hits do not allocate the result.
+// On a miss the given FoundNode is left untouched. The matched params are
+// still a new map, so they could be kept after the FoundNode is reused.
+func (t *Tree[T]) FindReuse(key string, out *FoundNode[T]) bool {
+	if out == nil || checkTree(t) != nil {
+		return false
+	}
+
+	fn := t.observe(key, func(key string) *FoundNode[T] {
+		if t.findInto(key, t.resolution, out) {
+			return out
+		}
+
+		return nil
+	})
+
+	return fn != nil
+}
+
+// findInto is the same as findWithDefault, but the result is written to out.
+func (t *Tree[T]) findInto(key string, order ResolutionOrder, out *FoundNode[T]) bool {
+	if n, params := t.cachedLookup(key, order); n != nil {
+		t.fillFoundNode(out, n, params)
+		return true
+	}
+
+	// The trailing slash and the default route are the rare
+	// cases, so they are not worth a path of their own.
+	fn := t.findTrailingSlash(key, order)
+	if fn == nil {
+		fn = t.findDefault(key, order)
+	}
+
+	if fn == nil {
+		return false
+	}
+
+	*out = *fn
+
+	return true
+}
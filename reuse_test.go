@@ -0,0 +1,94 @@
+package rtree
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFindReuse(t *testing.T) {
+	tree := New(WithTrailingSlash[*Route](TrailingSlashRedirect), WithDefaultRoute[*Route]("/404"))
+
+	for _, p := range []string{"/404", "/api/users", "/api/users/{id}", "/files/{path...}"} {
+		if err := tree.Insert(p, &Route{name: p}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	var out FoundNode[*Route]
+
+	for _, key := range []string{"/api/users", "/api/users/1", "/files/a/b", "/api/users/", "/missing"} {
+		expected := tree.Find(key)
+
+		if !tree.FindReuse(key, &out) {
+			t.Fatalf("%s: expected match; got none\n", key)
+		}
+
+		if out.GetPattern() != expected.GetPattern() || out.GetValue() != expected.GetValue() {
+			t.Errorf("%s: expected: %s; got: %s\n", key, expected.GetPattern(), out.GetPattern())
+		}
+
+		if !reflect.DeepEqual(out.GetParams(), expected.GetParams()) {
+			t.Errorf("%s: expected params: %v; got: %v\n", key, expected.GetParams(), out.GetParams())
+		}
+	}
+
+	// The redirect of the trailing slash is kept as well.
+	tree.FindReuse("/api/users/", &out)
+
+	if target, code := out.Redirect(); target != "/api/users" || code != http.StatusMovedPermanently {
+		t.Errorf("expected redirect to /api/users; got: %s %d\n", target, code)
+	}
+
+	if tree.FindReuse("/api/users/1", nil) {
+		t.Errorf("expected no match without a result\n")
+	}
+
+	var nilTree *Tree[*Route]
+
+	if nilTree.FindReuse("/api/users", &out) {
+		t.Errorf("expected no match in a <nil> tree\n")
+	}
+}
+
+func TestFindReuseMiss(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/users", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var out FoundNode[*Route]
+
+	if !tree.FindReuse("/api/users", &out) {
+		t.Fatalf("expected match; got none\n")
+	}
+
+	if tree.FindReuse("/api/posts", &out) {
+		t.Fatalf("expected no match; got: %s\n", out.GetPattern())
+	}
+
+	// A miss leaves the previous result untouched.
+	if out.GetPattern() != "/api/users" {
+		t.Errorf("expected pattern: /api/users; got: %s\n", out.GetPattern())
+	}
+}
+
+func TestFindReuseAllocs(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/users/{id}", &Route{}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var out FoundNode[*Route]
+
+	var (
+		found  = testing.AllocsPerRun(100, func() { tree.Find("/api/users/1") })
+		reused = testing.AllocsPerRun(100, func() { tree.FindReuse("/api/users/1", &out) })
+	)
+
+	if reused >= found {
+		t.Errorf("expected less allocations than %.0f; got: %.0f\n", found, reused)
+	}
+}
@@ -0,0 +1,37 @@
+package rtree
+
+import "time"
+
+// RouteOption configures a single route at the time of its insertion.
+type RouteOption func(*routeMeta)
+
+// routeMeta holds all the optional metadata of a stored route.
+type routeMeta struct {
+	rateLimit *RateLimit
+	bucket    *tokenBucket
+	redirect  *redirect
+	source    string
+	protected bool
+
+	// description is the human-readable documentation of the route.
+	description string
+
+	// timeout and retry configure the outbound call of the route.
+	timeout time.Duration
+	retry   *RetryPolicy
+
+	// sunset is the time of the removal of a deprecated route.
+	sunset time.Time
+
+	constraints map[string]SegmentMatcher
+	labels      map[string]string
+
+	// guard is the condition of the route on the attributes of the lookup.
+	guard *Guard
+
+	// disabled routes are skipped by the lookups, see Disable.
+	disabled bool
+
+	// window is the time window of the route being active, if any.
+	window *activeWindow
+}
@@ -0,0 +1,26 @@
+package rtree
+
+// RouteID returns the stable ID of the matched route. The IDs are
+// assigned at insertion – starting from 1 – and never reused, so they
+// make small, low-cardinality labels eg. for metrics.
+func (fn *FoundNode[T]) RouteID() uint64 {
+	return fn.id
+}
+
+// RouteID returns the stable ID of the stored route.
+func (nv *NodeValue[T]) RouteID() uint64 {
+	return nv.id
+}
+
+// RouteByID returns the stored route with the given ID,
+// or nil if there is no such route.
+func (t *Tree[T]) RouteByID(id uint64) *NodeValue[T] {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.routes[id]
+}
@@ -0,0 +1,52 @@
+package rtree
+
+import "testing"
+
+func TestRouteID(t *testing.T) {
+	tree := New[*Route]()
+
+	routes := []string{"/api/users/{id}", "/api/products", "/api"}
+
+	for _, r := range routes {
+		if err := tree.Insert(r, &Route{name: r}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	// Unsuccessful insert must not consume an ID.
+	if err := tree.Insert("/api", getRoute()); err == nil {
+		t.Fatal("expected error, but got <nil>")
+	}
+
+	if err := tree.Insert("/api/categories", &Route{name: "/api/categories"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	routes = append(routes, "/api/categories")
+
+	for i, r := range routes {
+		expectedID := uint64(i + 1)
+
+		fn := tree.Find(r)
+		if fn == nil {
+			t.Fatalf("expected to find %s, but got <nil>", r)
+		}
+
+		if fn.RouteID() != expectedID {
+			t.Errorf("%s: expected id: %d; got: %d\n", r, expectedID, fn.RouteID())
+		}
+
+		nv := tree.RouteByID(expectedID)
+		if nv == nil {
+			t.Fatalf("expected route by id: %d, but got <nil>", expectedID)
+		}
+
+		if nv.Pattern() != r || nv.RouteID() != expectedID {
+			t.Errorf("expected route: %s; got: %s\n", r, nv.Pattern())
+		}
+	}
+
+	if tree.RouteByID(0) != nil || tree.RouteByID(100) != nil {
+		t.Error("expected no route by unknown id")
+	}
+}
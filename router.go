@@ -0,0 +1,99 @@
+package rtree
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Router is the convenience facade of the method-aware route table, for
+// the common cases, where the trees, the method table and the adapters
+// need not be assembled by hand:
+//
+//	r := rtree.NewRouter[*Route]()
+//	r.GET("/users/{id}", &Route{})
+//	fn := r.Match("GET", "/users/5")
+//
+// The underlying table is available by MethodTrees, so the rest of the
+// features – eg. publishing the trees together – are still reachable.
+// The zero value is an empty router without options, ready to use.
+type Router[T storeValue] struct {
+	// init creates the table of the zero value on the first use.
+	init   sync.Once
+	routes *MethodTrees[T]
+}
+
+// NewRouter creates an empty router. The given options are
+// applied to the trees of every method.
+func NewRouter[T storeValue](opts ...OptionFunc[T]) *Router[T] {
+	return &Router[T]{
+		routes: NewMethodTrees(opts...),
+	}
+}
+
+// Handle stores the value of the pattern for the given method.
+func (r *Router[T]) Handle(method, pattern string, value T, opts ...RouteOption) error {
+	return r.MethodTrees().Insert(method, pattern, value, opts...)
+}
+
+// GET stores the value of the pattern for the GET method.
+func (r *Router[T]) GET(pattern string, value T, opts ...RouteOption) error {
+	return r.Handle(http.MethodGet, pattern, value, opts...)
+}
+
+// HEAD stores the value of the pattern for the HEAD method.
+func (r *Router[T]) HEAD(pattern string, value T, opts ...RouteOption) error {
+	return r.Handle(http.MethodHead, pattern, value, opts...)
+}
+
+// POST stores the value of the pattern for the POST method.
+func (r *Router[T]) POST(pattern string, value T, opts ...RouteOption) error {
+	return r.Handle(http.MethodPost, pattern, value, opts...)
+}
+
+// PUT stores the value of the pattern for the PUT method.
+func (r *Router[T]) PUT(pattern string, value T, opts ...RouteOption) error {
+	return r.Handle(http.MethodPut, pattern, value, opts...)
+}
+
+// PATCH stores the value of the pattern for the PATCH method.
+func (r *Router[T]) PATCH(pattern string, value T, opts ...RouteOption) error {
+	return r.Handle(http.MethodPatch, pattern, value, opts...)
+}
+
+// DELETE stores the value of the pattern for the DELETE method.
+func (r *Router[T]) DELETE(pattern string, value T, opts ...RouteOption) error {
+	return r.Handle(http.MethodDelete, pattern, value, opts...)
+}
+
+// OPTIONS stores the value of the pattern for the OPTIONS method.
+func (r *Router[T]) OPTIONS(pattern string, value T, opts ...RouteOption) error {
+	return r.Handle(http.MethodOptions, pattern, value, opts...)
+}
+
+// Match searches for the key among the routes of the given method.
+// It returns nil, if there is no match.
+func (r *Router[T]) Match(method, key string) *FoundNode[T] {
+	return r.MethodTrees().Find(method, key)
+}
+
+// Allowed returns the sorted methods, that have a route for the key.
+// An empty list means, that the path is unknown.
+func (r *Router[T]) Allowed(key string) []string {
+	return r.MethodTrees().Table().Allowed(key)
+}
+
+// MethodTrees returns the method-aware route table of the router,
+// eg. to serve it by NewHandler. A nil router has a nil table.
+func (r *Router[T]) MethodTrees() *MethodTrees[T] {
+	if r == nil {
+		return nil
+	}
+
+	r.init.Do(func() {
+		if r.routes == nil {
+			r.routes = NewMethodTrees[T]()
+		}
+	})
+
+	return r.routes
+}
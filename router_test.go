@@ -0,0 +1,107 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	r := NewRouter[*Route]()
+
+	inserts := []struct {
+		register func(string, *Route, ...RouteOption) error
+		pattern  string
+		name     string
+	}{
+		{r.GET, "/users/{id}", "get user"},
+		{r.PUT, "/users/{id}", "replace user"},
+		{r.PATCH, "/users/{id}", "update user"},
+		{r.DELETE, "/users/{id}", "delete user"},
+		{r.POST, "/users", "create user"},
+		{r.HEAD, "/users", "head users"},
+		{r.OPTIONS, "/users", "options users"},
+	}
+
+	for _, ins := range inserts {
+		if err := ins.register(ins.pattern, &Route{name: ins.name}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := r.Handle("get", "/health", &Route{name: "health"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := r.GET("/users/{id}", &Route{}); !errors.Is(err, errKeyIsAlreadyStored) {
+		t.Errorf("expected error: %v; got: %v\n", errKeyIsAlreadyStored, err)
+	}
+
+	tt := []struct {
+		method       string
+		key          string
+		expectedName string
+	}{
+		{method: "GET", key: "/users/5", expectedName: "get user"},
+		{method: "PUT", key: "/users/5", expectedName: "replace user"},
+		{method: "PATCH", key: "/users/5", expectedName: "update user"},
+		{method: "DELETE", key: "/users/5", expectedName: "delete user"},
+		{method: "POST", key: "/users", expectedName: "create user"},
+		{method: "HEAD", key: "/users", expectedName: "head users"},
+		{method: "OPTIONS", key: "/users", expectedName: "options users"},
+		{method: "GET", key: "/health", expectedName: "health"},
+		{method: "POST", key: "/users/5"},
+		{method: "GET", key: "/posts"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.method+" "+tc.key, func(t *testing.T) {
+			fn := r.Match(tc.method, tc.key)
+
+			if tc.expectedName == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil || fn.GetValue().name != tc.expectedName {
+				t.Fatalf("expected %s; got: %v\n", tc.expectedName, fn)
+			}
+		})
+	}
+
+	if allowed := r.Allowed("/users/5"); !reflect.DeepEqual(allowed, []string{"DELETE", "GET", "PATCH", "PUT"}) {
+		t.Errorf("expected allowed methods: [DELETE GET PATCH PUT]; got: %v\n", allowed)
+	}
+
+	var nilRouter *Router[*Route]
+
+	if err := nilRouter.GET("/users", &Route{}); !errors.Is(err, ErrTreeIsNil) {
+		t.Errorf("expected error: %v; got: %v\n", ErrTreeIsNil, err)
+	}
+
+	if fn := nilRouter.Match("GET", "/users"); fn != nil {
+		t.Errorf("expected no match of a nil router; got: %s\n", fn.GetPattern())
+	}
+}
+
+func TestRouterZeroValue(t *testing.T) {
+	var r Router[*Route]
+
+	if err := r.GET("/users/{id}", &Route{name: "get user"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn := r.Match("GET", "/users/5"); fn == nil || fn.GetValue().name != "get user" {
+		t.Fatalf("expected get user; got: %v\n", fn)
+	}
+
+	if allowed := r.Allowed("/users/5"); !reflect.DeepEqual(allowed, []string{"GET"}) {
+		t.Errorf("expected allowed methods: [GET]; got: %v\n", allowed)
+	}
+
+	if r.MethodTrees() != r.MethodTrees() {
+		t.Errorf("expected the same table on every call\n")
+	}
+}
@@ -0,0 +1,11 @@
+package rtree
+
+// WithRuneMatching enables the rune-aware mode of the tree: keys must be
+// valid UTF-8 at Insert, and lookups of invalid UTF-8 keys never match.
+// Since the nodes are always split on rune boundaries, static segments
+// and params with non-ASCII characters behave the same as ASCII ones.
+func WithRuneMatching[T storeValue]() OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.runeMatching = true
+	}
+}
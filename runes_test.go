@@ -0,0 +1,110 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLongestCommonPrefixRunes(t *testing.T) {
+	type testCase struct {
+		name     string
+		str1     string
+		str2     string
+		expected int
+	}
+
+	tt := []testCase{
+		{
+			name:     "ascii strings",
+			str1:     "/foo/bar",
+			str2:     "/foo/baz",
+			expected: 7,
+		},
+		{
+			name:     "runes with common first byte",
+			str1:     "/café",
+			str2:     "/cafè",
+			expected: 4,
+		},
+		{
+			name:     "same runes",
+			str1:     "/árvíztűrő",
+			str2:     "/árvíz",
+			expected: len("/árvíz"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := longestCommonPrefix(tc.str1, tc.str2); got != tc.expected {
+				t.Errorf("expected lcp: %d; got: %d\n", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRuneMatching(t *testing.T) {
+	tree := New(WithRuneMatching[*Route]())
+
+	routes := []string{"/café/{item}", "/cafè/menu", "/日本/{id}"}
+
+	for _, r := range routes {
+		if err := tree.Insert(r, &Route{name: r}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := tree.Insert("/bad/\xff", getRoute()); !errors.Is(err, errInvalidUTF8) {
+		t.Errorf("expected error: %v; got: %v\n", errInvalidUTF8, err)
+	}
+
+	err := tree.Walk(func(n *Node[*Route]) WalkVerdict {
+		if !utf8.ValidString(n.Key()) {
+			t.Errorf("expected valid UTF-8 node key; got: %q\n", n.Key())
+		}
+		return Continue
+	})
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	type testCase struct {
+		key            string
+		expectedName   string
+		expectedParams matchedParams
+	}
+
+	tt := []testCase{
+		{key: "/café/crème", expectedName: "/café/{item}", expectedParams: matchedParams{"item": "crème"}},
+		{key: "/cafè/menu", expectedName: "/cafè/menu", expectedParams: matchedParams{}},
+		{key: "/日本/東京", expectedName: "/日本/{id}", expectedParams: matchedParams{"id": "東京"}},
+		{key: "/café/\xff", expectedName: ""},
+	}
+
+	for _, tc := range tt {
+		fn := tree.Find(tc.key)
+
+		if tc.expectedName == "" {
+			if fn != nil {
+				t.Errorf("%q: expected not to find, but got route", tc.key)
+			}
+			continue
+		}
+
+		if fn == nil {
+			t.Errorf("%q: expected to find, but got <nil>", tc.key)
+			continue
+		}
+
+		if fn.GetValue().name != tc.expectedName {
+			t.Errorf("expected value: %s; got: %s\n", tc.expectedName, fn.GetValue().name)
+		}
+
+		for k, v := range tc.expectedParams {
+			if fn.GetParams()[k] != v {
+				t.Errorf("expected param %s: %s; got: %s\n", k, v, fn.GetParams()[k])
+			}
+		}
+	}
+}
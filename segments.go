@@ -0,0 +1,32 @@
+package rtree
+
+import "strings"
+
+// FindSegments is the same as Find, but it takes the already split path,
+// eg. []string{"api", "users", "1"} for /api/users/1. The segments are
+// joined by the segment delimiter of the tree – and prefixed by it, unless
+// the tree has object keys –, and the key is searched by Find, so the
+// trailing slash policy and the normalizations of the keys are applied
+// the same way. Segments containing the delimiter never match.
+func (t *Tree[T]) FindSegments(segments []string) *FoundNode[T] {
+	if err := checkTree(t); err != nil {
+		return nil
+	}
+
+	delimiter := t.segmentDelimiter()
+
+	for _, seg := range segments {
+		if strings.IndexByte(seg, delimiter) != -1 {
+			return nil
+		}
+	}
+
+	key := strings.Join(segments, string(delimiter))
+
+	// The object keys have no leading delimiter.
+	if !t.objectKeys {
+		key = string(delimiter) + key
+	}
+
+	return t.Find(key)
+}
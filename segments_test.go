@@ -0,0 +1,122 @@
+package rtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindSegments(t *testing.T) {
+	type testCase struct {
+		name            string
+		segments        []string
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	tree := New[*Route]()
+
+	for _, k := range []string{
+		"/",
+		"/api/users/{id}",
+		"/api/{resource}/{id}/reviews",
+		"/files/{path...}",
+	} {
+		if err := tree.Insert(k, &Route{name: k}); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:            "no segments is the root",
+			segments:        []string{},
+			expectedPattern: "/",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "single param",
+			segments:        []string{"api", "users", "1"},
+			expectedPattern: "/api/users/{id}",
+			expectedParams:  matchedParams{"id": "1"},
+		},
+		{
+			name:            "multiple params",
+			segments:        []string{"api", "products", "2", "reviews"},
+			expectedPattern: "/api/{resource}/{id}/reviews",
+			expectedParams:  matchedParams{"resource": "products", "id": "2"},
+		},
+		{
+			name:            "catch-all",
+			segments:        []string{"files", "a", "b.txt"},
+			expectedPattern: "/files/{path...}",
+			expectedParams:  matchedParams{"path": "a/b.txt"},
+		},
+		{
+			name:            "no match on segment with slash",
+			segments:        []string{"api", "users/1"},
+			expectedPattern: "",
+		},
+		{
+			name:            "no match",
+			segments:        []string{"api", "orders"},
+			expectedPattern: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := tree.FindSegments(tc.segments)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Errorf("expected not to find, but got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatal("expected to find, but got <nil>")
+			}
+
+			if got := fn.GetPattern(); got != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, got)
+			}
+
+			if got := fn.GetParams(); !reflect.DeepEqual(tc.expectedParams, got) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, got)
+			}
+		})
+	}
+}
+
+func TestFindSegmentsNormalized(t *testing.T) {
+	tree := New(
+		WithTrailingSlash[*Route](TrailingSlashIgnore),
+		WithCaseInsensitive[*Route](),
+	)
+
+	if err := tree.Insert("/api/users/{id}", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// The segments are searched the same way as their joined key by Find.
+	for _, segments := range [][]string{
+		{"API", "Users", "Ab"},
+		{"api", "users", "7", ""},
+		{"api", "%75sers", "a%2fb"},
+	} {
+		var (
+			fn       = tree.FindSegments(segments)
+			expected = tree.Find("/" + strings.Join(segments, "/"))
+		)
+
+		if fn == nil || expected == nil {
+			t.Fatalf("expected match of %v; got: %v %v\n", segments, fn, expected)
+		}
+
+		if fn.GetPattern() != expected.GetPattern() || !reflect.DeepEqual(fn.GetParams(), expected.GetParams()) {
+			t.Errorf("expected %s %v; got: %s %v\n", expected.GetPattern(), expected.GetParams(), fn.GetPattern(), fn.GetParams())
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package rtree
+
+import "strings"
+
+// shadowSample is the value used in place of the path params,
+// when building a concrete key of a pattern. A NUL byte is not expected
+// to be the part of any static segment, so it could only match params.
+const shadowSample = "\x00"
+
+// ShadowReport describes a stored pattern that can never be matched,
+// because an other pattern always wins over it under the current precedence.
+// The suggestions are alternative fixes of it.
+type ShadowReport struct {
+	Pattern     string
+	ShadowedBy  string
+	Suggestions []Suggestion
+}
+
+// FindShadowed returns all the leaves that can never be matched by Find.
+// A static pattern is shadowed, if its own key resolves to another leaf.
+// A pattern with params is shadowed, if a sample key of it resolves to a
+// leaf whose pattern is at least as general as the pattern itself.
+// Patterns with segment matchers are not analyzed, since no sample
+// key could be built for them. Neither are the disabled, the inactive –
+// see InsertWindowed – and the guarded routes, since Find skips them on
+// purpose, see WithGuard.
+func (t *Tree[T]) FindShadowed() []ShadowReport {
+	if err := checkTree(t); err != nil {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	reports := make([]ShadowReport, 0)
+
+	for _, leaf := range getAllLeafRec(t.root) {
+		var (
+			nv      = leaf.value
+			general = len(nv.params) > 0
+		)
+
+		if hasMatcher(nv.params) || nv.meta.inactive() || !nv.meta.guard.Eval(nil) {
+			continue
+		}
+
+		winner, _ := t.findNode(samplePattern(nv.pattern))
+
+		if winner == leaf {
+			continue
+		}
+
+		// A static key that can not be found at all is unreachable,
+		// while a missing sample of a general pattern proves nothing.
+		if winner == nil {
+			if !general {
+				reports = append(reports, ShadowReport{
+					Pattern:     nv.Pattern(),
+					Suggestions: t.suggestFixes(nv.pattern, ""),
+				})
+			}
+			continue
+		}
+
+		if general && !patternCovers(winner.value.pattern, nv.pattern) {
+			continue
+		}
+
+		reports = append(reports, ShadowReport{
+			Pattern:     nv.Pattern(),
+			ShadowedBy:  winner.value.Pattern(),
+			Suggestions: t.suggestFixes(nv.pattern, winner.value.pattern),
+		})
+	}
+
+	return reports
+}
+
+func hasMatcher(params []paramInfo) bool {
+	for _, pi := range params {
+		if pi.matcher != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isParamSegment(segment string) bool {
+	l := len(segment)
+
+	return l >= 2 && segment[0] == curlyStart && segment[l-1] == curlyEnd
+}
+
+// samplePattern replaces all the params of the pattern with a sample value.
+func samplePattern(pattern string) string {
+	segments := strings.Split(pattern, string(slash))
+
+	for i, seg := range segments {
+		if isParamSegment(seg) {
+			segments[i] = shadowSample
+			continue
+		}
+
+		segments[i] = unescape(seg)
+	}
+
+	return strings.Join(segments, string(slash))
+}
+
+// patternCovers reports whether every key matching the specific pattern
+// would also match the general one.
+func patternCovers(general, specific string) bool {
+	var (
+		gSegments = strings.Split(general, string(slash))
+		sSegments = strings.Split(specific, string(slash))
+	)
+
+	for i, gs := range gSegments {
+		if i >= len(sSegments) {
+			return false
+		}
+
+		ss := sSegments[i]
+
+		if !isParamSegment(gs) {
+			if gs != ss {
+				return false
+			}
+			continue
+		}
+
+		_, gMatcher, gCatchAll := parseParam(gs[1 : len(gs)-1])
+
+		// A catch-all covers all the remaining segments, but
+		// only if it covers the current one as well.
+		if gCatchAll {
+			return gMatcher == "" || isParamSegment(ss) && paramMatcher(ss) == gMatcher
+		}
+
+		// While a catch-all is never covered by a single segment.
+		if isParamSegment(ss) && isCatchAll(ss) {
+			return false
+		}
+
+		if gMatcher == "" {
+			continue
+		}
+
+		if !isParamSegment(ss) {
+			return false
+		}
+
+		if paramMatcher(ss) != gMatcher {
+			return false
+		}
+	}
+
+	return len(gSegments) == len(sSegments)
+}
+
+func isCatchAll(segment string) bool {
+	return strings.HasSuffix(segment, catchAllSuffix+string(curlyEnd))
+}
+
+// paramMatcher returns the name of the matcher of the param segment.
+func paramMatcher(segment string) string {
+	_, matcher, _ := parseParam(segment[1 : len(segment)-1])
+
+	return matcher
+}
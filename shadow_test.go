@@ -0,0 +1,164 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindShadowed(t *testing.T) {
+	type testCase struct {
+		name     string
+		routes   []string
+		disabled []string
+		guarded  map[string]string
+		expected []ShadowReport
+	}
+
+	tt := []testCase{
+		{
+			name:     "no shadowed routes",
+			routes:   []string{"/api/{resource}/get", "/api/products/get-all", "/api/users"},
+			expected: []ShadowReport{},
+		},
+		{
+			name:     "static route inserted after the wildcard route",
+			routes:   []string{"/api/{resource}/get", "/api/products/get"},
+			expected: []ShadowReport{},
+		},
+		{
+			name:     "static route inserted before the wildcard route",
+			routes:   []string{"/api/products/get", "/api/{resource}/get"},
+			expected: []ShadowReport{},
+		},
+		{
+			name:   "wildcard route shadowed by an other wildcard route",
+			routes: []string{"/api/{resource}/{id}", "/api/{name}/{key}"},
+			expected: []ShadowReport{
+				{
+					Pattern:    "/api/{resource}/{id}",
+					ShadowedBy: "/api/{name}/{key}",
+					Suggestions: []Suggestion{
+						{
+							Kind:    SuggestRemoveRoute,
+							Pattern: "/api/{resource}/{id}",
+							Message: "/api/{resource}/{id} duplicates /api/{name}/{key}, remove it",
+						},
+						{
+							Kind:        SuggestAddConstraint,
+							Pattern:     "/api/{name}/{key}",
+							Replacement: "/api/{name}/{key:int}",
+							Message:     "add constraint {key:int} to /api/{name}/{key} to disambiguate",
+						},
+						{
+							Kind:        SuggestRenameSegment,
+							Pattern:     "/api/{resource}/{id}",
+							Replacement: "/api/resource/{id}",
+							Message:     "rename the segment {resource} of /api/{resource}/{id} to a static one",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "disabled static route",
+			routes:   []string{"/api/users", "/api/{resource}/get"},
+			disabled: []string{"/api/users"},
+			expected: []ShadowReport{},
+		},
+		{
+			name:     "disabled wildcard route",
+			routes:   []string{"/api/{resource}/get", "/{any...}"},
+			disabled: []string{"/api/{resource}/get"},
+			expected: []ShadowReport{},
+		},
+		{
+			name:     "guarded static route",
+			routes:   []string{"/api/beta", "/api/{resource}/get"},
+			guarded:  map[string]string{"/api/beta": `cookie.beta == "on"`},
+			expected: []ShadowReport{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			for _, r := range tc.routes {
+				var opts []RouteOption
+
+				if expr, ok := tc.guarded[r]; ok {
+					g, err := ParseGuard(expr)
+					if err != nil {
+						t.Fatalf("not expected error, but got: %v\n", err)
+					}
+
+					opts = append(opts, WithGuard(g))
+				}
+
+				if err := tree.Insert(r, getRoute(), opts...); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			for _, r := range tc.disabled {
+				if err := tree.Disable(r); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			if got := tree.FindShadowed(); !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected reports: %v; got: %v\n", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPatternCovers(t *testing.T) {
+	type testCase struct {
+		name     string
+		general  string
+		specific string
+		expected bool
+	}
+
+	tt := []testCase{
+		{
+			name:     "different segment count",
+			general:  "/api/{id}",
+			specific: "/api/foo/bar",
+			expected: false,
+		},
+		{
+			name:     "param covers static",
+			general:  "/api/{id}",
+			specific: "/api/foo",
+			expected: true,
+		},
+		{
+			name:     "static does not cover param",
+			general:  "/api/foo",
+			specific: "/api/{id}",
+			expected: false,
+		},
+		{
+			name:     "param with matcher does not cover param without",
+			general:  "/api/{id:int}",
+			specific: "/api/{id}",
+			expected: false,
+		},
+		{
+			name:     "param with matcher covers param with same matcher",
+			general:  "/api/{id:int}",
+			specific: "/api/{key:int}",
+			expected: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := patternCovers(tc.general, tc.specific); got != tc.expected {
+				t.Errorf("expected: %v; got: %v\n", tc.expected, got)
+			}
+		})
+	}
+}
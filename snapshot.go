@@ -0,0 +1,263 @@
+package rtree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Codec converts the stored values to bytes and back,
+// so the route table could be transferred or persisted.
+type Codec[T storeValue] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec is a Codec based on encoding/json.
+type JSONCodec[T storeValue] struct{}
+
+func (JSONCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+
+	err := json.Unmarshal(data, &value)
+
+	return value, err
+}
+
+// SnapshotEntry is a single stored route in its serialized form. The
+// pattern is in its stored form – the curly syntax with the default
+// delimiters –, so the snapshots are only restored by the trees of
+// the same options, as they are.
+type SnapshotEntry struct {
+	Pattern string `json:"pattern"`
+	Value   []byte `json:"value"`
+}
+
+// Snapshot is the serialized form of the whole route table, taken at
+// the given generation. The entries are sorted by their patterns. The
+// route metadata – eg. rate-limits – are not the part of the snapshot.
+type Snapshot struct {
+	Generation uint64          `json:"generation"`
+	Entries    []SnapshotEntry `json:"entries"`
+}
+
+// Changes describes how to get from one snapshot to an other.
+type Changes struct {
+	Upserts []SnapshotEntry `json:"upserts"`
+	Deletes []string        `json:"deletes"`
+}
+
+// IsEmpty reports whether there is no change at all.
+func (c Changes) IsEmpty() bool {
+	return len(c.Upserts) == 0 && len(c.Deletes) == 0
+}
+
+// Snapshot returns the serialized form of the route table.
+func (t *Tree[T]) Snapshot(codec Codec[T]) (Snapshot, error) {
+	snap := Snapshot{
+		Entries: make([]SnapshotEntry, 0),
+	}
+
+	if t == nil {
+		return snap, ErrTreeIsNil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snap.Generation = t.Generation()
+
+	if t.root == nil {
+		return snap, nil
+	}
+
+	for _, l := range getAllLeafRec(t.root) {
+		data, err := codec.Marshal(t.resolve(l.value).value)
+		if err != nil {
+			return snap, err
+		}
+
+		snap.Entries = append(snap.Entries, SnapshotEntry{
+			Pattern: l.value.pattern,
+			Value:   data,
+		})
+	}
+
+	sort.Slice(snap.Entries, func(i, j int) bool {
+		return snap.Entries[i].Pattern < snap.Entries[j].Pattern
+	})
+
+	return snap, nil
+}
+
+// Restore makes the route table identical to the given snapshot,
+// by applying only the differences between the two.
+func (t *Tree[T]) Restore(snap Snapshot, codec Codec[T]) error {
+	current, err := t.Snapshot(codec)
+	if err != nil {
+		return err
+	}
+
+	return t.Apply(DiffSnapshots(current, snap), codec)
+}
+
+// Apply applies the given changes on the route table atomically: the
+// changes are made under a single lock – so the locked lookups, see
+// WithReadLocking, never see a half-applied table –, and if one of them
+// fails, the ones already applied are rolled back, putting back the
+// previous versions of the routes with their metadata. The patterns of
+// the changes are in their stored form – as they are taken by Snapshot –,
+// so they are not normalized again.
+func (t *Tree[T]) Apply(changes Changes, codec Codec[T]) error {
+	if t == nil {
+		return ErrTreeIsNil
+	}
+
+	// Everything is decoded before the table is touched.
+	values := make([]*NodeValue[T], 0, len(changes.Upserts))
+
+	for _, e := range changes.Upserts {
+		value, err := codec.Unmarshal(e.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.Pattern, err)
+		}
+
+		nv, err := t.newStoredValue(e.Pattern, value)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, nv)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	undo := make([]undoEntry[T], 0, len(changes.Deletes)+len(values))
+
+	if err := t.applyLocked(changes.Deletes, values, &undo); err != nil {
+		if rollbackErr := t.rollback(undo); rollbackErr != nil {
+			return newMultiError([]error{err, rollbackErr})
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// undoEntry is the version of the route of the pattern
+// before a change of Apply – or nil, if there was none.
+type undoEntry[T storeValue] struct {
+	pattern  string
+	previous *NodeValue[T]
+}
+
+// applyLocked applies the deletes and the upserts, recording the
+// previous version of every changed route to the undo log.
+// The caller must hold the write lock.
+func (t *Tree[T]) applyLocked(deletes []string, values []*NodeValue[T], undo *[]undoEntry[T]) error {
+	record := func(pattern string) {
+		e := undoEntry[T]{pattern: pattern}
+
+		if n := findExactRec(t.root, pattern); n != nil {
+			e.previous = n.value
+		}
+
+		*undo = append(*undo, e)
+	}
+
+	for _, key := range deletes {
+		record(key)
+
+		if err := t.deleteLocked(key, false); err != nil {
+			return err
+		}
+	}
+
+	for _, nv := range values {
+		record(nv.pattern)
+
+		if err := t.upsertLocked(nv, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollback undoes the changes of the undo log in reverse order: the
+// previous versions are put back – keeping their IDs, so their aliases
+// resolve them again –, and the newly stored routes are removed.
+// The caller must hold the write lock.
+func (t *Tree[T]) rollback(undo []undoEntry[T]) error {
+	errs := make([]error, 0)
+
+	for i := len(undo) - 1; i >= 0; i-- {
+		var (
+			e    = undo[i]
+			path = findExactPath(t.root, e.pattern)
+			err  error
+		)
+
+		switch {
+		case e.previous == nil && path != nil:
+			t.remove(path, ChangeDelete)
+			err = t.unpersist(e.pattern)
+		case e.previous == nil:
+			// The change failed before storing anything.
+		case path == nil:
+			if err = t.store(e.previous); err == nil {
+				err = t.persist(e.previous)
+			}
+		case path[len(path)-1].value != e.previous:
+			err = t.replace(path[len(path)-1], e.previous)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return newMultiError(errs)
+}
+
+// DiffSnapshots returns the changes needed to get from the old
+// snapshot to the new one. Values are compared by their bytes.
+func DiffSnapshots(old, new Snapshot) Changes {
+	changes := Changes{
+		Upserts: make([]SnapshotEntry, 0),
+		Deletes: make([]string, 0),
+	}
+
+	oldValues := make(map[string][]byte, len(old.Entries))
+
+	for _, e := range old.Entries {
+		oldValues[e.Pattern] = e.Value
+	}
+
+	for _, e := range new.Entries {
+		value, exists := oldValues[e.Pattern]
+
+		delete(oldValues, e.Pattern)
+
+		if exists && bytes.Equal(value, e.Value) {
+			continue
+		}
+
+		changes.Upserts = append(changes.Upserts, e)
+	}
+
+	for pattern := range oldValues {
+		changes.Deletes = append(changes.Deletes, pattern)
+	}
+
+	sort.Strings(changes.Deletes)
+
+	return changes
+}
@@ -0,0 +1,224 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func buildStringTree(t *testing.T, routes map[string]string) *Tree[string] {
+	tree := New[string]()
+
+	for k, v := range routes {
+		if err := tree.Insert(k, v); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	return tree
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	old := Snapshot{
+		Entries: []SnapshotEntry{
+			{Pattern: "/a", Value: []byte("1")},
+			{Pattern: "/b", Value: []byte("2")},
+			{Pattern: "/c", Value: []byte("3")},
+		},
+	}
+
+	new := Snapshot{
+		Entries: []SnapshotEntry{
+			{Pattern: "/a", Value: []byte("1")},
+			{Pattern: "/b", Value: []byte("changed")},
+			{Pattern: "/d", Value: []byte("4")},
+		},
+	}
+
+	expected := Changes{
+		Upserts: []SnapshotEntry{
+			{Pattern: "/b", Value: []byte("changed")},
+			{Pattern: "/d", Value: []byte("4")},
+		},
+		Deletes: []string{"/c"},
+	}
+
+	if got := DiffSnapshots(old, new); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected changes: %v; got: %v\n", expected, got)
+	}
+
+	if !DiffSnapshots(new, new).IsEmpty() {
+		t.Error("expected no changes between same snapshots")
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	var (
+		codec Codec[string] = JSONCodec[string]{}
+
+		source = buildStringTree(t, map[string]string{
+			"/api/users/{id}": "users",
+			"/api/products":   "products",
+			"/health":         "health",
+		})
+
+		target = buildStringTree(t, map[string]string{
+			"/api/products": "old-products",
+			"/api/legacy":   "legacy",
+		})
+	)
+
+	snap, err := source.Snapshot(codec)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if len(snap.Entries) != 3 || snap.Entries[0].Pattern != "/api/products" {
+		t.Fatalf("expected sorted entries; got: %v\n", snap.Entries)
+	}
+
+	if err := target.Restore(snap, codec); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if source.Hash() != target.Hash() {
+		t.Error("expected restored tree to have the same routes")
+	}
+
+	if got := target.Find("/api/products").GetValue(); got != "products" {
+		t.Errorf("expected value: %s; got: %s\n", "products", got)
+	}
+
+	if target.Find("/api/legacy") != nil {
+		t.Error("expected not to find deleted route")
+	}
+}
+
+func TestRoundTripSyntaxes(t *testing.T) {
+	type testCase struct {
+		name           string
+		opts           []OptionFunc[string]
+		pattern        string
+		key            string
+		expectedParams matchedParams
+	}
+
+	tt := []testCase{
+		{
+			name:           "curly syntax",
+			pattern:        "/files/{dir}/{path...}",
+			key:            "/files/docs/a/b.txt",
+			expectedParams: matchedParams{"dir": "docs", "path": "a/b.txt"},
+		},
+		{
+			name:           "colon syntax",
+			opts:           []OptionFunc[string]{WithPatternSyntax[string](SyntaxColon)},
+			pattern:        "/files/:dir/*path",
+			key:            "/files/docs/a/b.txt",
+			expectedParams: matchedParams{"dir": "docs", "path": "a/b.txt"},
+		},
+		{
+			name:           "mqtt syntax",
+			opts:           []OptionFunc[string]{WithPatternSyntax[string](SyntaxMQTT)},
+			pattern:        "sport/+/player/#",
+			key:            "sport/tennis/player/1",
+			expectedParams: matchedParams{"1": "tennis", "#": "1"},
+		},
+		{
+			name: "amqp syntax",
+			opts: []OptionFunc[string]{
+				WithPatternSyntax[string](SyntaxAMQP),
+				WithDelimiters[string](Delimiters{Segment: '.', ParamStart: '{', ParamEnd: '}'}),
+			},
+			pattern:        "sport.*.player",
+			key:            "sport.tennis.player",
+			expectedParams: matchedParams{"1": "tennis"},
+		},
+		{
+			name:           "custom delimiters",
+			opts:           []OptionFunc[string]{WithDelimiters[string](Delimiters{Segment: '.', ParamStart: '<', ParamEnd: '>'})},
+			pattern:        "orders.<region>.created",
+			key:            "orders.eu.created",
+			expectedParams: matchedParams{"region": "eu"},
+		},
+		{
+			name:           "case-insensitive",
+			opts:           []OptionFunc[string]{WithCaseInsensitive[string]()},
+			pattern:        "/API/Users/{ID}",
+			key:            "/api/users/5",
+			expectedParams: matchedParams{"ID": "5"},
+		},
+	}
+
+	var codec Codec[string] = JSONCodec[string]{}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			check := func(tree *Tree[string]) {
+				t.Helper()
+
+				fn := tree.Find(tc.key)
+				if fn == nil {
+					t.Fatalf("expected to find %s, but got <nil>\n", tc.key)
+				}
+
+				if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+					t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+				}
+			}
+
+			store, err := NewFSStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			tree := New(append([]OptionFunc[string]{WithStore[string](store, codec)}, tc.opts...)...)
+
+			if err := tree.Insert(tc.pattern, "value"); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			check(tree)
+
+			// Snapshot → Restore.
+			snap, err := tree.Snapshot(codec)
+			if err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			restored := New(tc.opts...)
+
+			if err := restored.Restore(snap, codec); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			check(restored)
+
+			if restored.Hash() != tree.Hash() {
+				t.Error("expected the restored tree to be the same")
+			}
+
+			// Restoring the same snapshot again changes nothing.
+			if err := restored.Restore(snap, codec); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			// Save → Load.
+			loaded := New(append([]OptionFunc[string]{WithStore[string](store, codec)}, tc.opts...)...)
+
+			if err := loaded.Load(); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			check(loaded)
+
+			// The routes could be removed by the restored snapshots.
+			if err := loaded.Restore(Snapshot{}, codec); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			if loaded.Find(tc.key) != nil {
+				t.Errorf("expected not to find %s, but got route\n", tc.key)
+			}
+		})
+	}
+}
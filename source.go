@@ -0,0 +1,37 @@
+package rtree
+
+import "fmt"
+
+// WithSource labels the route with its source – eg. file:line, the name
+// of a config or the registering module –, so conflicts could be traced
+// back to whoever registered the other route.
+func WithSource(source string) RouteOption {
+	return func(rm *routeMeta) {
+		rm.source = source
+	}
+}
+
+// Source returns the source label of the route, if any.
+func (nv *NodeValue[T]) Source() string {
+	return nv.meta.source
+}
+
+// Source returns the source label of the matched route, if any.
+func (fn *FoundNode[T]) Source() string {
+	if fn.meta == nil {
+		return ""
+	}
+
+	return fn.meta.source
+}
+
+// conflictError extends the error of a conflicting insertion with
+// the source of the already stored route, if it is known.
+func (t *Tree[T]) conflictError(err error, key string) error {
+	n := findExactRec(t.root, key)
+	if n == nil || n.value == nil || n.value.meta.source == "" {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s is registered by %s", err, key, n.value.meta.source)
+}
@@ -0,0 +1,88 @@
+package rtree
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInsertConflictSource(t *testing.T) {
+	type testCase struct {
+		name            string
+		opts            []RouteOption
+		expectedMessage string
+	}
+
+	tt := []testCase{
+		{
+			name:            "conflict without source",
+			opts:            nil,
+			expectedMessage: "",
+		},
+		{
+			name:            "conflict with the source of the stored route",
+			opts:            []RouteOption{WithSource("routes/users.go:12")},
+			expectedMessage: "/api/users/{id} is registered by routes/users.go:12",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			if err := tree.Insert("/api/users/{id}", getRoute(), tc.opts...); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			err := tree.Insert("/api/users/{id}", getRoute(), WithSource("routes/admin.go:40"))
+
+			if !errors.Is(err, errKeyIsAlreadyStored) {
+				t.Fatalf("expected error: %v; got: %v\n", errKeyIsAlreadyStored, err)
+			}
+
+			if tc.expectedMessage == "" {
+				if err != errKeyIsAlreadyStored {
+					t.Errorf("expected plain error; got: %v\n", err)
+				}
+				return
+			}
+
+			if !strings.Contains(err.Error(), tc.expectedMessage) {
+				t.Errorf("expected error to contain: %s; got: %v\n", tc.expectedMessage, err)
+			}
+		})
+	}
+}
+
+func TestSource(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Insert("/api/users/{id}", getRoute(), WithSource("config/users.yaml")); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/api/products", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := tree.Find("/api/users/1").Source(); got != "config/users.yaml" {
+		t.Errorf("expected source: %s; got: %s\n", "config/users.yaml", got)
+	}
+
+	if got := tree.Find("/api/products").Source(); got != "" {
+		t.Errorf("expected no source; got: %s\n", got)
+	}
+
+	rec := httptest.NewRecorder()
+
+	AdminHandler(tree).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/__routes", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+
+	if !strings.Contains(string(body), `"source":"config/users.yaml"`) {
+		t.Errorf("expected the source in the listing; got: %s\n", body)
+	}
+}
@@ -0,0 +1,144 @@
+package rtree
+
+import (
+	"sort"
+	"strings"
+)
+
+// The kinds of the first param of the patterns, from the least specific.
+const (
+	firstCatchAll = iota
+	firstParam
+	// firstNone is the kind of the patterns without params.
+	firstNone
+
+	firstKinds
+)
+
+// Specificity returns the precedence score of the pattern – in the native
+// syntax –: of two patterns matching the same key, the tree tries the one
+// with the higher score first. The score is decided by the static bytes
+// before the first param – since a static branch is tried before the
+// wildcard ones –, then by the kind of the first param, a catch-all being
+// the least specific. The patterns with the same score are ordered by
+// ComparePatterns.
+func Specificity(pattern string) int {
+	var (
+		start = indexUnescaped(pattern, curlyStart)
+		kind  = firstNone
+	)
+
+	if start == -1 {
+		return staticLen(pattern)*firstKinds + kind
+	}
+
+	kind = firstParam
+
+	if end := indexUnescaped(pattern[start:], curlyEnd); end != -1 && strings.HasSuffix(pattern[start:start+end], catchAllSuffix) {
+		kind = firstCatchAll
+	}
+
+	return staticLen(pattern[:start])*firstKinds + kind
+}
+
+// ComparePatterns returns a negative number, if the tree tries the pattern a
+// before b, a positive one if it tries b first, and 0 if they are the same.
+// The patterns are compared, as if they were the only ones of the tree: the
+// branches they diverge at are ordered by the same rules as the children of
+// a node, see precedes. Since the branches of a tree depend on all of its
+// patterns, the order of more patterns is given by SortPatterns.
+func ComparePatterns(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	var (
+		lcp   = longestCommonPrefix(a, b)
+		restA = a[lcp:]
+		restB = b[lcp:]
+	)
+
+	switch {
+	// The leaf of a node goes after its children.
+	case restA == "":
+		return 1
+	case restB == "":
+		return -1
+	case branchPrecedes(restA, restB):
+		return -1
+	default:
+		return 1
+	}
+}
+
+// SortPatterns sorts the patterns in the order, that a tree
+// of them tries them, by building the branches of the tree.
+func SortPatterns(patterns []string) {
+	copy(patterns, sortBranch(patterns, 0))
+}
+
+// patternBranch is a child of a node, that the given patterns go to.
+type patternBranch struct {
+	key      string
+	patterns []string
+}
+
+// sortBranch returns the patterns – sharing their first offset bytes – in
+// the order of the branches of the node at the offset, the same way as the
+// tree builds and orders the children of a node. The patterns ending at
+// the node go after all of its branches.
+func sortBranch(patterns []string, offset int) []string {
+	var (
+		ended    = make([]string, 0)
+		branches = make([]*patternBranch, 0)
+	)
+
+	for _, p := range patterns {
+		if len(p) == offset {
+			ended = append(ended, p)
+			continue
+		}
+
+		var (
+			rest   = p[offset:]
+			placed = false
+		)
+
+		for _, b := range branches {
+			if lcp := longestCommonPrefix(b.key, rest); lcp > 0 {
+				b.key = b.key[:lcp]
+				b.patterns = append(b.patterns, p)
+				placed = true
+
+				break
+			}
+		}
+
+		if !placed {
+			branches = append(branches, &patternBranch{key: rest, patterns: []string{p}})
+		}
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branchPrecedes(branches[i].key, branches[j].key)
+	})
+
+	sorted := make([]string, 0, len(patterns))
+
+	for _, b := range branches {
+		sorted = append(sorted, sortBranch(b.patterns, offset+len(b.key))...)
+	}
+
+	return append(sorted, ended...)
+}
+
+// branchPrecedes is the same as precedes, but the static siblings – only
+// one of which could match a key – are ordered by their keys, so the
+// order does not depend on the order of the patterns.
+func branchPrecedes(key1, key2 string) bool {
+	if precedes(key1, key2) {
+		return true
+	}
+
+	return !precedes(key2, key1) && key1 < key2
+}
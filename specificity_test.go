@@ -0,0 +1,163 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpecificity(t *testing.T) {
+	// Every pattern is more specific, than the next one.
+	ordered := []string{
+		"/users/me",
+		"/users/v{version}",
+		"/users/{id:digits}",
+		"/users/{id}",
+		"/users/{path...}",
+		"/{group}/me",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		var (
+			a = ordered[i]
+			b = ordered[i+1]
+		)
+
+		if Specificity(a) < Specificity(b) {
+			t.Errorf("expected %s to be at least as specific as %s; got: %d < %d\n", a, b, Specificity(a), Specificity(b))
+		}
+
+		if ComparePatterns(a, b) >= 0 || ComparePatterns(b, a) <= 0 {
+			t.Errorf("expected %s to precede %s\n", a, b)
+		}
+	}
+
+	if got := ComparePatterns("/users/{id}", "/users/{id}"); got != 0 {
+		t.Errorf("expected 0 for the same pattern; got: %d\n", got)
+	}
+
+	if Specificity(`/legacy/\{id\}`) <= Specificity("/legacy/{id}") {
+		t.Errorf("expected the escaped brackets to be static\n")
+	}
+
+	// The tree decides by the static bytes after the params, not by the
+	// kind of the next segment: /a/1/c/dd/ee is matched by the latter.
+	if ComparePatterns("/a/{y}/{z}/dd/ee", "/a/{x}/c/{q}/ee") >= 0 {
+		t.Errorf("expected /a/{y}/{z}/dd/ee to precede /a/{x}/c/{q}/ee\n")
+	}
+}
+
+func TestSortPatterns(t *testing.T) {
+	patterns := []string{
+		"/files/{path...}",
+		"/files/{name}",
+		"/{any}",
+		"/files/readme",
+		"/files/{name:digits}/raw",
+		"/files/{name}/raw",
+	}
+
+	SortPatterns(patterns)
+
+	expected := []string{
+		"/files/readme",
+		"/files/{name:digits}/raw",
+		"/files/{name}/raw",
+		"/files/{name}",
+		"/files/{path...}",
+		"/{any}",
+	}
+
+	if !reflect.DeepEqual(patterns, expected) {
+		t.Errorf("expected order: %v; got: %v\n", expected, patterns)
+	}
+}
+
+// TestSpecificityMatchesTree checks, that the match of the tree is
+// always the first pattern in the sorted order, that matches the key.
+func TestSpecificityMatchesTree(t *testing.T) {
+	digits := SegmentMatcherFunc(func(segment string) (bool, string) {
+		for _, c := range segment {
+			if c < '0' || c > '9' {
+				return false, ""
+			}
+		}
+
+		return segment != "", segment
+	})
+
+	tt := []struct {
+		name     string
+		patterns []string
+		keys     []string
+	}{
+		{
+			name: "segments of every kind",
+			patterns: []string{
+				"/users/me", "/users/{id:digits}", "/users/{name}", "/users/v{version}",
+				"/users/{id:digits}/posts", "/users/{name}/posts", "/users/me/{tab}",
+				"/{group}/me", "/{group}/{member}", "/files/{path...}", "/files/{name}/raw",
+				"/files/readme", "/{z:digits}", "/{any}",
+			},
+			keys: []string{
+				"/users/me", "/users/1", "/users/bob", "/users/v2", "/users/1/posts", "/users/bob/posts",
+				"/users/me/posts", "/admins/me", "/admins/1", "/files/readme", "/files/a/raw", "/files/a/b/c",
+				"/files/a", "/1", "/x",
+			},
+		},
+		{
+			name:     "static bytes after the params",
+			patterns: []string{"/a/{x}/c/{q}/ee", "/a/{y}/{z}/dd/ee"},
+			keys:     []string{"/a/1/c/dd/ee", "/a/1/c/2/ee", "/a/1/2/dd/ee"},
+		},
+		{
+			// The shorter pattern splits the branch of the first
+			// one, so it has less static bytes, than the second.
+			name:     "branches split by an other pattern",
+			patterns: []string{"/a/{x}/cc/{q}/zzzz", "/a/{y}/{z}/{w}/zzzz", "/a/{x}/c"},
+			keys:     []string{"/a/1/cc/2/zzzz", "/a/1/c", "/a/1/2/3/zzzz"},
+		},
+	}
+
+	newTree := func(patterns ...string) *Tree[*Route] {
+		tree := New(WithSegmentMatcher[*Route]("digits", digits))
+
+		for _, p := range patterns {
+			if err := tree.Insert(p, &Route{}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		return tree
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				tree   = newTree(tc.patterns...)
+				sorted = append([]string(nil), tc.patterns...)
+			)
+
+			SortPatterns(sorted)
+
+			for _, key := range tc.keys {
+				expected := ""
+
+				for _, p := range sorted {
+					if newTree(p).Find(key) != nil {
+						expected = p
+						break
+					}
+				}
+
+				got := ""
+				if fn := tree.Find(key); fn != nil {
+					got = fn.GetPattern()
+				}
+
+				if got != expected {
+					t.Errorf("%s: expected: %s; got: %s\n", key, expected, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package rtree
+
+import "fmt"
+
+var errBadSplitWeights = fmt.Errorf("[rtree %s]: split weights must be non-negative with positive sum", version)
+
+// trafficSplit holds the alternative value of a route and the weights
+// of the values, based on which the lookups choose between them.
+type trafficSplit[T storeValue] struct {
+	canary        T
+	primaryWeight int
+	totalWeight   int
+}
+
+// InsertSplit stores two values under the same key, so lookups return
+// them in the proportion of the given weights, eg. 90 and 10 means that
+// 90% of the lookups resolve to primary and 10% resolve to canary.
+// The selection is random in case of Find, and deterministic
+// in case of FindSticky.
+func (t *Tree[T]) InsertSplit(key string, primary T, primaryWeight int, canary T, canaryWeight int, opts ...RouteOption) error {
+	if primaryWeight < 0 || canaryWeight < 0 || primaryWeight+canaryWeight == 0 {
+		return errBadSplitWeights
+	}
+
+	split := &trafficSplit[T]{
+		canary:        canary,
+		primaryWeight: primaryWeight,
+		totalWeight:   primaryWeight + canaryWeight,
+	}
+
+	return t.insert(key, primary, opts, func(nv *NodeValue[T]) {
+		nv.split = split
+	})
+}
+
+// FindSticky is similar to Find, but in case of a split route the
+// choice between the values is determined by the given hash – eg. the
+// hash of the user id – so the same caller always gets the same value.
+func (t *Tree[T]) FindSticky(key string, hash uint64) *FoundNode[T] {
+	if err := checkTree(t); err != nil {
+		return nil
+	}
+
+	return t.observe(key, func(key string) *FoundNode[T] {
+		n, params := t.findNode(key)
+		if n == nil {
+			return nil
+		}
+
+		fn := t.newFoundNode(n, params)
+
+		fn.value = t.resolve(n.value).pick(func(total int) int {
+			return int(hash % uint64(total))
+		})
+
+		return fn
+	})
+}
+
+// pick returns the stored value, or in case of a split route, the
+// value chosen by the given function, which must return a number
+// in the range of [0, total).
+func (nv *NodeValue[T]) pick(choose func(total int) int) T {
+	if nv.split == nil {
+		return nv.value
+	}
+
+	if choose(nv.split.totalWeight) < nv.split.primaryWeight {
+		return nv.value
+	}
+
+	return nv.split.canary
+}
@@ -0,0 +1,113 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInsertSplit(t *testing.T) {
+	type testCase struct {
+		name            string
+		primaryWeight   int
+		canaryWeight    int
+		err             error
+		expectedPrimary int
+	}
+
+	tt := []testCase{
+		{
+			name:          "error on negative weight",
+			primaryWeight: -1,
+			canaryWeight:  10,
+			err:           errBadSplitWeights,
+		},
+		{
+			name:          "error on zero sum of weights",
+			primaryWeight: 0,
+			canaryWeight:  0,
+			err:           errBadSplitWeights,
+		},
+		{
+			name:            "only primary",
+			primaryWeight:   100,
+			canaryWeight:    0,
+			expectedPrimary: 100,
+		},
+		{
+			name:            "only canary",
+			primaryWeight:   0,
+			canaryWeight:    100,
+			expectedPrimary: 0,
+		},
+		{
+			name:            "sticky hashes distributed by weights",
+			primaryWeight:   90,
+			canaryWeight:    10,
+			expectedPrimary: 90,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				tree    = New[*Route]()
+				primary = &Route{name: "primary"}
+				canary  = &Route{name: "canary"}
+			)
+
+			err := tree.InsertSplit("/api/users/{id}", primary, tc.primaryWeight, canary, tc.canaryWeight)
+
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.err, err)
+			}
+
+			if tc.err != nil {
+				return
+			}
+
+			primaries := 0
+
+			for i := uint64(0); i < 100; i++ {
+				fn := tree.FindSticky("/api/users/1", i)
+
+				if fn == nil {
+					t.Fatal("expected to find, but got <nil>")
+				}
+
+				if fn.GetValue() == primary {
+					primaries++
+				}
+
+				if again := tree.FindSticky("/api/users/1", i); again.GetValue() != fn.GetValue() {
+					t.Errorf("expected sticky value for hash: %d\n", i)
+				}
+			}
+
+			if primaries != tc.expectedPrimary {
+				t.Errorf("expected primary count: %d; got: %d\n", tc.expectedPrimary, primaries)
+			}
+		})
+	}
+}
+
+func TestFindSplitRandom(t *testing.T) {
+	var (
+		tree    = New[*Route]()
+		primary = &Route{name: "primary"}
+		canary  = &Route{name: "canary"}
+	)
+
+	if err := tree.InsertSplit("/api/users/{id}", primary, 50, canary, 50); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	seen := make(map[*Route]bool)
+
+	for i := 0; i < 1000; i++ {
+		seen[tree.Find("/api/users/1").GetValue()] = true
+	}
+
+	if !seen[primary] || !seen[canary] {
+		t.Errorf("expected both values to be returned; got: %v\n", seen)
+	}
+}
@@ -0,0 +1,65 @@
+package rtree
+
+import "time"
+
+// Stats is the telemetry of the tree. The insertion counters make it
+// visible, whether the distribution of the keys causes pathological
+// splitting during bulk loads.
+type Stats struct {
+	// Routes is the number of the stored routes.
+	Routes int
+	// Generation is the current generation of the tree.
+	Generation uint64
+	// Splits is the number of nodes split by the insertions.
+	Splits uint64
+	// ChildAppends is the number of nodes appended as a new child.
+	ChildAppends uint64
+	// DepthGrowths is the number of insertions, which stored
+	// a leaf deeper than any of the previous ones.
+	DepthGrowths uint64
+	// MaxDepth is the deepest level – the root being 1 – that
+	// a leaf was stored at, at the time of its insertion.
+	MaxDepth int
+	// LastSyncedAt is the time of the last successful refresh from
+	// a dynamic source – see Refresh and SyncClient –, or zero if the
+	// tree was never synced. The tree keeps serving its last good
+	// table after the failed refreshes, so its age tells the staleness.
+	LastSyncedAt time.Time
+}
+
+type insertStats struct {
+	splits       uint64
+	appends      uint64
+	depthGrowths uint64
+	maxDepth     int
+}
+
+// leafAt records that a leaf was stored at the given depth.
+func (st *insertStats) leafAt(depth int) {
+	if depth <= st.maxDepth {
+		return
+	}
+
+	st.maxDepth = depth
+	st.depthGrowths++
+}
+
+// Stats returns the current telemetry of the tree.
+func (t *Tree[T]) Stats() Stats {
+	if t == nil {
+		return Stats{}
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return Stats{
+		Routes:       len(t.routes),
+		Generation:   t.generation.Load(),
+		Splits:       t.stats.splits,
+		ChildAppends: t.stats.appends,
+		DepthGrowths: t.stats.depthGrowths,
+		MaxDepth:     t.stats.maxDepth,
+		LastSyncedAt: t.syncedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	type testCase struct {
+		name     string
+		keys     []string
+		expected Stats
+	}
+
+	tt := []testCase{
+		{
+			name:     "empty tree",
+			keys:     nil,
+			expected: Stats{},
+		},
+		{
+			name: "single root",
+			keys: []string{"/api/users"},
+			expected: Stats{
+				Routes:       1,
+				Generation:   1,
+				DepthGrowths: 1,
+				MaxDepth:     1,
+			},
+		},
+		{
+			name: "splits, appends and depth growth",
+			keys: []string{
+				"/api/users",
+				"/api/products",
+				"/api/products/{id}",
+				"/api",
+			},
+			expected: Stats{
+				Routes:       4,
+				Generation:   4,
+				Splits:       2,
+				ChildAppends: 2,
+				DepthGrowths: 3,
+				MaxDepth:     3,
+			},
+		},
+		{
+			name: "duplicate keys do not count",
+			keys: []string{
+				"/api/users",
+				"/api/users",
+				"/api/orders",
+			},
+			expected: Stats{
+				Routes:       2,
+				Generation:   2,
+				Splits:       1,
+				ChildAppends: 1,
+				DepthGrowths: 2,
+				MaxDepth:     2,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			for _, k := range tc.keys {
+				// Duplicates are expected to fail.
+				_ = tree.Insert(k, getRoute())
+			}
+
+			if got := tree.Stats(); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected stats: %+v; got: %+v\n", tc.expected, got)
+			}
+		})
+	}
+}
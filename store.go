@@ -0,0 +1,248 @@
+package rtree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const fsStoreExt = ".route"
+
+var (
+	errNotPersisted = fmt.Errorf("[rtree %s]: change is not persisted", version)
+	errBadRouteFile = fmt.Errorf("[rtree %s]: route file has no key", version)
+)
+
+// Store is a persistent backend of the route table. Every blob
+// is saved under the pattern of its route.
+type Store interface {
+	Save(key string, blob []byte) error
+	Delete(key string) error
+	LoadAll() (map[string][]byte, error)
+}
+
+type persistence[T storeValue] struct {
+	store Store
+	codec Codec[T]
+}
+
+// WithStore wires the tree to the given store: every successful Insert,
+// Upsert, Alias and Delete is persisted, so the route table could be
+// rebuilt by Load after a restart. Just like in case of the snapshots,
+// only the patterns and the values are persisted – an alias is persisted
+// with the value it resolves to –, the route metadata are not.
+//
+// If persisting fails, the in-memory change is kept, and the error of
+// the mutation wraps the error of the store.
+func WithStore[T storeValue](store Store, codec Codec[T]) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.persistence = &persistence[T]{
+			store: store,
+			codec: codec,
+		}
+	}
+}
+
+// Load stores all the routes of the store of the tree. The loaded
+// routes are not saved again. It goes on after the routes, that could
+// not be loaded, and returns their MultiError – each failure prefixed
+// by its key –, in the order of the keys.
+func (t *Tree[T]) Load() error {
+	if t == nil {
+		return ErrTreeIsNil
+	}
+
+	if t.persistence == nil {
+		return nil
+	}
+
+	blobs, err := t.persistence.store.LoadAll()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(blobs))
+
+	for key := range blobs {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	errs := make([]error, 0)
+
+	for _, key := range keys {
+		if err := t.loadBlob(key, blobs[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	return newMultiError(errs)
+}
+
+// loadBlob stores the route of the given key, decoded from the blob.
+func (t *Tree[T]) loadBlob(key string, blob []byte) error {
+	value, err := t.persistence.codec.Unmarshal(blob)
+	if err != nil {
+		return err
+	}
+
+	nv, err := t.newStoredValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.store(nv)
+}
+
+// persist saves the given value to the store of the tree, if any.
+// The caller must hold the write lock.
+func (t *Tree[T]) persist(nv *NodeValue[T]) error {
+	if t.persistence == nil {
+		return nil
+	}
+
+	// Only the value of the leaf is saved under the pattern, see WithGuard.
+	if n := findExactRec(t.root, nv.pattern); n != nil && n.value != nv {
+		return nil
+	}
+
+	blob, err := t.persistence.codec.Marshal(t.resolve(nv).value)
+	if err == nil {
+		err = t.persistence.store.Save(nv.pattern, blob)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", errNotPersisted, nv.pattern, err)
+	}
+
+	return nil
+}
+
+// unpersist deletes the given pattern from the store of the tree, if any.
+// The caller must hold the write lock.
+func (t *Tree[T]) unpersist(pattern string) error {
+	if t.persistence == nil {
+		return nil
+	}
+
+	if err := t.persistence.store.Delete(pattern); err != nil {
+		return fmt.Errorf("%w: %s: %v", errNotPersisted, pattern, err)
+	}
+
+	return nil
+}
+
+// FSStore is a Store, which keeps every blob in a file of its own, in
+// the given directory. The files are named by the SHA-256 hash of their
+// keys – so the long keys fit in a filename as well –, while the hex
+// encoded key itself is the first line of the file, followed by the blob.
+type FSStore struct {
+	dir string
+}
+
+// NewFSStore creates a filesystem store in the given directory,
+// creating the directory if it does not exist.
+func NewFSStore(dir string) (*FSStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FSStore{dir: dir}, nil
+}
+
+// Save writes the blob atomically: it is written to a temporary file
+// first, which then replaces the previous file of the key, if any.
+func (s *FSStore) Save(key string, blob []byte) error {
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+
+	// It is a no-op after the successful rename.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(hex.EncodeToString([]byte(key)) + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(blob); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Delete removes the file of the key. Deleting a missing key is not an error.
+func (s *FSStore) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// LoadAll reads all the stored blobs.
+func (s *FSStore) LoadAll() (map[string][]byte, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := make(map[string][]byte, len(entries))
+
+	for _, e := range entries {
+		name := e.Name()
+
+		if e.IsDir() || !strings.HasSuffix(name, fsStoreExt) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		line, blob, found := bytes.Cut(content, []byte{'\n'})
+		if !found {
+			return nil, fmt.Errorf("%w: %s", errBadRouteFile, name)
+		}
+
+		key, err := hex.DecodeString(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		blobs[string(key)] = blob
+	}
+
+	return blobs, nil
+}
+
+// path returns the path of the file of the key.
+func (s *FSStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+fsStoreExt)
+}
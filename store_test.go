@@ -0,0 +1,157 @@
+package rtree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var errStoreIsDown = errors.New("store is down")
+
+type failingStore struct{}
+
+func (failingStore) Save(string, []byte) error           { return errStoreIsDown }
+func (failingStore) Delete(string) error                 { return errStoreIsDown }
+func (failingStore) LoadAll() (map[string][]byte, error) { return nil, errStoreIsDown }
+
+func TestFSStore(t *testing.T) {
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// The key is too long to be a filename, even hex encoded.
+	long := strings.Repeat("/segment", 40) + "/{id}"
+
+	for k, v := range map[string]string{
+		"/api/users/{id}":  "users",
+		"/files/{path...}": "files",
+		"/api/products":    "products",
+		long:               "long",
+	} {
+		if err := store.Save(k, []byte(v)); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := store.Save("/api/products", []byte("products-v2")); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := store.Delete("/api/users/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := store.Delete("/unknown"); err != nil {
+		t.Errorf("not expected error on missing key, but got: %v\n", err)
+	}
+
+	got, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	expected := map[string][]byte{
+		"/files/{path...}": []byte("files"),
+		"/api/products":    []byte("products-v2"),
+		long:               []byte("long"),
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected blobs: %v; got: %v\n", expected, got)
+	}
+}
+
+func TestFSStoreBadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFSStore(dir)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad"+fsStoreExt), []byte("blob"), 0o644); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if _, err := store.LoadAll(); !errors.Is(err, errBadRouteFile) {
+		t.Errorf("expected error: %v; got: %v\n", errBadRouteFile, err)
+	}
+}
+
+func TestTreeWithStore(t *testing.T) {
+	var codec Codec[string] = JSONCodec[string]{}
+
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tree := New(WithStore[string](store, codec))
+
+	for k, v := range map[string]string{
+		"/api/users/{id}": "users",
+		"/api/products":   "products",
+		"/api/orders":     "orders",
+	} {
+		if err := tree.Insert(k, v); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := tree.Upsert("/api/products", "products-v2"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Delete("/api/orders"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Alias("/api/users/{id}", "/api/people/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// The alias is persisted with the value of the upserted route.
+	if err := tree.Upsert("/api/users/{id}", "users-v2"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	restarted := New(WithStore[string](store, codec))
+
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	expected, _ := tree.Snapshot(codec)
+	got, _ := restarted.Snapshot(codec)
+
+	if !reflect.DeepEqual(expected.Entries, got.Entries) {
+		t.Errorf("expected entries: %v; got: %v\n", expected.Entries, got.Entries)
+	}
+}
+
+func TestTreeWithFailingStore(t *testing.T) {
+	var codec Codec[string] = JSONCodec[string]{}
+
+	tree := New(WithStore[string](failingStore{}, codec))
+
+	if err := tree.Insert("/api/users", "users"); !errors.Is(err, errNotPersisted) {
+		t.Errorf("expected error: %v; got: %v\n", errNotPersisted, err)
+	}
+
+	// The in-memory change is kept.
+	if tree.Find("/api/users") == nil {
+		t.Error("expected to find, but got <nil>")
+	}
+
+	if err := tree.Delete("/api/users"); !errors.Is(err, errNotPersisted) {
+		t.Errorf("expected error: %v; got: %v\n", errNotPersisted, err)
+	}
+
+	if err := tree.Load(); !errors.Is(err, errStoreIsDown) {
+		t.Errorf("expected error: %v; got: %v\n", errStoreIsDown, err)
+	}
+}
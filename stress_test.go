@@ -0,0 +1,197 @@
+package rtree
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+)
+
+var stressBudget = flag.Int("stress.budget", 64, "the backtrack budget of the lookups in the stress tests")
+
+// deepStaticRoutes returns a route of the given depth, with
+// width static siblings of it on each level.
+func deepStaticRoutes(depth, width int) []string {
+	var (
+		routes = make([]string, 0, depth*width+1)
+		prefix = ""
+	)
+
+	for d := 0; d < depth; d++ {
+		for w := 0; w < width; w++ {
+			routes = append(routes, fmt.Sprintf("%s/x%d-%d", prefix, d, w))
+		}
+
+		prefix += fmt.Sprintf("/s%d", d)
+	}
+
+	return append(routes, prefix)
+}
+
+// wildcardFanOutRoutes returns all the routes of the given depth, whose
+// segments are params with one of the width names, ending with /end.
+// A missing lookup has to backtrack through all of them.
+func wildcardFanOutRoutes(depth, width int) []string {
+	routes := []string{""}
+
+	for d := 0; d < depth; d++ {
+		next := make([]string, 0, len(routes)*width)
+
+		for _, r := range routes {
+			for w := 0; w < width; w++ {
+				next = append(next, fmt.Sprintf("%s/{p%d_%d}", r, d, w))
+			}
+		}
+
+		routes = next
+	}
+
+	for i := range routes {
+		routes[i] += "/end"
+	}
+
+	return routes
+}
+
+func buildStressTree(t *testing.T, routes []string, opts ...OptionFunc[*Route]) *Tree[*Route] {
+	t.Helper()
+
+	tree := New(opts...)
+
+	for _, r := range routes {
+		if err := tree.Insert(r, &Route{name: r}); err != nil {
+			t.Fatalf("not expected error on %s, but got: %v\n", r, err)
+		}
+	}
+
+	return tree
+}
+
+func countNodes[T storeValue](t *Tree[T]) int {
+	nodes := 0
+
+	_ = t.Walk(func(n *Node[T]) WalkVerdict {
+		nodes++
+		return Continue
+	})
+
+	return nodes
+}
+
+func TestStressLookupVisits(t *testing.T) {
+	deepRoutes := deepStaticRoutes(64, 8)
+
+	type testCase struct {
+		name     string
+		routes   []string
+		key      string
+		matched  bool
+		maxVisit func(tree *Tree[*Route]) int
+	}
+
+	tt := []testCase{
+		{
+			name:    "deep static nesting is linear in the depth and the width",
+			routes:  deepRoutes,
+			key:     deepRoutes[len(deepRoutes)-1],
+			matched: true,
+			maxVisit: func(*Tree[*Route]) int {
+				// Every non-matching sibling costs one visit on each level.
+				return 64*(8+1) + 1
+			},
+		},
+		{
+			name:    "wildcard fan-out match is found on the first branch",
+			routes:  wildcardFanOutRoutes(8, 2),
+			key:     "/1/2/3/4/5/6/7/8/end",
+			matched: true,
+			maxVisit: func(*Tree[*Route]) int {
+				return 2*8 + 2
+			},
+		},
+		{
+			name:    "wildcard fan-out miss visits every node at most once",
+			routes:  wildcardFanOutRoutes(8, 2),
+			key:     "/1/2/3/4/5/6/7/8/miss",
+			matched: false,
+			maxVisit: func(tree *Tree[*Route]) int {
+				return countNodes(tree)
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := buildStressTree(t, tc.routes)
+
+			exp := tree.Explain(tc.key)
+
+			if exp.Matched != tc.matched {
+				t.Fatalf("expected matched: %v; got: %v\n", tc.matched, exp.Matched)
+			}
+
+			if max, got := tc.maxVisit(tree), len(exp.Steps); got > max {
+				t.Errorf("expected at most %d visits; got: %d\n", max, got)
+			}
+		})
+	}
+}
+
+func TestStressBacktrackBudget(t *testing.T) {
+	var (
+		budget = *stressBudget
+		routes = wildcardFanOutRoutes(10, 2)
+		tree   = buildStressTree(t, routes, WithBacktrackBudget[*Route](budget))
+		miss   = "/1/2/3/4/5/6/7/8/9/10/miss"
+	)
+
+	if nodes := countNodes(tree); nodes <= budget {
+		t.Skipf("the tree of %d nodes fits in the budget of %d\n", nodes, budget)
+	}
+
+	fn, err := tree.TryFind(miss)
+
+	if fn != nil {
+		t.Errorf("expected not to find, but got: %s\n", fn.GetPattern())
+	}
+
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected error: %v; got: %v\n", ErrBudgetExceeded, err)
+	}
+
+	exp := tree.Explain(miss)
+
+	if !exp.BudgetExceeded {
+		t.Error("expected the explanation to report the exceeded budget")
+	}
+
+	if got := len(exp.Steps); got > budget+1 {
+		t.Errorf("expected at most %d visits; got: %d\n", budget+1, got)
+	}
+
+	if last := exp.Steps[len(exp.Steps)-1].Outcome; last != OutcomeBudgetExceeded {
+		t.Errorf("expected last outcome: %s; got: %s\n", OutcomeBudgetExceeded, last)
+	}
+
+	// A match within the budget – found on the first branch – is not affected.
+	if budget < 2*10+2 {
+		return
+	}
+
+	fn, err = tree.TryFind("/1/2/3/4/5/6/7/8/9/10/end")
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if fn == nil {
+		t.Fatal("expected to find, but got <nil>")
+	}
+}
+
+func TestTryFindMiss(t *testing.T) {
+	tree := buildStressTree(t, []string{"/api/users"}, WithBacktrackBudget[*Route](1))
+
+	if fn, err := tree.TryFind("/api/products"); fn != nil || err != nil {
+		t.Errorf("expected a miss without error; got: %v, %v\n", fn, err)
+	}
+}
@@ -0,0 +1,148 @@
+package rtree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultSuggestedMatcher is the matcher suggested for
+// disambiguation, if the tree has no matcher registered.
+const defaultSuggestedMatcher = "int"
+
+// SuggestionKind is the kind of a suggested fix of a shadowed route.
+type SuggestionKind string
+
+const (
+	// SuggestRemoveRoute suggests the removal of the route, which
+	// can never be matched, or duplicates the one shadowing it.
+	SuggestRemoveRoute SuggestionKind = "remove-route"
+	// SuggestAddConstraint suggests a matcher on a param of the
+	// shadowing route, so the rest of the keys fall through.
+	SuggestAddConstraint SuggestionKind = "add-constraint"
+	// SuggestRenameSegment suggests replacing a param of the
+	// shadowed route with a static segment.
+	SuggestRenameSegment SuggestionKind = "rename-segment"
+	// SuggestResolutionOrder suggests the StaticFirst resolution
+	// order, so the static routes win over the catch-all ones.
+	SuggestResolutionOrder SuggestionKind = "resolution-order"
+)
+
+// Suggestion is a machine-readable fix of a shadowed route, eg.
+// for lint tools. Pattern is the route to change and Replacement
+// is its suggested pattern, if the fix is a new pattern.
+type Suggestion struct {
+	Kind        SuggestionKind `json:"kind"`
+	Pattern     string         `json:"pattern"`
+	Replacement string         `json:"replacement,omitempty"`
+	Message     string         `json:"message"`
+}
+
+// suggestFixes returns the suggested fixes of the shadowed pattern.
+// The winner is empty, if the pattern could not be matched at all.
+func (t *Tree[T]) suggestFixes(shadowed, winner string) []Suggestion {
+	var (
+		suggestions = make([]Suggestion, 0)
+
+		// The suggestions are reported with the delimiters of the tree.
+		ext   = t.externalPattern
+		eShad = ext(shadowed)
+		eWin  = ext(winner)
+	)
+
+	if winner == "" {
+		return append(suggestions, Suggestion{
+			Kind:    SuggestRemoveRoute,
+			Pattern: eShad,
+			Message: fmt.Sprintf("%s can never be matched, remove it", eShad),
+		})
+	}
+
+	if t.resolution == CatchAllFirst && strings.HasSuffix(winner, catchAllSuffix+string(curlyEnd)) {
+		suggestions = append(suggestions, Suggestion{
+			Kind:    SuggestResolutionOrder,
+			Pattern: eShad,
+			Message: fmt.Sprintf("resolve with StaticFirst, so %s wins over %s", eShad, eWin),
+		})
+	}
+
+	// Patterns covering each other differ only in their param names.
+	if patternCovers(shadowed, winner) {
+		suggestions = append(suggestions, Suggestion{
+			Kind:    SuggestRemoveRoute,
+			Pattern: eShad,
+			Message: fmt.Sprintf("%s duplicates %s, remove it", eShad, eWin),
+		})
+	}
+
+	var (
+		sSegments = strings.Split(shadowed, string(slash))
+		wSegments = strings.Split(winner, string(slash))
+	)
+
+	// The last param of the winner gets the constraint, since
+	// the ambiguous params are most often the IDs.
+	for i := len(wSegments) - 1; i >= 0; i-- {
+		ws := wSegments[i]
+
+		if !isParamSegment(ws) || isCatchAll(ws) || paramMatcher(ws) != "" {
+			continue
+		}
+
+		name, _, _ := parseParam(ws[1 : len(ws)-1])
+		matcher := t.suggestedMatcher()
+
+		wSegments[i] = string(curlyStart) + name + string(matcherSeparator) + matcher + string(curlyEnd)
+
+		suggestions = append(suggestions, Suggestion{
+			Kind:        SuggestAddConstraint,
+			Pattern:     eWin,
+			Replacement: ext(strings.Join(wSegments, string(slash))),
+			Message:     fmt.Sprintf("add constraint %s to %s to disambiguate", ext(wSegments[i]), eWin),
+		})
+
+		break
+	}
+
+	// The first param of the shadowed route, that overlaps with the
+	// winner, is turned into a static segment of the same name.
+	for i, ss := range sSegments {
+		if !isParamSegment(ss) || isCatchAll(ss) || i >= len(wSegments) {
+			continue
+		}
+
+		name, _, _ := parseParam(ss[1 : len(ss)-1])
+
+		sSegments[i] = name
+
+		suggestions = append(suggestions, Suggestion{
+			Kind:        SuggestRenameSegment,
+			Pattern:     eShad,
+			Replacement: ext(strings.Join(sSegments, string(slash))),
+			Message:     fmt.Sprintf("rename the segment %s of %s to a static one", ext(ss), eShad),
+		})
+
+		break
+	}
+
+	return suggestions
+}
+
+// suggestedMatcher returns the name of the matcher suggested for
+// disambiguation: „int” if it is registered – or there are no
+// matchers at all –, otherwise the first one by name.
+func (t *Tree[T]) suggestedMatcher() string {
+	if _, exists := t.matchers[defaultSuggestedMatcher]; exists || len(t.matchers) == 0 {
+		return defaultSuggestedMatcher
+	}
+
+	names := make([]string, 0, len(t.matchers))
+
+	for name := range t.matchers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names[0]
+}
@@ -0,0 +1,101 @@
+package rtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestFixes(t *testing.T) {
+	type testCase struct {
+		name     string
+		opts     []OptionFunc[*Route]
+		shadowed string
+		winner   string
+		expected []SuggestionKind
+	}
+
+	tt := []testCase{
+		{
+			name:     "unreachable route",
+			shadowed: "/api/users",
+			winner:   "",
+			expected: []SuggestionKind{SuggestRemoveRoute},
+		},
+		{
+			name:     "static route behind a catch-all",
+			opts:     []OptionFunc[*Route]{WithResolutionOrder[*Route](CatchAllFirst)},
+			shadowed: "/files/config.json",
+			winner:   "/files/{path...}",
+			expected: []SuggestionKind{SuggestResolutionOrder},
+		},
+		{
+			name:     "param route behind a more general one",
+			shadowed: "/api/{resource}/get",
+			winner:   "/api/{name}/{action}",
+			expected: []SuggestionKind{SuggestAddConstraint, SuggestRenameSegment},
+		},
+		{
+			name:     "param route behind a constrained one",
+			shadowed: "/api/{id:int}",
+			winner:   "/api/{key:int}",
+			expected: []SuggestionKind{SuggestRemoveRoute, SuggestRenameSegment},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			suggestions := New(tc.opts...).suggestFixes(tc.shadowed, tc.winner)
+
+			kinds := make([]SuggestionKind, 0, len(suggestions))
+
+			for _, s := range suggestions {
+				kinds = append(kinds, s.Kind)
+			}
+
+			if !reflect.DeepEqual(kinds, tc.expected) {
+				t.Errorf("expected suggestions: %v; got: %v\n", tc.expected, suggestions)
+			}
+		})
+	}
+}
+
+func TestSuggestedMatcher(t *testing.T) {
+	type testCase struct {
+		name     string
+		opts     []OptionFunc[*Route]
+		expected string
+	}
+
+	tt := []testCase{
+		{
+			name:     "no matchers",
+			expected: "int",
+		},
+		{
+			name:     "int is registered",
+			opts:     []OptionFunc[*Route]{WithSegmentMatcher[*Route]("uuid", UUID), WithSegmentMatcher[*Route]("int", Int)},
+			expected: "int",
+		},
+		{
+			name:     "first registered by name",
+			opts:     []OptionFunc[*Route]{WithSegmentMatcher[*Route]("uuid", UUID), WithSegmentMatcher[*Route]("num", Int)},
+			expected: "num",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			if got := tree.suggestedMatcher(); got != tc.expected {
+				t.Errorf("expected: %s; got: %s\n", tc.expected, got)
+			}
+
+			suggestions := tree.suggestFixes("/users/{name}", "/users/{id}")
+
+			if suggestions[1].Replacement != "/users/{id:"+tc.expected+"}" {
+				t.Errorf("expected constraint %s; got: %v\n", tc.expected, suggestions[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package rtree
+
+import (
+	"sort"
+	"time"
+)
+
+// WithSunset marks the route as deprecated, to be removed at the given
+// time by PruneExpired. The aliases of the route share its sunset.
+func WithSunset(at time.Time) RouteOption {
+	return func(rm *routeMeta) {
+		rm.sunset = at
+	}
+}
+
+// Sunset returns the sunset of the route, or the zero
+// time if the route is not deprecated.
+func (nv *NodeValue[T]) Sunset() time.Time {
+	return nv.meta.sunset
+}
+
+// Sunset returns the sunset of the matched route, or
+// the zero time if the route is not deprecated.
+func (fn *FoundNode[T]) Sunset() time.Time {
+	if fn.meta == nil {
+		return time.Time{}
+	}
+
+	return fn.meta.sunset
+}
+
+// expired reports whether the route is past its sunset at the given time.
+func (rm *routeMeta) expired(now time.Time) bool {
+	return !rm.sunset.IsZero() && !rm.sunset.After(now)
+}
+
+// PruneExpired removes the routes past their sunset at the given time,
+// alongside with their aliases, and returns the sorted patterns of the
+// removed routes. The watchers get a ChangeExpire event of every removed
+// route. The protected routes – and the ones with protected aliases –
+// are kept, since only ForceDelete could remove them. The guarded values
+// of a pattern – see WithGuard – expire one by one, the others of the
+// pattern are kept. The error is only returned, if the removals could
+// not be persisted.
+func (t *Tree[T]) PruneExpired(now time.Time) ([]string, error) {
+	if err := checkTree(t); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	expired := make([]*NodeValue[T], 0)
+
+	for _, nv := range t.routes {
+		if nv.aliasOf == 0 && nv.meta.expired(now) && !t.hasProtected(nv) {
+			expired = append(expired, nv)
+		}
+	}
+
+	// The removals are done in a stable order, so are their events.
+	sort.Slice(expired, func(i, j int) bool {
+		if expired[i].pattern != expired[j].pattern {
+			return expired[i].pattern < expired[j].pattern
+		}
+
+		return expired[i].id < expired[j].id
+	})
+
+	var (
+		// The patterns are reported in their external form, while
+		// the store of the tree knows them in their stored form.
+		removed = make([]string, 0, len(expired))
+		deleted = make([]string, 0, len(expired))
+		// The leaves with an other value in the place of the removed one.
+		replaced = make(map[string]struct{})
+	)
+
+	for _, nv := range expired {
+		// The aliases are removed first, so they still
+		// resolve to the value of the route.
+		for _, a := range t.aliasesOf(nv.id) {
+			if path := findExactPath(t.root, a.pattern); path != nil {
+				t.remove(path, ChangeExpire)
+				removed = append(removed, a.Pattern())
+				deleted = append(deleted, a.pattern)
+			}
+		}
+
+		path := findExactPath(t.root, nv.pattern)
+		if path == nil {
+			continue
+		}
+
+		primary := path[len(path)-1].value == nv
+
+		if t.removeValue(path, nv, ChangeExpire) {
+			deleted = append(deleted, nv.pattern)
+			delete(replaced, nv.pattern)
+		} else if primary {
+			replaced[nv.pattern] = struct{}{}
+		}
+
+		removed = append(removed, nv.Pattern())
+	}
+
+	sort.Strings(removed)
+	sort.Strings(deleted)
+
+	var errs []error
+
+	for _, pattern := range deleted {
+		if err := t.unpersist(pattern); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for pattern := range replaced {
+		if n := findExactRec(t.root, pattern); n != nil {
+			if err := t.persist(n.value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return removed, newMultiError(errs)
+}
+
+// hasProtected reports whether the route or any of its aliases is protected.
+// The caller must hold the lock.
+func (t *Tree[T]) hasProtected(nv *NodeValue[T]) bool {
+	if nv.meta.protected {
+		return true
+	}
+
+	for _, a := range t.aliasesOf(nv.id) {
+		if a.meta.protected {
+			return true
+		}
+	}
+
+	return false
+}
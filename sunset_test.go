@@ -0,0 +1,226 @@
+package rtree
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPruneExpired(t *testing.T) {
+	var (
+		tree = New[string]()
+		now  = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	routes := []struct {
+		key       string
+		sunset    time.Time
+		protected bool
+	}{
+		{key: "/v1/users/{id}", sunset: now.Add(-time.Hour)},
+		{key: "/v1/orders", sunset: now},
+		{key: "/v1/health", sunset: now.Add(-time.Hour), protected: true},
+		{key: "/v2/users/{id}", sunset: now.Add(time.Hour)},
+		{key: "/v2/orders"},
+	}
+
+	for _, r := range routes {
+		insert := tree.Insert
+		if r.protected {
+			insert = tree.InsertProtected
+		}
+
+		if err := insert(r.key, r.key, WithSunset(r.sunset)); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	if err := tree.Alias("/v1/users/{id}", "/legacy/users/{id}"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := tree.Find("/legacy/users/1").Sunset(); !got.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("expected the sunset of the aliased route; got: %v\n", got)
+	}
+
+	events, _ := tree.Watch(ctx)
+
+	removed, err := tree.PruneExpired(now)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	expected := []string{"/legacy/users/{id}", "/v1/orders", "/v1/users/{id}"}
+
+	if !reflect.DeepEqual(removed, expected) {
+		t.Fatalf("expected removed: %v; got: %v\n", expected, removed)
+	}
+
+	for _, key := range []string{"/v1/health", "/v2/users/1", "/v2/orders"} {
+		if tree.Find(key) == nil {
+			t.Errorf("expected %s to be kept\n", key)
+		}
+	}
+
+	for _, key := range []string{"/v1/users/1", "/legacy/users/1", "/v1/orders"} {
+		if fn := tree.Find(key); fn != nil {
+			t.Errorf("expected %s to be removed; got: %s\n", key, fn.GetPattern())
+		}
+	}
+
+	// The routes are removed in the order of their patterns,
+	// and the aliases before the routes they refer to.
+	expectedEvents := []watchedEvent{
+		{kind: ChangeExpire, key: "/v1/orders", value: "/v1/orders"},
+		{kind: ChangeExpire, key: "/legacy/users/{id}", value: "/v1/users/{id}"},
+		{kind: ChangeExpire, key: "/v1/users/{id}", value: "/v1/users/{id}"},
+	}
+
+	for i, exp := range expectedEvents {
+		ev := <-events
+
+		if got := (watchedEvent{kind: ev.Kind, key: ev.Key, value: ev.Value}); got != exp {
+			t.Errorf("expected event #%d: %+v; got: %+v\n", i, exp, got)
+		}
+	}
+
+	// Nothing is left to prune.
+	if removed, _ := tree.PruneExpired(now); len(removed) != 0 {
+		t.Fatalf("expected nothing to be removed; got: %v\n", removed)
+	}
+}
+
+func TestPruneExpiredGuarded(t *testing.T) {
+	var codec Codec[string] = JSONCodec[string]{}
+
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var (
+		tree = New(WithStore[string](store, codec))
+		now  = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	beta, err := ParseGuard(`cookie.beta == "on"`)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	v2, err := ParseGuard("header.x-api-version >= 2")
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	inserts := []struct {
+		key   string
+		value string
+		opts  []RouteOption
+	}{
+		{"/users/{id}", "plain", nil},
+		{"/users/{id}", "beta", []RouteOption{WithGuard(beta), WithSunset(now)}},
+		{"/orders", "v2", []RouteOption{WithGuard(v2), WithSunset(now)}},
+		{"/orders", "beta", []RouteOption{WithGuard(beta)}},
+	}
+
+	for _, ins := range inserts {
+		if err := tree.Insert(ins.key, ins.value, ins.opts...); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	removed, err := tree.PruneExpired(now)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if expected := []string{"/orders", "/users/{id}"}; !reflect.DeepEqual(removed, expected) {
+		t.Fatalf("expected removed: %v; got: %v\n", expected, removed)
+	}
+
+	tt := []struct {
+		key           string
+		attrs         map[string]string
+		expectedValue string
+	}{
+		{key: "/users/1", expectedValue: "plain"},
+		{key: "/users/1", attrs: map[string]string{"cookie.beta": "on"}, expectedValue: "plain"},
+		{key: "/orders", attrs: map[string]string{"cookie.beta": "on"}, expectedValue: "beta"},
+		{key: "/orders", attrs: map[string]string{"header.x-api-version": "2"}},
+	}
+
+	for _, tc := range tt {
+		fn := tree.FindAttrs(tc.key, tc.attrs)
+
+		if tc.expectedValue == "" {
+			if fn != nil {
+				t.Errorf("expected no match of %s %v; got: %s\n", tc.key, tc.attrs, fn.GetValue())
+			}
+			continue
+		}
+
+		if fn == nil || fn.GetValue() != tc.expectedValue {
+			t.Errorf("expected value of %s %v: %s; got: %v\n", tc.key, tc.attrs, tc.expectedValue, fn)
+		}
+	}
+
+	// The value, which took the place of the expired one, is persisted.
+	blobs, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	for key, expected := range map[string]string{"/users/{id}": "plain", "/orders": "beta"} {
+		if got, _ := codec.Unmarshal(blobs[key]); got != expected {
+			t.Errorf("expected persisted value of %s: %s; got: %s\n", key, expected, got)
+		}
+	}
+}
+
+func TestPruneExpiredUnpersist(t *testing.T) {
+	var codec Codec[string] = JSONCodec[string]{}
+
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	var (
+		tree = New(
+			WithStore[string](store, codec),
+			WithDelimiters[string](Delimiters{Segment: '.', ParamStart: '<', ParamEnd: '>'}),
+		)
+		now = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	if err := tree.Insert("orders.<region>.created", "created", WithSunset(now)); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Alias("orders.<region>.created", "legacy.<region>"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	removed, err := tree.PruneExpired(now)
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if expected := []string{"legacy.<region>", "orders.<region>.created"}; !reflect.DeepEqual(removed, expected) {
+		t.Fatalf("expected removed: %v; got: %v\n", expected, removed)
+	}
+
+	blobs, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if len(blobs) != 0 {
+		t.Errorf("expected the removed routes to be unpersisted; got: %v\n", blobs)
+	}
+}
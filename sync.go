@@ -0,0 +1,247 @@
+package rtree
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	syncHistorySize = 16
+	syncSinceParam  = "since"
+	syncEpochParam  = "epoch"
+)
+
+var (
+	errSyncStatus = fmt.Errorf("[rtree %s]: unexpected status of sync response", version)
+	errSyncEpoch  = fmt.Errorf("[rtree %s]: changes of an other leader", version)
+)
+
+// SyncResponse is the payload served by the leader. It either holds the
+// full snapshot of the route table, or only the changes since the
+// generation the follower has already seen. The epoch identifies the
+// leader, since its generations are only meaningful in the same process.
+type SyncResponse struct {
+	Epoch      string    `json:"epoch"`
+	Generation uint64    `json:"generation"`
+	Full       bool      `json:"full"`
+	Snapshot   *Snapshot `json:"snapshot,omitempty"`
+	Changes    *Changes  `json:"changes,omitempty"`
+}
+
+type syncHandler[T storeValue] struct {
+	mu      sync.Mutex
+	tree    *Tree[T]
+	codec   Codec[T]
+	epoch   string
+	history []Snapshot
+}
+
+// SyncHandler returns the leader side of the sync protocol. A follower
+// gets the full route table on its first request, then only the
+// changes since the generation given in the „since” query param – as
+// long as that generation is still in the history of the leader, and
+// the „epoch” query param is the one of the leader. Every handler has
+// an epoch of its own, so a restarted leader sends the full table again.
+func SyncHandler[T storeValue](t *Tree[T], codec Codec[T]) http.Handler {
+	return &syncHandler[T]{
+		tree:    t,
+		codec:   codec,
+		epoch:   newSyncEpoch(),
+		history: make([]Snapshot, 0, syncHistorySize),
+	}
+}
+
+// newSyncEpoch returns a random identifier of a leader.
+func newSyncEpoch() string {
+	b := make([]byte, 8)
+
+	// The clock is good enough, if there is no randomness at all.
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+func (sh *syncHandler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	current, err := sh.current()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := SyncResponse{
+		Epoch:      sh.epoch,
+		Generation: current.Generation,
+		Full:       true,
+		Snapshot:   &current,
+	}
+
+	q := r.URL.Query()
+
+	if since, err := strconv.ParseUint(q.Get(syncSinceParam), 10, 64); err == nil && q.Get(syncEpochParam) == sh.epoch {
+		if old, exists := sh.find(since); exists {
+			changes := DiffSnapshots(old, current)
+
+			res.Full = false
+			res.Snapshot = nil
+			res.Changes = &changes
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// current returns the snapshot of the current generation,
+// recording it in the history if it is a new one.
+func (sh *syncHandler[T]) current() (Snapshot, error) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if l := len(sh.history); l > 0 && sh.history[l-1].Generation == sh.tree.Generation() {
+		return sh.history[l-1], nil
+	}
+
+	snap, err := sh.tree.Snapshot(sh.codec)
+	if err != nil {
+		return snap, err
+	}
+
+	if len(sh.history) == syncHistorySize {
+		sh.history = append(sh.history[:0], sh.history[1:]...)
+	}
+
+	sh.history = append(sh.history, snap)
+
+	return snap, nil
+}
+
+func (sh *syncHandler[T]) find(generation uint64) (Snapshot, bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	for _, snap := range sh.history {
+		if snap.Generation == generation {
+			return snap, true
+		}
+	}
+
+	return Snapshot{}, false
+}
+
+// SyncClient is the follower side of the sync protocol, keeping
+// its tree identical to the tree of the leader.
+type SyncClient[T storeValue] struct {
+	url    string
+	client *http.Client
+	tree   *Tree[T]
+	codec  Codec[T]
+
+	mu         sync.Mutex
+	epoch      string
+	generation uint64
+	synced     bool
+}
+
+// NewSyncClient creates a follower of the leader served on the given url.
+func NewSyncClient[T storeValue](url string, t *Tree[T], codec Codec[T]) *SyncClient[T] {
+	return &SyncClient[T]{
+		url:    url,
+		client: http.DefaultClient,
+		tree:   t,
+		codec:  codec,
+	}
+}
+
+// Pull fetches the changes from the leader, and applies them on the tree.
+// Like Refresh, a failed pull keeps the last good table of the tree.
+func (sc *SyncClient[T]) Pull(ctx context.Context) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	u, err := url.Parse(sc.url)
+	if err != nil {
+		return err
+	}
+
+	if sc.synced {
+		q := u.Query()
+		q.Set(syncSinceParam, strconv.FormatUint(sc.generation, 10))
+		q.Set(syncEpochParam, sc.epoch)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := sc.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d", errSyncStatus, res.StatusCode)
+	}
+
+	var payload SyncResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return err
+	}
+
+	// The changes of an other leader could not be applied on the
+	// table of the previous one, so the next pull is a full one.
+	if !payload.Full && payload.Epoch != sc.epoch {
+		sc.synced = false
+
+		return fmt.Errorf("%w: %s", errSyncEpoch, payload.Epoch)
+	}
+
+	// Only the full payloads take a snapshot of the tree – to diff
+	// it against the received one –, the changes are applied as they are.
+	switch {
+	case payload.Full && payload.Snapshot != nil:
+		err = sc.tree.Restore(*payload.Snapshot, sc.codec)
+	case payload.Changes != nil:
+		err = sc.tree.Apply(*payload.Changes, sc.codec)
+	}
+
+	if err := sc.tree.revalidate(err); err != nil {
+		return err
+	}
+
+	sc.epoch = payload.Epoch
+	sc.generation = payload.Generation
+	sc.synced = true
+
+	return nil
+}
+
+// Generation returns the generation of the leader, that was last synced.
+func (sc *SyncClient[T]) Generation() uint64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	return sc.generation
+}
@@ -0,0 +1,143 @@
+package rtree
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSync(t *testing.T) {
+	var (
+		codec  Codec[string] = JSONCodec[string]{}
+		leader               = buildStringTree(t, map[string]string{
+			"/api/users/{id}": "users",
+			"/api/products":   "products",
+		})
+		follower = New[string]()
+	)
+
+	server := httptest.NewServer(SyncHandler(leader, codec))
+	defer server.Close()
+
+	client := NewSyncClient(server.URL, follower, codec)
+
+	pull := func() {
+		if err := client.Pull(context.Background()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+
+		if leader.Hash() != follower.Hash() {
+			t.Fatal("expected follower to have the same routes as the leader")
+		}
+
+		if client.Generation() != leader.Generation() {
+			t.Errorf("expected generation: %d; got: %d\n", leader.Generation(), client.Generation())
+		}
+	}
+
+	// Full snapshot.
+	pull()
+
+	// Incremental changes.
+	if err := leader.Delete("/api/products"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := leader.Upsert("/api/users/{id}", "users-v2"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := leader.Insert("/health", "health"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	pull()
+
+	if got := follower.Find("/api/users/1").GetValue(); got != "users-v2" {
+		t.Errorf("expected value: %s; got: %s\n", "users-v2", got)
+	}
+
+	// Nothing changed.
+	pull()
+}
+
+func TestSyncHandlerIncremental(t *testing.T) {
+	var (
+		codec  Codec[string] = JSONCodec[string]{}
+		leader               = buildStringTree(t, map[string]string{"/a": "a"})
+		sh                   = SyncHandler(leader, codec).(*syncHandler[string])
+	)
+
+	first, err := sh.current()
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := leader.Insert("/b", "b"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if _, err := sh.current(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if _, exists := sh.find(first.Generation); !exists {
+		t.Error("expected first generation to be in the history")
+	}
+
+	if _, exists := sh.find(first.Generation + 100); exists {
+		t.Error("expected unknown generation not to be in the history")
+	}
+}
+
+func TestSyncRestartedLeader(t *testing.T) {
+	var (
+		codec    Codec[string] = JSONCodec[string]{}
+		leader                 = buildStringTree(t, map[string]string{"/a": "a"})
+		follower               = New[string]()
+		current  atomic.Value
+	)
+
+	current.Store(SyncHandler(leader, codec))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current.Load().(http.Handler).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewSyncClient(server.URL, follower, codec)
+
+	if err := client.Pull(context.Background()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// The restarted leader has an other table, at the same generation.
+	restarted := buildStringTree(t, map[string]string{"/b": "b"})
+
+	if restarted.Generation() != leader.Generation() {
+		t.Fatalf("expected the same generation: %d; got: %d\n", leader.Generation(), restarted.Generation())
+	}
+
+	sh := SyncHandler(restarted, codec).(*syncHandler[string])
+
+	// The generation of the follower is in the history of the restarted leader.
+	if _, err := sh.current(); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := restarted.Insert("/c", "c"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	current.Store(http.Handler(sh))
+
+	if err := client.Pull(context.Background()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if restarted.Hash() != follower.Hash() {
+		t.Error("expected follower to have the same routes as the restarted leader")
+	}
+}
@@ -0,0 +1,71 @@
+package rtree
+
+import "time"
+
+// RetryPolicy describes how the outbound call of a route is retried by
+// the proxy layer. Attempts is the maximum number of the attempts – the
+// first one included –, Backoff is the delay between two attempts, while
+// On holds the status codes worth a retry.
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration
+	On       []int
+}
+
+// Retries reports whether a response with the given status code is retried.
+func (rp RetryPolicy) Retries(status int) bool {
+	for _, code := range rp.On {
+		if code == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithTimeout attaches the timeout of the outbound call to the route.
+func WithTimeout(d time.Duration) RouteOption {
+	return func(rm *routeMeta) {
+		rm.timeout = d
+	}
+}
+
+// WithRetry attaches the retry policy of the outbound call to the route.
+func WithRetry(policy RetryPolicy) RouteOption {
+	return func(rm *routeMeta) {
+		rm.retry = copyRetryPolicy(&policy)
+	}
+}
+
+// Timeout returns the timeout of the outbound call of the
+// matched route, or 0 if the route has none.
+func (fn *FoundNode[T]) Timeout() time.Duration {
+	if fn.meta == nil {
+		return 0
+	}
+
+	return fn.meta.timeout
+}
+
+// Retry returns the retry policy of the outbound call of
+// the matched route, or nil if the route has none.
+func (fn *FoundNode[T]) Retry() *RetryPolicy {
+	if fn.meta == nil {
+		return nil
+	}
+
+	return copyRetryPolicy(fn.meta.retry)
+}
+
+// copyRetryPolicy copies the policy, so the policy of the
+// stored route could not be altered through its status codes.
+func copyRetryPolicy(rp *RetryPolicy) *RetryPolicy {
+	if rp == nil {
+		return nil
+	}
+
+	cp := *rp
+	cp.On = append([]int(nil), rp.On...)
+
+	return &cp
+}
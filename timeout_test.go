@@ -0,0 +1,63 @@
+package rtree
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRouteTimeoutAndRetry(t *testing.T) {
+	tree := New[*Route]()
+
+	policy := RetryPolicy{
+		Attempts: 3,
+		Backoff:  100 * time.Millisecond,
+		On:       []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+	}
+
+	if err := tree.Insert("/api/reports/{id}", getRoute(), WithTimeout(30*time.Second), WithRetry(policy)); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/api/users", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// The policy of the route must not be altered by the caller.
+	policy.On[0] = http.StatusTeapot
+
+	fn := tree.Find("/api/reports/1")
+
+	if got := fn.Timeout(); got != 30*time.Second {
+		t.Errorf("expected timeout: %v; got: %v\n", 30*time.Second, got)
+	}
+
+	expected := &RetryPolicy{
+		Attempts: 3,
+		Backoff:  100 * time.Millisecond,
+		On:       []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+	}
+
+	got := fn.Retry()
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected retry policy: %v; got: %v\n", expected, got)
+	}
+
+	got.On[0] = http.StatusTeapot
+
+	if !reflect.DeepEqual(tree.Find("/api/reports/1").Retry(), expected) {
+		t.Errorf("expected the stored policy to be unchanged\n")
+	}
+
+	if !got.Retries(http.StatusServiceUnavailable) || got.Retries(http.StatusNotFound) {
+		t.Errorf("unexpected retried status codes: %v\n", got.On)
+	}
+
+	fn = tree.Find("/api/users")
+
+	if fn.Timeout() != 0 || fn.Retry() != nil {
+		t.Errorf("expected no timeout and retry policy; got: %v, %v\n", fn.Timeout(), fn.Retry())
+	}
+}
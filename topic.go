@@ -0,0 +1,67 @@
+package rtree
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// multiLevelWildcard matches any number of levels of a topic.
+	multiLevelWildcard = "#"
+	// zeroLevels is appended to the topics, that are searched again with
+	// no levels for the multi level wildcard. It could not be a level of
+	// a real topic, since the topics never contain the NUL char.
+	zeroLevels = "\x00"
+)
+
+// isTopic reports whether the syntax is one of the topic filters.
+func (s PatternSyntax) isTopic() bool {
+	return s == SyntaxMQTT || s == SyntaxAMQP
+}
+
+// singleLevelWildcard returns the single level wildcard of the syntax.
+func (s PatternSyntax) singleLevelWildcard() string {
+	if s == SyntaxAMQP {
+		return "*"
+	}
+
+	return "+"
+}
+
+// convertTopic converts the given topic filter to the native syntax. A
+// multi level wildcard, that is not the last level, becomes a catch-all
+// param in the middle of the pattern, which is refused by the validation.
+func (t *Tree[T]) convertTopic(pattern string) string {
+	var (
+		segments = strings.Split(pattern, string(slash))
+		single   = t.syntax.singleLevelWildcard()
+	)
+
+	for i, s := range segments {
+		switch s {
+		case single:
+			segments[i] = string(curlyStart) + strconv.Itoa(i) + string(curlyEnd)
+		case multiLevelWildcard:
+			segments[i] = string(curlyStart) + multiLevelWildcard + catchAllSuffix + string(curlyEnd)
+		default:
+			segments[i] = escapeKey(s)
+		}
+	}
+
+	return strings.Join(segments, string(slash))
+}
+
+// lookupZeroLevels searches for the topic filters, whose multi level
+// wildcard matches the key with no levels at all, eg. sport/# of sport.
+func (t *Tree[T]) lookupZeroLevels(key string, attrs map[string]string, trace *Explanation, b *budget) (*Node[T], matchedParams) {
+	key += string(t.segmentDelimiter()) + zeroLevels
+
+	n, params := t.lookupFiltered(key, t.searchKey(key), attrs, trace, nil, b, (*NodeValue[T]).hasCatchAll)
+	if n == nil {
+		return nil, nil
+	}
+
+	params[multiLevelWildcard] = ""
+
+	return n, params
+}
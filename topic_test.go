@@ -0,0 +1,148 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTopicSyntax(t *testing.T) {
+	type testCase struct {
+		name            string
+		opts            []OptionFunc[*Route]
+		patterns        []string
+		key             string
+		expectedPattern string
+		expectedParams  matchedParams
+	}
+
+	var (
+		mqtt = []OptionFunc[*Route]{WithPatternSyntax[*Route](SyntaxMQTT)}
+		amqp = []OptionFunc[*Route]{
+			WithPatternSyntax[*Route](SyntaxAMQP),
+			WithDelimiters[*Route](Delimiters{Segment: '.', ParamStart: '{', ParamEnd: '}'}),
+		}
+	)
+
+	tt := []testCase{
+		{
+			name:            "single level",
+			opts:            mqtt,
+			patterns:        []string{"sport/+/player"},
+			key:             "sport/tennis/player",
+			expectedPattern: "sport/{1}/player",
+			expectedParams:  matchedParams{"1": "tennis"},
+		},
+		{
+			name:     "single level is exactly one level",
+			opts:     mqtt,
+			patterns: []string{"sport/+"},
+			key:      "sport/tennis/player",
+		},
+		{
+			name:            "multi level",
+			opts:            mqtt,
+			patterns:        []string{"sport/#"},
+			key:             "sport/tennis/player/1",
+			expectedPattern: "sport/{#...}",
+			expectedParams:  matchedParams{"#": "tennis/player/1"},
+		},
+		{
+			name:            "multi level matches the parent level",
+			opts:            mqtt,
+			patterns:        []string{"sport/+/#", "sport/+"},
+			key:             "sport/tennis",
+			expectedPattern: "sport/{1}",
+			expectedParams:  matchedParams{"1": "tennis"},
+		},
+		{
+			name:            "multi level with no levels",
+			opts:            mqtt,
+			patterns:        []string{"sport/+/#"},
+			key:             "sport/tennis",
+			expectedPattern: "sport/{1}/{#...}",
+			expectedParams:  matchedParams{"1": "tennis", "#": ""},
+		},
+		{
+			name:            "multi level only",
+			opts:            mqtt,
+			patterns:        []string{"#"},
+			key:             "sport/tennis",
+			expectedPattern: "{#...}",
+			expectedParams:  matchedParams{"#": "sport/tennis"},
+		},
+		{
+			name:            "curly brackets are literal",
+			opts:            mqtt,
+			patterns:        []string{"raw/{id}"},
+			key:             "raw/{id}",
+			expectedPattern: "raw/\\{id\\}",
+			expectedParams:  matchedParams{},
+		},
+		{
+			name:            "amqp words",
+			opts:            amqp,
+			patterns:        []string{"orders.*.created", "orders.#"},
+			key:             "orders.eu.created",
+			expectedPattern: "orders.{1}.created",
+			expectedParams:  matchedParams{"1": "eu"},
+		},
+		{
+			name:            "amqp multi level",
+			opts:            amqp,
+			patterns:        []string{"orders.*.created", "orders.#"},
+			key:             "orders.eu.deleted",
+			expectedPattern: "orders.{#...}",
+			expectedParams:  matchedParams{"#": "eu.deleted"},
+		},
+		{
+			name:            "amqp multi level with no levels",
+			opts:            amqp,
+			patterns:        []string{"orders.*.created", "orders.#"},
+			key:             "orders",
+			expectedPattern: "orders.{#...}",
+			expectedParams:  matchedParams{"#": ""},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New(tc.opts...)
+
+			for _, p := range tc.patterns {
+				if err := tree.Insert(p, &Route{}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			fn := tree.Find(tc.key)
+
+			if tc.expectedPattern == "" {
+				if fn != nil {
+					t.Fatalf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %s\n", tc.expectedPattern, fn.GetPattern())
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), tc.expectedParams) {
+				t.Errorf("expected params: %v; got: %v\n", tc.expectedParams, fn.GetParams())
+			}
+		})
+	}
+}
+
+func TestTopicSyntaxValidation(t *testing.T) {
+	tree := New(WithPatternSyntax[*Route](SyntaxMQTT))
+
+	if err := tree.Insert("sport/#/player", &Route{}); !errors.Is(err, errBadPathParamSyntax) {
+		t.Fatalf("expected error: %v; got: %v\n", errBadPathParamSyntax, err)
+	}
+}
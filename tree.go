@@ -3,8 +3,12 @@ package rtree
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -28,40 +32,171 @@ type (
 var (
 	errBadPathParamSyntax = fmt.Errorf("[rtree %s]: bad path param syntax", version)
 	errKeyIsAlreadyStored = fmt.Errorf("[rtree %s]: key is already stored", version)
-	errKeyIsEmpty         = fmt.Errorf("[rtree %s]: key is empty", version)
 	errMissingSlashPrefix = fmt.Errorf("[rtree %s]: urls must be started with a '/'", version)
 	errNoCommonPrefix     = fmt.Errorf("[rtree %s]: no commmon prefix in given strings", version)
 	errPresentSlashSuffix = fmt.Errorf("[rtree %s]: urls must not be ended with a '/'", version)
-	errRootIsNil          = fmt.Errorf("[rtree %s]: the root of the tree is <nil>", version)
-	errTreeIsNil          = fmt.Errorf("[rtree %s]: the tree is <nil>", version)
+	errInvalidUTF8        = fmt.Errorf("[rtree %s]: key is not valid UTF-8", version)
+	errKeyNotFound        = fmt.Errorf("[rtree %s]: key is not found", version)
+	errRouteHasAliases    = fmt.Errorf("[rtree %s]: route has aliases", version)
+
+	// errEmptyParamName is a syntax error as well, eg. /api/{}/list.
+	errEmptyParamName = fmt.Errorf("%w: empty param name", errBadPathParamSyntax)
 )
 
 type Tree[T storeValue] struct {
-	mu   sync.RWMutex
-	root *Node[T]
+	mu       sync.RWMutex
+	root     *Node[T]
+	matchers map[string]SegmentMatcher
+
+	defaultRoute string
+	findObserver FindObserver
+	rateLimiting bool
+	runeMatching bool
+
+	boundaryPrefixes bool
+	comparer         ValueComparer[T]
+
+	paramPolicy   *ParamPolicy
+	paramPolicies map[string]ParamPolicy
+
+	// nonEmptyParams makes the empty captures fail the match.
+	nonEmptyParams bool
+
+	// windowed is set once the first windowed route is inserted,
+	// since the lookup caches could not tell when a window ends.
+	windowed atomic.Bool
+
+	// paramNameValidator checks the param names of the inserted patterns.
+	paramNameValidator func(name string) error
+
+	// resolution is the order of the static and catch-all routes.
+	resolution ResolutionOrder
+
+	// generation is bumped on every mutation of the tree.
+	generation atomic.Uint64
+
+	// lastRouteID is the ID of the last stored route, while
+	// routes indexes the stored routes by their IDs.
+	lastRouteID uint64
+	routes      map[uint64]*NodeValue[T]
+
+	// aliases indexes the IDs of the aliases by the IDs of their routes.
+	aliases map[uint64]map[uint64]struct{}
+
+	// stats collects the telemetry of the insertions.
+	stats insertStats
+
+	// syncedAt is the time of the last successful refresh
+	// from a dynamic source, see Refresh.
+	syncedAt time.Time
+
+	// persistence saves the mutations, if it is not nil.
+	persistence *persistence[T]
+
+	// watchers are notified about the mutations.
+	watchers map[*watcher[T]]struct{}
+
+	// backtrackBudget is the maximum number of nodes visited
+	// by a single lookup, or 0 if it is unlimited.
+	backtrackBudget int
+
+	caseInsensitive bool
+	trailingSlash   TrailingSlashPolicy
+	syntax          PatternSyntax
+	readLocking     bool
+	objectKeys      bool
+	delimiters      *delimiterTable
+	cache           *lookupCache[T]
+	prefixCache     *lookupCache[T]
+	metrics         *Metrics
+
+	// options holds the values of the exclusive options,
+	// while optionErrs collects the errors of the options.
+	options    map[string]any
+	optionErrs []error
+}
+
+// ParamInfo is the read-only description of a path param.
+// Position is the index of the segment – split by slashes – that holds the param.
+// CatchAll reports whether the param captures all the remaining segments.
+type ParamInfo struct {
+	Name     string `json:"name"`
+	Matcher  string `json:"matcher,omitempty"`
+	Position int    `json:"position"`
+	CatchAll bool   `json:"catchAll,omitempty"`
 }
 
 type paramInfo struct {
-	key string
-	pos uint8
+	key      string
+	matcher  string
+	pos      int
+	catchAll bool
 }
 
 type NodeValue[T storeValue] struct {
-	value  T
-	params []paramInfo
+	id      uint64
+	value   T
+	pattern string
+	params  []paramInfo
+	meta    routeMeta
+	split   *trafficSplit[T]
+
+	// aliasOf is the ID of the route, whose value is
+	// resolved by this route, or 0 if it is not an alias.
+	aliasOf uint64
+
+	// cell holds the current version of the route – or of the
+	// route this one is an alias of –, see resolve.
+	cell *routeCell[T]
+
+	// checks are the compiled checks of the params.
+	checks []paramCheck
+
+	// external is the pattern with the delimiters of the tree,
+	// if they differ from the default ones, see WithDelimiters.
+	external string
+
+	// variants are the detached leaves of the other values of the
+	// pattern, each having a guard of its own, in the order of their
+	// IDs, see addVariant. They are carried by the value – instead of
+	// the node –, since the values are moved by the splits and merges.
+	variants []*Node[T]
 }
 
 type Node[T storeValue] struct {
 	key      string
 	value    *NodeValue[T]
 	children []*Node[T]
+
+	// paramIdx is the index of the first curlyStart in the key,
+	// or -1 if there is none. It is precomputed, so the lookups
+	// do not have to scan the key on every visit.
+	paramIdx int
+
+	// ops is the compiled form of the key, see compileKey.
+	ops []keyOp
+
+	// index is the child index of a wide node, or nil, see childIndex.
+	index *childIndex
 }
 
 type matchedParams map[string]string
 
 type FoundNode[T storeValue] struct {
-	value  T
-	params matchedParams
+	id      uint64
+	value   T
+	pattern string
+	params  matchedParams
+	allowed bool
+	meta    *routeMeta
+
+	// delimiter is the segment delimiter of the tree, see Segments.
+	delimiter byte
+
+	// base is the matched prefix of the key, if the node was
+	// found as the longest match of the key, see BasePath.
+	base string
+	rest string
 }
 
 // IsLeaf returns whether a node is a leaf.
@@ -80,6 +215,47 @@ func (nv *NodeValue[T]) GetValue() T {
 	return nv.value
 }
 
+// Key returns the part of the key that is stored in the node.
+// The full key of a leaf is the concatenation of the keys
+// on the path from the root to the leaf.
+func (n *Node[T]) Key() string {
+	return n.key
+}
+
+// Children returns a copy of the children of the node,
+// so the structure of the tree can not be altered through it.
+func (n *Node[T]) Children() []*Node[T] {
+	children := make([]*Node[T], len(n.children))
+	copy(children, n.children)
+
+	return children
+}
+
+// Pattern returns the full key, that the value was stored with.
+func (nv *NodeValue[T]) Pattern() string {
+	if nv.external != "" {
+		return nv.external
+	}
+
+	return nv.pattern
+}
+
+// ParamInfos returns the path params of the stored key.
+func (nv *NodeValue[T]) ParamInfos() []ParamInfo {
+	infos := make([]ParamInfo, len(nv.params))
+
+	for i, pi := range nv.params {
+		infos[i] = ParamInfo{
+			Name:     pi.key,
+			Matcher:  pi.matcher,
+			Position: pi.pos,
+			CatchAll: pi.catchAll,
+		}
+	}
+
+	return infos
+}
+
 // GetValue returns the stored value of a pointer to a node.
 func (fn *FoundNode[T]) GetValue() T {
 	return fn.value
@@ -90,6 +266,11 @@ func (fn *FoundNode[T]) GetParams() matchedParams {
 	return fn.params
 }
 
+// GetPattern returns the stored pattern that was matched.
+func (fn *FoundNode[T]) GetPattern() string {
+	return fn.pattern
+}
+
 func New[T storeValue](opts ...OptionFunc[T]) *Tree[T] {
 	t := &Tree[T]{
 		mu: sync.RWMutex{},
@@ -104,34 +285,406 @@ func New[T storeValue](opts ...OptionFunc[T]) *Tree[T] {
 
 // insert tries to store a key-value pair in the tree.
 // In case of unsuccessful insertion, we return the root of the error.
-func (t *Tree[T]) Insert(key string, value T) error {
-	if t == nil {
-		return errTreeIsNil
-	}
+func (t *Tree[T]) Insert(key string, value T, opts ...RouteOption) error {
+	return t.insert(key, value, opts, nil)
+}
 
-	if key == "" {
-		return errKeyIsEmpty
+// insert is the main logic of Insert. The optional prepare function
+// could alter the value to be stored, before it is actually stored.
+func (t *Tree[T]) insert(key string, value T, opts []RouteOption, prepare func(*NodeValue[T])) error {
+	nv, err := t.newNodeValue(key, value, opts, prepare)
+	if err != nil {
+		return err
 	}
 
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if err := checkUrl(key); err != nil {
+	if err := t.store(nv); err != nil {
 		return err
 	}
 
-	var (
-		paramInfos = getPathParams(key)
-		nv         = createNewNodeValue[T](value, paramInfos)
-	)
+	return t.persist(nv)
+}
+
+// newNodeValue checks the given key, and creates the value to be stored.
+func (t *Tree[T]) newNodeValue(key string, value T, opts []RouteOption, prepare func(*NodeValue[T])) (*NodeValue[T], error) {
+	key, err := t.checkPattern(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.createValue(key, value, opts, prepare), nil
+}
+
+// newStoredValue is the same as newNodeValue, but the pattern is already
+// in its stored form – eg. it was saved by a snapshot or by the store of
+// the tree –, so it is not normalized again. Normalizing a stored pattern
+// is not idempotent: the stored params of the colon syntax would become
+// literal brackets, and the delimiters would be swapped twice.
+func (t *Tree[T]) newStoredValue(pattern string, value T) (*NodeValue[T], error) {
+	if t == nil {
+		return nil, ErrTreeIsNil
+	}
+
+	if err := t.checkStoredPattern(pattern); err != nil {
+		return nil, err
+	}
+
+	return t.createValue(pattern, value, nil, nil), nil
+}
+
+// createValue creates the value to be stored under the checked key.
+func (t *Tree[T]) createValue(key string, value T, opts []RouteOption, prepare func(*NodeValue[T])) *NodeValue[T] {
+	// Every node key, param name and the pattern of the route is sliced
+	// from this single backing string, so splits never copy bytes, and
+	// the tree does not retain the – possibly larger – buffer of the caller.
+	key = strings.Clone(key)
+
+	nv := createNewNodeValue[T](key, value, getPathParams(key))
+
+	for _, o := range opts {
+		o(&nv.meta)
+	}
+
+	if prepare != nil {
+		prepare(nv)
+	}
+
+	nv.checks = t.compileParams(nv)
+
+	if t.delimiters != nil {
+		nv.external = t.externalPattern(key)
+	}
+
+	return nv
+}
+
+// store stores the given value in the tree under its pattern.
+// The caller must hold the write lock.
+func (t *Tree[T]) store(nv *NodeValue[T]) error {
+	key := nv.pattern
 
 	// If the root is still nil, then the new node is the root.
 	if t.root == nil {
 		t.root = createNewNode(key, nv)
+		t.stats.leafAt(1)
+		t.stored(nv)
 		return nil
 	}
 
-	return insertRec(t.root, key, nv)
+	err := insertRec(t.root, key, nv, 1, &t.stats)
+
+	// Only the object keys could have nothing in common with the root,
+	// which is then replaced by an empty one – having the old as child.
+	if errors.Is(err, errNoCommonPrefix) {
+		t.root = createNewNode("", nil, t.root)
+		err = insertRec(t.root, key, nv, 1, &t.stats)
+	}
+
+	if err != nil {
+		if errors.Is(err, errKeyIsAlreadyStored) {
+			if t.addVariant(nv) {
+				return nil
+			}
+
+			if t.isSameValue(key, nv.value) {
+				return nil
+			}
+			return t.conflictError(err, key)
+		}
+		return err
+	}
+
+	t.stored(nv)
+
+	return nil
+}
+
+// Upsert stores the key-value pair in the tree, replacing the value
+// if the key is already stored. The replaced route keeps its ID.
+// It fails on protected routes.
+func (t *Tree[T]) Upsert(key string, value T, opts ...RouteOption) error {
+	return t.upsert(key, value, opts, false)
+}
+
+// upsert is the main logic of Upsert. If force is true,
+// the protected routes are replaced as well.
+func (t *Tree[T]) upsert(key string, value T, opts []RouteOption, force bool) error {
+	nv, err := t.newNodeValue(key, value, opts, nil)
+	if err != nil {
+		return err
+	}
+
+	return t.upsertValue(nv, force)
+}
+
+// upsertValue stores the created value, replacing the value
+// of the same pattern, if there is one.
+func (t *Tree[T]) upsertValue(nv *NodeValue[T], force bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.upsertLocked(nv, force)
+}
+
+// upsertLocked is the main logic of upsertValue.
+// The caller must hold the write lock.
+func (t *Tree[T]) upsertLocked(nv *NodeValue[T], force bool) error {
+	n := findExactRec(t.root, nv.pattern)
+	if n == nil {
+		if err := t.store(nv); err != nil {
+			return err
+		}
+
+		return t.persist(nv)
+	}
+
+	if n.value.meta.protected {
+		if !force {
+			return errRouteIsProtected
+		}
+
+		// The replaced route remains protected.
+		nv.meta.protected = true
+	}
+
+	nv.id = n.value.id
+	nv.variants = n.value.variants
+
+	// An alias replaced by a value is an alias no more.
+	if n.value.aliasOf == 0 {
+		nv.cell = n.value.cell
+	} else {
+		nv.cell = &routeCell[T]{}
+	}
+
+	return t.replace(n, nv)
+}
+
+// replace replaces the value of the leaf with the given version of
+// the same route, notifying the watchers. The aliases of the route are
+// persisted again, since their blobs hold the value they resolve to.
+// The caller must hold the write lock.
+func (t *Tree[T]) replace(n *Node[T], nv *NodeValue[T]) error {
+	t.unindexAlias(n.value)
+
+	if nv.aliasOf != 0 {
+		t.indexAlias(nv)
+	}
+
+	n.value = nv
+	t.publish(nv)
+
+	t.generation.Add(1)
+	t.notify(ChangeUpdate, nv, t.resolve(nv).value)
+
+	if err := t.persist(nv); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, a := range t.aliasesOf(nv.id) {
+		if err := t.persist(a); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return newMultiError(errs)
+}
+
+// Delete removes the given key from the tree. The key is matched
+// exactly – path params included – so it must be the stored pattern.
+// The structure is fixed up locally: a node left without value and
+// with a single child is merged with that child, so the tree remains
+// the same as if the key was never inserted. It fails on protected routes.
+func (t *Tree[T]) Delete(key string) error {
+	return t.delete(key, false)
+}
+
+// delete is the main logic of Delete. If force is true,
+// the protected routes are removed as well.
+func (t *Tree[T]) delete(key string, force bool) error {
+	if err := checkTree(t); err != nil {
+		return err
+	}
+
+	if key == "" {
+		return ErrKeyIsEmpty
+	}
+
+	return t.deleteStored(t.normalizePattern(key), force)
+}
+
+// deleteStored is the same as delete, but the pattern
+// is already in its stored form, see newStoredValue.
+func (t *Tree[T]) deleteStored(key string, force bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.deleteLocked(key, force)
+}
+
+// deleteLocked is the main logic of deleteStored.
+// The caller must hold the write lock.
+func (t *Tree[T]) deleteLocked(key string, force bool) error {
+	path := findExactPath(t.root, key)
+	if path == nil {
+		return errKeyNotFound
+	}
+
+	n := path[len(path)-1]
+
+	if n.value.meta.protected && !force {
+		return errRouteIsProtected
+	}
+
+	if len(t.aliasesOf(n.value.id)) > 0 {
+		return errRouteHasAliases
+	}
+
+	t.remove(path, ChangeDelete)
+
+	return t.unpersist(key)
+}
+
+// remove removes the leaf at the end of the given path, notifying
+// the watchers with the given kind. The caller must hold the write lock.
+func (t *Tree[T]) remove(path []*Node[T], kind ChangeKind) {
+	var (
+		n        = path[len(path)-1]
+		resolved = t.resolve(n.value)
+	)
+
+	delete(t.routes, n.value.id)
+	t.unindexAlias(n.value)
+
+	t.generation.Add(1)
+	t.notify(kind, n.value, resolved.value)
+
+	// The other values of the pattern are removed with it.
+	for _, v := range n.value.variants {
+		delete(t.routes, v.value.id)
+		t.notify(kind, v.value, v.value.value)
+	}
+
+	n.value = nil
+
+	var parent *Node[T]
+	if len(path) > 1 {
+		parent = path[len(path)-2]
+	}
+
+	switch len(n.children) {
+	case 0:
+		if parent == nil {
+			t.root = nil
+			return
+		}
+
+		removeChild(parent, n)
+
+		// The parent could have become a useless inner node.
+		if !parent.IsLeaf() && len(parent.children) == 1 {
+			mergeWithChild(parent)
+
+			if len(path) > 2 {
+				reorderChild(path[len(path)-3], parent)
+			}
+		}
+	case 1:
+		mergeWithChild(n)
+
+		if parent != nil {
+			reorderChild(parent, n)
+		}
+	}
+}
+
+// findExactPath returns the nodes from the root to the leaf stored with
+// exactly the given key, or nil if there is no such leaf.
+func findExactPath[T storeValue](n *Node[T], key string) []*Node[T] {
+	path := make([]*Node[T], 0)
+
+	for n != nil {
+		if !strings.HasPrefix(key, n.key) {
+			return nil
+		}
+
+		path = append(path, n)
+		key = key[len(n.key):]
+
+		if key == "" {
+			if n.IsLeaf() {
+				return path
+			}
+			return nil
+		}
+
+		var next *Node[T]
+
+		for _, ch := range n.children {
+			if strings.HasPrefix(key, ch.key) {
+				next = ch
+				break
+			}
+		}
+
+		n = next
+	}
+
+	return nil
+}
+
+func removeChild[T storeValue](n, child *Node[T]) {
+	for i, ch := range n.children {
+		if ch == child {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			n.reindex()
+
+			return
+		}
+	}
+}
+
+// mergeWithChild merges the node with its only child.
+func mergeWithChild[T storeValue](n *Node[T]) {
+	child := n.children[0]
+
+	n.setKey(n.key + child.key)
+	n.value = child.value
+	n.children = child.children
+	n.index = child.index
+}
+
+// stored does the bookkeeping of a newly stored value.
+func (t *Tree[T]) stored(nv *NodeValue[T]) {
+	if t.routes == nil {
+		t.routes = make(map[uint64]*NodeValue[T])
+	}
+
+	// The values put back by a rollback – see Apply – keep their IDs.
+	if nv.id == 0 {
+		t.lastRouteID++
+		nv.id = t.lastRouteID
+	}
+
+	if nv.aliasOf != 0 {
+		t.indexAlias(nv)
+	} else if nv.cell == nil {
+		nv.cell = &routeCell[T]{}
+	}
+
+	t.publish(nv)
+
+	// A value put back by a rollback brings its variants as well.
+	for _, v := range nv.variants {
+		t.publish(v.value)
+	}
+
+	t.generation.Add(1)
+	t.notify(ChangeInsert, nv, t.resolve(nv).value)
 }
 
 // iterateInsert iterates on the given node's children, and calls
@@ -140,11 +693,18 @@ func (t *Tree[T]) Insert(key string, value T) error {
 // differs from errNoCommonPrefix, we return it. If none of those happaned, we
 // simply return errNoCommonPrefix which indicates we were trying to
 // insert on a wrong branch.
-func iterateInsert[T storeValue](n *Node[T], key string, value *NodeValue[T]) error {
+func iterateInsert[T storeValue](n *Node[T], key string, value *NodeValue[T], depth int, st *insertStats) error {
 	for _, ch := range n.children {
-		insertErr := insertRec(ch, key, value)
+		keyLen := len(ch.key)
+
+		insertErr := insertRec(ch, key, value, depth+1, st)
 
 		if insertErr == nil {
+			// A split shortens the key of the child, which
+			// could change its place amongst its siblings.
+			if len(ch.key) != keyLen {
+				reorderChild(n, ch)
+			}
 			return nil
 		}
 
@@ -156,14 +716,21 @@ func iterateInsert[T storeValue](n *Node[T], key string, value *NodeValue[T]) er
 	return errNoCommonPrefix
 }
 
-func insertRec[T storeValue](n *Node[T], key string, value *NodeValue[T]) error {
+// insertRec inserts the value under the given node, which is at the given
+// depth of the tree. The structural changes are recorded in st.
+func insertRec[T storeValue](n *Node[T], key string, value *NodeValue[T], depth int, st *insertStats) error {
 	lcp := longestCommonPrefix(n.key, key)
 
-	// There is no chance of inserting in this branch.
-	if lcp == 0 {
+	// There is no chance of inserting in this branch – but
+	// under the empty root, that every key has in common.
+	if lcp == 0 && n.key != "" {
 		return errNoCommonPrefix
 	}
 
+	if splitsCatchAll(key, n.key, lcp) {
+		return fmt.Errorf("%w: %s next to a catch-all", errDotInParam, value.pattern)
+	}
+
 	var (
 		currentKeyLen = len(n.key)
 		keyLen        = len(key)
@@ -178,6 +745,7 @@ func insertRec[T storeValue](n *Node[T], key string, value *NodeValue[T]) error
 		}
 		// Otherwise we simply the store the value and we are done.
 		n.value = value
+		st.leafAt(depth)
 
 		return nil
 	}
@@ -193,9 +761,13 @@ func insertRec[T storeValue](n *Node[T], key string, value *NodeValue[T]) error
 		// then we have to store it here.
 		keyRem := key[lcp:]
 		if keyRem == "" {
-			n.key = n.key[:lcp]
+			n.setKey(n.key[:lcp])
 			n.value = value
 			n.children = []*Node[T]{cNewNode}
+			n.reindex()
+
+			st.splits++
+			st.leafAt(depth)
 
 			return nil
 		}
@@ -203,15 +775,21 @@ func insertRec[T storeValue](n *Node[T], key string, value *NodeValue[T]) error
 		newNode := createNewNode(keyRem, value)
 
 		n.value = nil
-		n.key = n.key[:lcp]
-		n.children = []*Node[T]{cNewNode, newNode}
+		n.setKey(n.key[:lcp])
+		n.children = []*Node[T]{cNewNode}
+
+		addToChildren(n, newNode)
+
+		st.splits++
+		st.appends++
+		st.leafAt(depth + 1)
 
 		return nil
 	}
 
 	keyRem := key[lcp:]
 
-	err := iterateInsert(n, keyRem, value)
+	err := iterateInsert(n, keyRem, value, depth, st)
 
 	if err == nil {
 		return nil
@@ -223,11 +801,175 @@ func insertRec[T storeValue](n *Node[T], key string, value *NodeValue[T]) error
 
 	addToChildren(n, createNewNode(keyRem, value))
 
+	st.appends++
+	st.leafAt(depth + 1)
+
 	return nil
 }
 
+// addToChildren adds the new node to the children of the given node,
+// keeping the invariant of the static children being before the
+// wildcard ones – and the catch-all ones being the last –, so the
+// most specific match wins – and is found faster.
 func addToChildren[T storeValue](n, newNode *Node[T]) {
-	n.children = append(n.children, newNode)
+	idx := len(n.children)
+
+	for i, ch := range n.children {
+		if precedes(newNode.key, ch.key) {
+			idx = i
+			break
+		}
+	}
+
+	n.children = append(n.children, nil)
+	copy(n.children[idx+1:], n.children[idx:])
+	n.children[idx] = newNode
+
+	n.reindex()
+}
+
+// reorderChild moves the child – whose key has changed – to its place.
+func reorderChild[T storeValue](n, child *Node[T]) {
+	removeChild(n, child)
+	addToChildren(n, child)
+}
+
+// precedes reports whether the sibling with key1 must be tried before the
+// one with key2. Static siblings keep their order of insertion, since only
+// one of them could match at all. While the wildcard siblings are ordered by
+// the number of their static bytes – the more specific one goes first –,
+// then the params with matchers go first, and finally they are ordered by
+// their keys, so the order does not depend on the order of insertion.
+func precedes(key1, key2 string) bool {
+	var (
+		rank1 = nodeRank(key1)
+		rank2 = nodeRank(key2)
+	)
+
+	if rank1 != rank2 {
+		return rank1 < rank2
+	}
+
+	if rank1 == 0 {
+		return false
+	}
+
+	var (
+		static1 = staticLen(key1)
+		static2 = staticLen(key2)
+	)
+
+	if static1 != static2 {
+		return static1 > static2
+	}
+
+	// A param with a matcher goes before the one without,
+	// since the latter would accept every segment.
+	if c1, c2 := isConstrained(key1), isConstrained(key2); c1 != c2 {
+		return c1
+	}
+
+	return key1 < key2
+}
+
+// isConstrained reports whether the param, that the wildcard
+// key starts with – or continues –, references a matcher.
+func isConstrained(key string) bool {
+	end := indexUnescaped(key, curlyEnd)
+	if end == -1 {
+		end = len(key)
+	}
+
+	return strings.IndexByte(key[:end], matcherSeparator) != -1
+}
+
+// staticLen returns the number of bytes of the key outside of the params.
+// The key could start inside of a param, which was opened by an ancestor.
+func staticLen(key string) int {
+	var (
+		count   = 0
+		inParam = nodeRank(key) > 0
+	)
+
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case escapeChar:
+			i++
+			count++
+		case curlyStart:
+			inParam = true
+		case curlyEnd:
+			inParam = false
+		default:
+			if !inParam {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// nodeRank returns the precedence of a node by its key: 0 for static
+// keys, 1 for keys starting with – or inside of – a param, and 2 if
+// that param is a catch-all.
+func nodeRank(key string) int {
+	var (
+		start = indexUnescaped(key, curlyStart)
+		end   = indexUnescaped(key, curlyEnd)
+	)
+
+	// A closing bracket before any opening one means, that
+	// the key continues a param started by an ancestor.
+	inParam := start == 0 || (end != -1 && (start == -1 || end < start))
+
+	if !inParam {
+		return 0
+	}
+
+	if end != -1 && strings.HasSuffix(key[:end], catchAllSuffix) {
+		return 2
+	}
+
+	return 1
+}
+
+// hasParam returns whether the key of the node contains the start of a path param.
+func (n *Node[T]) hasParam() bool {
+	return n.paramIdx != -1
+}
+
+// setKey sets the key of the node, keeping the precomputed fields up to date.
+func (n *Node[T]) setKey(key string) {
+	n.key = key
+	n.paramIdx = indexUnescaped(key, curlyStart)
+	n.ops = compileKey(key)
+}
+
+// findExactRec returns the leaf stored with exactly the given key,
+// treating the path params as plain characters. Since siblings never
+// share a common prefix, there is at most one child to descend into.
+func findExactRec[T storeValue](n *Node[T], key string) *Node[T] {
+	if n == nil || !strings.HasPrefix(key, n.key) {
+		return nil
+	}
+
+	rem := key[len(n.key):]
+
+	if rem == "" {
+		if n.IsLeaf() {
+			return n
+		}
+		return nil
+	}
+
+	for _, ch := range n.children {
+		if found := findExactRec(ch, rem); found != nil {
+			return found
+		}
+	}
+
+	return nil
 }
 
 // checkUrl checks the given of errors such as missing slash prefix
@@ -243,19 +985,21 @@ func checkUrl(url string) error {
 		return errPresentSlashSuffix
 	}
 
-	// Check for path params, and check for its syntax.
-	return checkPathParams(url)
+	// Check for path params, and check for its syntax. The
+	// prefix of the host patterns is not an escape sequence.
+	return checkPathParams(strings.TrimPrefix(url, hostPrefix))
 }
 
 func checkPathParams(url string) error {
 	// If there is none of the curly brackets, we are good to go.
-	if !strings.ContainsRune(url, curlyStart) && !strings.ContainsRune(url, curlyEnd) {
+	if !strings.ContainsAny(url, `{}\`) {
 		return nil
 	}
 
 	var (
 		insideParam = false
 		counter     = 0
+		paramStart  = 0
 	)
 
 	for counter < len(url) {
@@ -264,17 +1008,32 @@ func checkPathParams(url string) error {
 			return errBadPathParamSyntax
 		}
 
+		// Escape sequences are only allowed outside of the params.
+		if url[counter] == escapeChar {
+			if insideParam || counter+1 == len(url) || !isEscapable(url[counter+1]) {
+				return errBadEscape
+			}
+
+			counter += 2
+			continue
+		}
+
 		if url[counter] == curlyStart {
 			if insideParam {
 				return errBadPathParamSyntax
 			}
 			insideParam = true
+			paramStart = counter + 1
 		}
 
 		if url[counter] == curlyEnd {
 			if !insideParam {
 				return errBadPathParamSyntax
 			}
+
+			if err := checkParam(url, paramStart, counter); err != nil {
+				return err
+			}
 			insideParam = false
 		}
 
@@ -290,15 +1049,12 @@ func checkPathParams(url string) error {
 	return nil
 }
 
-// checkTree does a basic check on the given tree, returns error
-// if either the tree or the root is nil.
+// checkTree returns error, if the tree is nil. The root is not checked,
+// since it is created lazily by the first insertion – under the lock –,
+// so an empty tree is searched, walked and listed as any other.
 func checkTree[T storeValue](t *Tree[T]) error {
 	if t == nil {
-		return errTreeIsNil
-	}
-
-	if t.root == nil {
-		return errRootIsNil
+		return ErrTreeIsNil
 	}
 
 	return nil
@@ -314,7 +1070,9 @@ func min(num1, num2 int) int {
 }
 
 // longestCommonPrefix returns the length of the
-// longest common prefix of two given strings.
+// longest common prefix of two given strings. The prefix never
+// ends in the middle of a multi-byte rune, so the nodes are never
+// split inside a rune, and their keys remain valid UTF-8.
 func longestCommonPrefix(str1, str2 string) int {
 	var counter = 0
 
@@ -324,28 +1082,72 @@ func longestCommonPrefix(str1, str2 string) int {
 		counter += 1
 	}
 
+	for counter > 0 && (isInsideRune(str1, counter) || isInsideRune(str2, counter)) {
+		counter--
+	}
+
+	// An escape sequence is never split either.
+	if counter > 0 && counter < maxVal && isEscaped(str1, counter) {
+		counter--
+	}
+
 	return counter
 }
 
+// isInsideRune reports whether the byte at the given index
+// is a continuation byte of a multi-byte rune.
+func isInsideRune(str string, idx int) bool {
+	return idx < len(str) && !utf8.RuneStart(str[idx])
+}
+
 // createNewNode is a factory for creating new nodes.
 func createNewNode[T storeValue](key string, value *NodeValue[T], children ...*Node[T]) *Node[T] {
 	n := &Node[T]{
 		key:      key,
+		paramIdx: indexUnescaped(key, curlyStart),
+		ops:      compileKey(key),
 		value:    value,
 		children: make([]*Node[T], 0),
 	}
 
 	if len(children) > 0 {
 		n.children = children
+		n.reindex()
 	}
 
 	return n
 }
 
-func createNewNodeValue[T storeValue](val T, paramsInfo []paramInfo) *NodeValue[T] {
+// newFoundNode is a factory for creating the result of a lookup.
+func (t *Tree[T]) newFoundNode(n *Node[T], params matchedParams) *FoundNode[T] {
+	fn := &FoundNode[T]{}
+
+	t.fillFoundNode(fn, n, params)
+
+	return fn
+}
+
+// fillFoundNode overwrites the given result with the found node.
+func (t *Tree[T]) fillFoundNode(fn *FoundNode[T], n *Node[T], params matchedParams) {
+	target := t.resolve(n.value)
+
+	*fn = FoundNode[T]{
+		id:      n.value.id,
+		value:   target.pick(rand.Intn),
+		pattern: n.value.Pattern(),
+		params:  params,
+		allowed: t.allow(&target.meta),
+		meta:    &target.meta,
+
+		delimiter: t.segmentDelimiter(),
+	}
+}
+
+func createNewNodeValue[T storeValue](pattern string, val T, paramsInfo []paramInfo) *NodeValue[T] {
 	return &NodeValue[T]{
-		value:  val,
-		params: paramsInfo,
+		value:   val,
+		pattern: pattern,
+		params:  paramsInfo,
 	}
 }
 
@@ -356,101 +1158,354 @@ func (t *Tree[T]) Find(key string) *FoundNode[T] {
 		return nil
 	}
 
+	return t.observe(key, func(key string) *FoundNode[T] {
+		return t.findWithDefault(key, t.resolution)
+	})
+}
+
+var (
+	// ErrNotFound is returned by FindE, if there is no match for the key.
+	ErrNotFound = fmt.Errorf("[rtree %s]: no match", version)
+	// ErrTreeIsNil is returned by the methods of a nil tree.
+	ErrTreeIsNil = fmt.Errorf("[rtree %s]: the tree is <nil>", version)
+	// ErrKeyIsEmpty is returned for an empty key or pattern.
+	ErrKeyIsEmpty = fmt.Errorf("[rtree %s]: key is empty", version)
+)
+
+// FindE is the same as Find, but it tells the misses apart from the
+// problems of the tree and the key: a nil tree, an empty key or – with
+// rune matching – a key that is not valid UTF-8 are errors, while a real
+// miss is ErrNotFound, or ErrRouteDisabled, if only a disabled route
+// matches the key. A lookup aborted by the backtrack budget is a miss
+// as well; TryFind reports it.
+func (t *Tree[T]) FindE(key string) (*FoundNode[T], error) {
+	if err := checkTree(t); err != nil {
+		return nil, err
+	}
+
 	if key == "" {
+		return nil, ErrKeyIsEmpty
+	}
+
+	if t.runeMatching && !utf8.ValidString(key) {
+		return nil, errInvalidUTF8
+	}
+
+	fn := t.Find(key)
+	if fn == nil {
+		if t.matchesDisabled(key) {
+			return nil, ErrRouteDisabled
+		}
+
+		return nil, ErrNotFound
+	}
+
+	return fn, nil
+}
+
+// findWithDefault is the unobserved version of Find.
+func (t *Tree[T]) findWithDefault(key string, order ResolutionOrder) *FoundNode[T] {
+	if fn := t.find(key, order); fn != nil {
+		return fn
+	}
+
+	return t.findDefault(key, order)
+}
+
+// findDefault returns the default route, after the given key had no match.
+func (t *Tree[T]) findDefault(key string, order ResolutionOrder) *FoundNode[T] {
+	if t.defaultRoute == "" || t.defaultRoute == key {
 		return nil
 	}
 
-	n := findRec(t.root, key, false)
+	return t.find(t.defaultRoute, order)
+}
+
+// find is the non-fallback version of Find.
+func (t *Tree[T]) find(key string, order ResolutionOrder) *FoundNode[T] {
+	n, params := t.cachedLookup(key, order)
+
+	if n == nil {
+		return t.findTrailingSlash(key, order)
+	}
+
+	return t.newFoundNode(n, params)
+}
+
+// findNode returns the matching leaf of the given key
+// alongside with the matched params.
+func (t *Tree[T]) findNode(key string) (*Node[T], matchedParams) {
+	n, params, _ := t.lookup(key, nil, t.resolution)
+
+	return n, params
+}
+
+// lookup is the main logic of findNode. The steps of the search are
+// recorded in trace, if it is not nil. The error is only returned, if
+// the search ran out of its backtrack budget.
+func (t *Tree[T]) lookup(key string, trace *Explanation, order ResolutionOrder) (*Node[T], matchedParams, error) {
+	return t.lookupWith(key, nil, trace, nil, order)
+}
+
+// searchKey returns the form of the key, that is searched in the tree:
+// translated to the default delimiters, with its percent-encodings
+// normalized the same way as the ones of the stored URLs – see
+// Normalize –, folded by the case policy and escaped. Only the static
+// parts are compared by it, the params are captured from the key itself.
+func (t *Tree[T]) searchKey(key string) string {
+	key = t.canonicalKey(key)
+
+	if !t.objectKeys {
+		key = normalizePercent(key)
+	}
+
+	return escapeKey(t.foldKey(key))
+}
+
+// lookupWith is the same as lookup, but the guards of the routes are
+// evaluated on the given attributes. The longest match is recorded in
+// longest, if it is not nil.
+func (t *Tree[T]) lookupWith(key string, attrs map[string]string, trace *Explanation, longest *longestMatch[T], order ResolutionOrder) (*Node[T], matchedParams, error) {
+	if key == "" {
+		return nil, nil, nil
+	}
+
+	if t.runeMatching && !utf8.ValidString(key) {
+		return nil, nil, nil
+	}
+
+	var (
+		b       = &budget{limit: t.backtrackBudget}
+		escaped = t.searchKey(key)
+	)
+
+	// The catch-all routes are searched in a first pass of their own,
+	// so they win over every other route they overlap with.
+	if order == CatchAllFirst {
+		n, params := t.lookupFiltered(key, escaped, attrs, trace, longest, b, (*NodeValue[T]).hasCatchAll)
+		if n != nil || b.exceeded {
+			return n, params, b.err()
+		}
+	}
+
+	n, params := t.lookupFiltered(key, escaped, attrs, trace, longest, b, nil)
+
+	if n == nil && !b.exceeded && t.syntax.isTopic() {
+		n, params = t.lookupZeroLevels(key, attrs, trace, b)
+	}
+
+	return n, params, b.err()
+}
+
+// lookupFiltered searches for the key – by its escaped and normalized
+// form –, only accepting the leaves approved by the
+// filter – if it is not nil – and by their guards.
+func (t *Tree[T]) lookupFiltered(key, escaped string, attrs map[string]string, trace *Explanation, longest *longestMatch[T], b *budget, filter func(*NodeValue[T]) bool) (*Node[T], matchedParams) {
+	var (
+		params   matchedParams
+		segments []string
+	)
+
+	accept := func(n *Node[T]) bool {
+		if filter != nil && !filter(n.value) {
+			return false
+		}
+
+		if n.value.meta.inactive() || !n.value.meta.guard.Eval(attrs) {
+			return false
+		}
+
+		// The key is split at most once, no matter how many leaves are tried.
+		if segments == nil {
+			segments = strings.Split(key, string(t.segmentDelimiter()))
+		}
+
+		mp, ok := t.matchSegments(n.value, segments, 0)
+		if ok {
+			params = mp
+		}
+		return ok
+	}
+
+	// The params are matched in the original key, so their values
+	// are unescaped, and keep their case in a case-insensitive tree.
+	n := findRec(t.root, escaped, false, &search[T]{accept: accept, trace: trace, longest: longest, budget: b})
 
 	if n == nil || n.value == nil {
+		return nil, nil
+	}
+
+	return n, params
+}
+
+// search holds the state of a single lookup, that is shared
+// between the recursive calls of findRec.
+type search[T storeValue] struct {
+	// accept approves the found leaves.
+	accept predicateFunction[T]
+	// trace records the steps of the search, if it is not nil.
+	trace *Explanation
+	// longest records the longest match, if it is not nil.
+	longest *longestMatch[T]
+	// paramDepth is the number of the params, that
+	// the node being visited is nested in.
+	paramDepth int
+	// budget limits the number of visited nodes.
+	budget *budget
+}
+
+// step records a step of the search, if it is traced.
+func (s *search[T]) step(n *Node[T], key string, lcp int, isWildcard bool, outcome StepOutcome) {
+	if s.trace == nil {
+		return
+	}
+
+	s.trace.Steps = append(s.trace.Steps, ExplainStep{
+		NodeKey:   n.key,
+		SearchKey: key,
+		LCP:       lcp,
+		Wildcard:  isWildcard,
+		Outcome:   outcome,
+	})
+}
+
+// leaf decides whether the given node – where the search key ended – is a match.
+func (s *search[T]) leaf(n *Node[T], key string, lcp int, isWildcard bool) *Node[T] {
+	if !n.IsLeaf() {
+		s.step(n, key, lcp, isWildcard, OutcomeNotLeaf)
 		return nil
 	}
 
-	return &FoundNode[T]{
-		value:  n.value.value,
-		params: matchParams(n.value.params, key),
+	found := s.acceptValues(n)
+	if found == nil {
+		s.step(n, key, lcp, isWildcard, OutcomeRejected)
+		return nil
 	}
+
+	s.step(n, key, lcp, isWildcard, OutcomeMatched)
+
+	return found
+}
+
+// acceptValues tries the values of the leaf in order: the guarded ones by
+// their insertion, and the one without a guard – if any – last, so it is
+// the fallback of the others. It returns the node of the accepted value.
+func (s *search[T]) acceptValues(n *Node[T]) *Node[T] {
+	pending := true
+
+	for _, v := range n.value.variants {
+		if pending && n.value.meta.guard != nil && n.value.id < v.value.id {
+			pending = false
+
+			if s.accept(n) {
+				return n
+			}
+		}
+
+		if s.accept(v) {
+			return v
+		}
+	}
+
+	if pending && s.accept(n) {
+		return n
+	}
+
+	return nil
 }
 
 // findRec is the main logic for conducting the search in a recursive manner.
 // It looks for match on the given node's level, and calls itself recursively
-// amongs its children, until the search is over.
-func findRec[T storeValue](n *Node[T], key string, isWildcard bool) *Node[T] {
+// amongs its children, until the search is over. A leaf is only returned
+// if the search accepts it, otherwise the search goes on the other branches.
+func findRec[T storeValue](n *Node[T], key string, isWildcard bool, s *search[T]) *Node[T] {
 	if n == nil {
 		return nil
 	}
 
-	// If the current node's key contains curlyStart char,
-	// that means there is a start of wildcard part.
-	if strings.ContainsRune(n.key, curlyStart) {
-		isWildcard = true
+	if !s.budget.spend() {
+		s.step(n, key, 0, isWildcard, OutcomeBudgetExceeded)
+		return nil
+	}
+
+	// The nodes with params – or inside of one – are
+	// matched by the compiled form of their keys.
+	if isWildcard || n.hasParam() {
+		return findWildcardRec(n, key, isWildcard, s)
 	}
 
 	lcp := longestCommonPrefix(n.key, key)
 
-	// If there is nothing in common and it is not wildcard, then we are off.
-	if lcp == 0 && !isWildcard {
+	// If there is nothing in common, then we are off – but
+	// the empty root has nothing in common with any key.
+	if lcp == 0 && n.key != "" {
+		s.step(n, key, lcp, isWildcard, OutcomeNoCommonPrefix)
 		return nil
 	}
 
-	// In case of non wildcard part, normal string comp.
-	if !isWildcard {
-		if key == n.key {
-			return n
-		}
-
-		// If the current node's key is longer than the lcp, no match.
-		if lcp < len(n.key) {
-			return nil
-		}
+	if lcp == len(n.key) && s.paramDepth == 0 {
+		s.longest.record(n, key[lcp:])
+	}
 
-		// Otherwise have to look amongst the children recursively.
-		for _, c := range n.children {
-			if found := findRec(c, key[lcp:], isWildcard); found != nil {
-				return found
-			}
-		}
+	if key == n.key {
+		return s.leaf(n, key, lcp, isWildcard)
+	}
 
+	// If the current node's key is longer than the lcp, no match.
+	if lcp < len(n.key) {
+		s.step(n, key, lcp, isWildcard, OutcomePartialMatch)
 		return nil
 	}
 
-	var (
-		nodeKeyRem   = n.key[lcp:]
-		searchKeyRem = key[lcp:]
-	)
+	s.step(n, key, lcp, isWildcard, OutcomeDescend)
 
-	offset1, offset2, isStillWildcard := getOffsets(nodeKeyRem, searchKeyRem, true)
+	// Otherwise have to look amongst the children recursively.
+	return findChildRec(n, key[lcp:], isWildcard, s)
+}
 
-	// Meaning we didnt shift until the last char, not a full match in this level.
-	if len(nodeKeyRem) != offset1 {
+// findWildcardRec is the part of findRec, that matches the
+// nodes with params, or the nodes inside of a param.
+func findWildcardRec[T storeValue](n *Node[T], key string, isWildcard bool, s *search[T]) *Node[T] {
+	// The wildcard state is carried from the parent, since the node's key
+	// could have a static part before its first param.
+	offset, isStillWildcard, ok := n.matchKey(key, isWildcard)
+
+	// The common prefix is not needed by the matching, only by the trace.
+	lcp := 0
+	if s.trace != nil {
+		lcp = longestCommonPrefix(n.key, key)
+	}
+
+	// Not a full match in this level.
+	if !ok {
+		s.step(n, key, lcp, isWildcard, OutcomePartialMatch)
 		return nil
 	}
 
-	newSearchKey := searchKeyRem[offset2:]
+	newSearchKey := key[offset:]
 
 	// If there is nothing from the original search key
 	// we are on the exact node we were looking for.
 	if newSearchKey == "" {
-		// Only to check if this node is a leaf, or not.
-		if n.IsLeaf() {
-			return n
-		}
-		return nil
+		return s.leaf(n, key, lcp, isWildcard)
 	}
 
+	s.step(n, key, lcp, isWildcard, OutcomeDescend)
+
+	s.paramDepth++
+
 	// Have to continue search on the next level.
-	for _, ch := range n.children {
-		if found := findRec(ch, newSearchKey, isStillWildcard); found != nil {
-			return found
-		}
-	}
+	found := findChildRec(n, newSearchKey, isStillWildcard, s)
 
-	return nil
+	s.paramDepth--
+
+	return found
 }
 
 // getOffsets returns the offset of the first and second given string and whether it is still
 // a wildcard search. These offsets are displaying how far should each string be shifted, how long
-// is the common part including wildcard option.
+// is the common part including wildcard option. It is the byte by byte interpretation of a key,
+// which the lookups replaced by the compiled keys; it is the reference of matchKey.
 func getOffsets(storedKey, searchKey string, isWildcard bool) (int, int, bool) {
 	var (
 		i = 0
@@ -469,6 +1524,19 @@ func getOffsets(storedKey, searchKey string, isWildcard bool) (int, int, bool) {
 			break
 		}
 
+		// An escaped char is compared as a plain one, alongside
+		// with its escape char – the search key is escaped too.
+		if storedKey[i] == escapeChar && !isWildcard {
+			if j+1 >= searchKeyLen || storedKey[i+1] != searchKey[j+1] || searchKey[j] != escapeChar {
+				break
+			}
+
+			i += 2
+			j += 2
+
+			continue
+		}
+
 		if storedKey[i] == curlyStart {
 			isWildcard = true
 			i++
@@ -480,6 +1548,14 @@ func getOffsets(storedKey, searchKey string, isWildcard bool) (int, int, bool) {
 		if storedKey[i] == curlyEnd {
 			isWildcard = false
 
+			// A catch-all param consumes the rest of the search key.
+			if strings.HasSuffix(storedKey[:i], catchAllSuffix) {
+				j = searchKeyLen
+				i++
+
+				continue
+			}
+
 			cSearchRem := searchKey[j:]
 
 			nextSlashIdx := strings.IndexRune(cSearchRem, slash)
@@ -524,30 +1600,36 @@ func (t *Tree[T]) FindLongestMatch(key string) *FoundNode[T] {
 		return nil
 	}
 
+	return t.observe(key, t.findLongestMatch)
+}
+
+// findLongestMatch is the unobserved version of FindLongestMatch.
+func (t *Tree[T]) findLongestMatch(key string) *FoundNode[T] {
 	if key == "" {
 		return nil
 	}
 
-	n := findLongestMatchRec(t.root, key)
+	if t.runeMatching && !utf8.ValidString(key) {
+		return nil
+	}
+
+	n := t.cachedLongestMatch(t.searchKey(key))
 
 	if n == nil || n.value == nil {
 		return nil
 	}
 
-	return &FoundNode[T]{
-		value:  n.value.value,
-		params: make(matchedParams),
-	}
+	return t.newLongestMatch(n, key)
 }
 
-func findLongestMatchRec[T storeValue](n *Node[T], key string) *Node[T] {
+func findLongestMatchRec[T storeValue](n *Node[T], key string, boundary bool) *Node[T] {
 	if n == nil {
 		return nil
 	}
 
 	lcp := longestCommonPrefix(n.key, key)
 
-	if lcp == 0 {
+	if lcp == 0 && n.key != "" {
 		return nil
 	}
 
@@ -556,30 +1638,47 @@ func findLongestMatchRec[T storeValue](n *Node[T], key string) *Node[T] {
 	}
 
 	for _, ch := range n.children {
-		if node := findLongestMatchRec(ch, key[lcp:]); node != nil {
+		if node := findLongestMatchRec(ch, key[lcp:], boundary); node != nil {
 			return node
 		}
 	}
 
-	if !n.IsLeaf() {
+	if !n.IsLeaf() || n.value.meta.inactive() {
+		return nil
+	}
+
+	if boundary && !isSegmentBoundary(n.key, key[lcp:]) {
 		return nil
 	}
 
 	return n
 }
 
+// isSegmentBoundary reports whether the matched prefix – ending with
+// the given node key – ends at a segment boundary of the searched key.
+func isSegmentBoundary(nodeKey, rem string) bool {
+	return rem == "" || rem[0] == slash || nodeKey[len(nodeKey)-1] == slash
+}
+
 // GetAllLeaf returns all the stored leafs.
 func (t *Tree[T]) GetAllLeaf() []*Node[T] {
 	if err := checkTree(t); err != nil {
 		return nil
 	}
 
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
 	return getAllLeafRec(t.root)
 }
 
 func getAllLeafRec[T storeValue](n *Node[T]) []*Node[T] {
 	arr := make([]*Node[T], 0)
 
+	if n == nil {
+		return arr
+	}
+
 	for _, c := range n.children {
 		chArr := getAllLeafRec(c)
 
@@ -602,6 +1701,9 @@ func (t *Tree[T]) GetByPredicate(fn predicateFunction[T]) *Node[T] {
 		return nil
 	}
 
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
 	return getByPredicateRec(t.root, fn)
 }
 
@@ -625,7 +1727,7 @@ func getByPredicateRec[T storeValue](n *Node[T], fn predicateFunction[T]) *Node[
 
 func getPathParams(v string) []paramInfo {
 	var (
-		paramCount = strings.Count(v, string(curlyStart))
+		paramCount = countUnescaped(v, curlyStart)
 
 		params   = make([]paramInfo, paramCount)
 		splitted = strings.Split(v, string(slash))
@@ -634,7 +1736,7 @@ func getPathParams(v string) []paramInfo {
 	var counter = 0
 
 	for i, el := range splitted {
-		if !strings.ContainsRune(el, curlyStart) {
+		if indexUnescaped(el, curlyStart) == -1 {
 			continue
 		}
 
@@ -644,9 +1746,13 @@ func getPathParams(v string) []paramInfo {
 			continue
 		}
 
+		name, matcher, catchAll := parseParam(el[1 : l-1])
+
 		params[counter] = paramInfo{
-			key: el[1 : l-1],
-			pos: uint8(i),
+			key:      name,
+			matcher:  matcher,
+			pos:      i,
+			catchAll: catchAll,
 		}
 		counter++
 	}
@@ -655,20 +1761,32 @@ func getPathParams(v string) []paramInfo {
 }
 
 func matchParams(params []paramInfo, v string) matchedParams {
+	return matchParamsIn(params, strings.Split(v, string(slash)), 0, slash)
+}
+
+// matchParamsIn matches the params in the given segments. The position of
+// the params is shifted by the number of the segments missing from the start.
+// The segments captured by a catch-all param are joined by the delimiter.
+func matchParamsIn(params []paramInfo, spl []string, shift int, delimiter byte) matchedParams {
 	var (
-		mp  = make(matchedParams)
-		spl = strings.Split(v, string(slash))
+		mp = make(matchedParams)
 
 		l = len(spl)
 	)
 
 	for _, pi := range params {
-		var pos int = int(pi.pos)
+		pos := pi.pos - shift
 
 		if pos >= l {
 			continue
 		}
 
+		// A catch-all param captures all the remaining segments.
+		if pi.catchAll {
+			mp[pi.key] = strings.Join(spl[pos:], string(delimiter))
+			continue
+		}
+
 		mp[pi.key] = spl[pos]
 	}
 
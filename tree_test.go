@@ -2,8 +2,13 @@ package rtree
 
 import (
 	"errors"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 type Route struct {
@@ -146,7 +151,7 @@ func TestTreeInsert(t *testing.T) {
 			name:    "error if the tree is <nil>",
 			getTree: func(t *testing.T) *Tree[*Route] { return nil },
 			input:   "",
-			err:     errTreeIsNil,
+			err:     ErrTreeIsNil,
 		},
 		{
 			name: "error if given url (key) is empty",
@@ -154,7 +159,7 @@ func TestTreeInsert(t *testing.T) {
 				return New[*Route]()
 			},
 			input: "",
-			err:   errKeyIsEmpty,
+			err:   ErrKeyIsEmpty,
 		},
 		{
 			name: "error if given url (key) is not starting with a slash",
@@ -172,6 +177,14 @@ func TestTreeInsert(t *testing.T) {
 			input: "/foo/",
 			err:   errPresentSlashSuffix,
 		},
+		{
+			name: "error if given url (key) has a param without name",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				return New[*Route]()
+			},
+			input: "/api/{}/list",
+			err:   errEmptyParamName,
+		},
 		{
 			name: "no error if insertion was successfull (empty tree)",
 			getTree: func(t *testing.T) *Tree[*Route] {
@@ -484,6 +497,24 @@ func TestTreeFind(t *testing.T) {
 			searchKey: "/api/products/delete/1",
 			isExists:  true,
 		},
+		{
+			name: "wildcard search - static part before param in the same node (no match)",
+			getTree: func(t *testing.T) *Tree[*Route] {
+				tree := New[*Route]()
+
+				if err := tree.Insert("/api/users/{id}", getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				if err := tree.Insert("/api/fallback", getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+
+				return tree
+			},
+			searchKey: "/api/products/1",
+			isExists:  false,
+		},
 	}
 
 	for _, tc := range tt {
@@ -503,6 +534,88 @@ func TestTreeFind(t *testing.T) {
 	}
 }
 
+func TestTreeFindE(t *testing.T) {
+	type testCase struct {
+		name            string
+		getTree         func(t *testing.T) *Tree[*Route]
+		searchKey       string
+		expectedPattern string
+		expectedErr     error
+	}
+
+	getTree := func(opts ...OptionFunc[*Route]) func(t *testing.T) *Tree[*Route] {
+		return func(t *testing.T) *Tree[*Route] {
+			tree := New(opts...)
+
+			if err := tree.Insert("/api/users/{id}", getRoute()); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			return tree
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:        "error, if tree is <nil>",
+			getTree:     func(t *testing.T) *Tree[*Route] { return nil },
+			searchKey:   "/foo",
+			expectedErr: ErrTreeIsNil,
+		},
+		{
+			name:        "not found, if tree is empty",
+			getTree:     func(t *testing.T) *Tree[*Route] { return New[*Route]() },
+			searchKey:   "/foo",
+			expectedErr: ErrNotFound,
+		},
+		{
+			name:        "error, if the search key is empty",
+			getTree:     getTree(),
+			searchKey:   "",
+			expectedErr: ErrKeyIsEmpty,
+		},
+		{
+			name:        "error, if the search key is not valid UTF-8",
+			getTree:     getTree(WithRuneMatching[*Route]()),
+			searchKey:   "/api/users/\xff",
+			expectedErr: errInvalidUTF8,
+		},
+		{
+			name:        "not found",
+			getTree:     getTree(),
+			searchKey:   "/api/posts/1",
+			expectedErr: ErrNotFound,
+		},
+		{
+			name:            "found",
+			getTree:         getTree(),
+			searchKey:       "/api/users/1",
+			expectedPattern: "/api/users/{id}",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fn, err := tc.getTree(t).FindE(tc.searchKey)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if tc.expectedErr != nil {
+				if fn != nil {
+					t.Errorf("expected no match; got: %s\n", fn.GetPattern())
+				}
+				return
+			}
+
+			if fn == nil || fn.GetPattern() != tc.expectedPattern {
+				t.Errorf("expected pattern: %s; got: %v\n", tc.expectedPattern, fn)
+			}
+		})
+	}
+}
+
 func TestCheckPathParams(t *testing.T) {
 	type testCase struct {
 		name  string
@@ -556,6 +669,16 @@ func TestCheckPathParams(t *testing.T) {
 			input: "/{foo}/bar/baz}",
 			err:   errBadPathParamSyntax,
 		},
+		{
+			name:  "error if the name of the param is empty",
+			input: "/api/{}/list",
+			err:   errEmptyParamName,
+		},
+		{
+			name:  "error if the name of the param is empty, but it has a matcher",
+			input: "/api/{:lang}/list",
+			err:   errEmptyParamName,
+		},
 	}
 
 	for _, tc := range tt {
@@ -885,6 +1008,84 @@ func TestGetPathParams(t *testing.T) {
 	}
 }
 
+// longKey returns a key of the given number of segments, where
+// the segments at the given positions are replaced by params.
+func longKey(segments int, params map[int]string) string {
+	var sb strings.Builder
+
+	for i := 1; i <= segments; i++ {
+		sb.WriteByte('/')
+
+		if name, exists := params[i]; exists {
+			sb.WriteString("{" + name + "}")
+			continue
+		}
+
+		sb.WriteString("s" + strconv.Itoa(i))
+	}
+
+	return sb.String()
+}
+
+func TestLongKeys(t *testing.T) {
+	type testCase struct {
+		name     string
+		segments int
+		params   map[int]string
+	}
+
+	tt := []testCase{
+		{
+			name:     "params around the former 255 limit",
+			segments: 300,
+			params:   map[int]string{1: "first", 255: "a", 256: "b", 257: "c", 300: "last"},
+		},
+		{
+			name:     "param at a position wrapping to an earlier one",
+			segments: 520,
+			params:   map[int]string{3: "early", 259: "late", 515: "later"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			pattern := longKey(tc.segments, tc.params)
+
+			if err := tree.Insert(pattern, &Route{}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+
+			values := make(map[int]string, len(tc.params))
+			expected := make(matchedParams, len(tc.params))
+
+			for pos, name := range tc.params {
+				values[pos] = "v" + strconv.Itoa(pos)
+				expected[name] = "v" + strconv.Itoa(pos)
+			}
+
+			// The values are written in place of the param names.
+			key := strings.NewReplacer("{", "", "}", "").Replace(longKey(tc.segments, values))
+
+			fn := tree.Find(key)
+			if fn == nil {
+				t.Fatalf("expected match; got <nil>\n")
+			}
+
+			if !reflect.DeepEqual(fn.GetParams(), expected) {
+				t.Errorf("expected params: %v; got: %v\n", expected, fn.GetParams())
+			}
+
+			for _, pi := range tree.GetAllLeaf()[0].value.ParamInfos() {
+				if tc.params[pi.Position] != pi.Name {
+					t.Errorf("expected param %s at position %d\n", pi.Name, pi.Position)
+				}
+			}
+		})
+	}
+}
+
 func TestMatchParams(t *testing.T) {
 	type testCase struct {
 		name   string
@@ -972,3 +1173,524 @@ func TestCheckUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeAccessors(t *testing.T) {
+	tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+	if err := tree.Insert("/foo/{id}", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/foo/{lang:lang}/bar", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if got := tree.root.Key(); got != "/foo/{" {
+		t.Errorf("expected key: %s; got: %s\n", "/foo/{", got)
+	}
+
+	children := tree.root.Children()
+
+	if len(children) != 2 {
+		t.Fatalf("expected children count: %d; got: %d\n", 2, len(children))
+	}
+
+	children[0] = nil
+
+	if tree.root.children[0] == nil {
+		t.Error("expected children to be a copy")
+	}
+
+	expected := []ParamInfo{
+		{Name: "lang", Matcher: "lang", Position: 2},
+	}
+
+	// The more specific wildcard sibling comes first.
+	if got := tree.root.children[0].GetValue().ParamInfos(); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected param infos: %v; got: %v\n", expected, got)
+	}
+}
+
+func TestChildOrdering(t *testing.T) {
+	type testCase struct {
+		name         string
+		routes       []string
+		expectedKeys []string
+	}
+
+	tt := []testCase{
+		{
+			name:         "static children before the wildcard",
+			routes:       []string{"/api/{id}", "/api/foo", "/api/bar"},
+			expectedKeys: []string{"foo", "bar", "{id}"},
+		},
+		{
+			name:         "static children before the wildcard after split",
+			routes:       []string{"/api/foo", "/api/{id}", "/api/bar"},
+			expectedKeys: []string{"foo", "bar", "{id}"},
+		},
+		{
+			name:         "static children before the wildcard on split",
+			routes:       []string{"/api/{id}", "/api/{id}/foo", "/foo"},
+			expectedKeys: []string{"api/{id}", "foo"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			for _, r := range tc.routes {
+				if err := tree.Insert(r, &Route{name: r}); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			got := make([]string, 0)
+
+			for _, ch := range tree.root.children {
+				got = append(got, ch.key)
+			}
+
+			if !reflect.DeepEqual(tc.expectedKeys, got) {
+				t.Errorf("expected children: %v; got: %v\n", tc.expectedKeys, got)
+			}
+		})
+	}
+}
+
+func TestFindMostSpecificWins(t *testing.T) {
+	for _, routes := range [][]string{
+		{"/api/{resource}/get", "/api/products/get"},
+		{"/api/products/get", "/api/{resource}/get"},
+	} {
+		tree := New[*Route]()
+
+		for _, r := range routes {
+			if err := tree.Insert(r, &Route{name: r}); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		if got := tree.Find("/api/products/get").GetValue().name; got != "/api/products/get" {
+			t.Errorf("expected static route to win; got: %s\n", got)
+		}
+
+		if got := tree.Find("/api/users/get").GetValue().name; got != "/api/{resource}/get" {
+			t.Errorf("expected wildcard route to match; got: %s\n", got)
+		}
+	}
+}
+
+func TestPrecomputedParamIndex(t *testing.T) {
+	tree := New[*Route]()
+
+	routes := []string{
+		"/api/{resource}/get",
+		"/api/products/get",
+		"/api/{resource}/delete/{id}",
+		"/api/{res}",
+		"/api",
+		"/{id}",
+	}
+
+	for _, r := range routes {
+		if err := tree.Insert(r, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	err := tree.Walk(func(n *Node[*Route]) WalkVerdict {
+		if expected := strings.IndexByte(n.key, curlyStart); n.paramIdx != expected {
+			t.Errorf("%q: expected param index: %d; got: %d\n", n.key, expected, n.paramIdx)
+		}
+		return Continue
+	})
+
+	if err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+}
+
+func TestTreeDelete(t *testing.T) {
+	type testCase struct {
+		name        string
+		routes      []string
+		deleteKey   string
+		err         error
+		expectFound []string
+		expectMiss  []string
+	}
+
+	tt := []testCase{
+		{
+			name:      "error if the key is not stored",
+			routes:    []string{"/foo/bar", "/foo/baz"},
+			deleteKey: "/foo",
+			err:       errKeyNotFound,
+		},
+		{
+			name:      "error if the key is empty",
+			routes:    []string{"/foo"},
+			deleteKey: "",
+			err:       ErrKeyIsEmpty,
+		},
+		{
+			name:        "deleting the only route",
+			routes:      []string{"/foo"},
+			deleteKey:   "/foo",
+			expectMiss:  []string{"/foo"},
+			expectFound: []string{},
+		},
+		{
+			name:        "deleting a leaf with siblings",
+			routes:      []string{"/foo/bar", "/foo/baz", "/foo/qux"},
+			deleteKey:   "/foo/bar",
+			expectFound: []string{"/foo/baz", "/foo/qux"},
+			expectMiss:  []string{"/foo/bar"},
+		},
+		{
+			name:        "deleting an inner leaf",
+			routes:      []string{"/foo/bar", "/foo/baz", "/foo"},
+			deleteKey:   "/foo",
+			expectFound: []string{"/foo/bar", "/foo/baz"},
+			expectMiss:  []string{"/foo"},
+		},
+		{
+			name:        "deleting a wildcard route by its pattern",
+			routes:      []string{"/api/{id}", "/api/{id}/bar", "/api/foo"},
+			deleteKey:   "/api/{id}",
+			expectFound: []string{"/api/1/bar", "/api/foo"},
+			expectMiss:  []string{"/api/1"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := New[*Route]()
+
+			for _, r := range tc.routes {
+				if err := tree.Insert(r, getRoute()); err != nil {
+					t.Fatalf("not expected error, but got: %v\n", err)
+				}
+			}
+
+			if err := tree.Delete(tc.deleteKey); !errors.Is(err, tc.err) {
+				t.Errorf("expected error: %v; got: %v\n", tc.err, err)
+			}
+
+			for _, k := range tc.expectFound {
+				if tree.Find(k) == nil {
+					t.Errorf("expected to find %s, but got <nil>", k)
+				}
+			}
+
+			for _, k := range tc.expectMiss {
+				if tree.Find(k) != nil {
+					t.Errorf("expected not to find %s, but got route", k)
+				}
+			}
+		})
+	}
+}
+
+func TestTreeDeleteStructure(t *testing.T) {
+	build := func(routes ...string) *Tree[*Route] {
+		tree := New[*Route]()
+
+		for _, r := range routes {
+			if err := tree.Insert(r, getRoute()); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		return tree
+	}
+
+	keys := func(tree *Tree[*Route]) []string {
+		got := make([]string, 0)
+
+		_ = tree.Walk(func(n *Node[*Route]) WalkVerdict {
+			got = append(got, n.key)
+			return Continue
+		})
+
+		return got
+	}
+
+	tree := build("/foo/bar", "/foo/baz", "/foo")
+
+	if err := tree.Delete("/foo/baz"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Delete("/foo"); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if expected, got := keys(build("/foo/bar")), keys(tree); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected nodes: %v; got: %v\n", expected, got)
+	}
+
+	if tree.RouteByID(2) != nil {
+		t.Error("expected deleted route not to be found by its id")
+	}
+}
+
+// treeShape returns the full keys of the nodes – marking the leaves –, in
+// sorted order, so two trees of the same routes have the same shape, no
+// matter the order of their static siblings.
+func treeShape[T storeValue](t *testing.T, tree *Tree[T]) []string {
+	t.Helper()
+
+	shape := make([]string, 0)
+
+	var rec func(n *Node[T], prefix string)
+
+	rec = func(n *Node[T], prefix string) {
+		full := prefix + n.key
+
+		if n.IsLeaf() {
+			shape = append(shape, full+" *")
+		} else {
+			shape = append(shape, full)
+
+			// An inner node without a second child should have been merged.
+			if len(n.children) < 2 {
+				t.Errorf("inner node %q has %d children\n", full, len(n.children))
+			}
+		}
+
+		for i, ch := range n.children {
+			if i > 0 && precedes(ch.key, n.children[i-1].key) {
+				t.Errorf("child %q of %q is out of order\n", ch.key, full)
+			}
+
+			rec(ch, full)
+		}
+	}
+
+	if tree.root != nil {
+		rec(tree.root, "")
+	}
+
+	sort.Strings(shape)
+
+	return shape
+}
+
+// TestDeleteMatchesFreshTree deletes and inserts routes at random, and
+// checks, that the tree has the same shape, as a tree built freshly of
+// the remaining routes, so the deletions fix up the tree locally.
+func TestDeleteMatchesFreshTree(t *testing.T) {
+	pools := map[string]struct {
+		opts     []OptionFunc[*Route]
+		patterns []string
+	}{
+		"urls": {
+			patterns: []string{
+				"/", "/a", "/ab", "/abc", "/a/b", "/a/{id}", "/a/{id}/c", "/a/{ident}",
+				"/a/{id:digits}", "/a/b/{path...}", "/a/v{version}", "/b/{x}/{y}", "/b/{x}/z",
+				"/users", "/users/{id}", "/users/{id}/posts", "/users/me", "/user",
+				"/files/{path...}", "/files/readme", "/\\{escaped\\}",
+			},
+		},
+		"object keys": {
+			opts: []OptionFunc[*Route]{WithObjectKeys[*Route]()},
+			patterns: []string{
+				"a", "ab", "b", "bucket/a", "bucket/{key}", "bucket/{key...}", "x/y", "x", "{all...}",
+			},
+		},
+	}
+
+	digits := SegmentMatcherFunc(func(segment string) (bool, string) { return true, segment })
+
+	for name, pool := range pools {
+		t.Run(name, func(t *testing.T) {
+			newTree := func() *Tree[*Route] {
+				return New(append(pool.opts, WithSegmentMatcher[*Route]("digits", digits))...)
+			}
+
+			for seed := int64(0); seed < 50; seed++ {
+				var (
+					r       = rand.New(rand.NewSource(seed))
+					tree    = newTree()
+					present = make(map[string]bool)
+				)
+
+				for step := 0; step < 200; step++ {
+					p := pool.patterns[r.Intn(len(pool.patterns))]
+
+					if present[p] {
+						if err := tree.Delete(p); err != nil {
+							t.Fatalf("seed %d: not expected error on delete %s, but got: %v\n", seed, p, err)
+						}
+
+						delete(present, p)
+					} else {
+						if err := tree.Insert(p, &Route{name: p}); err != nil {
+							t.Fatalf("seed %d: not expected error on insert %s, but got: %v\n", seed, p, err)
+						}
+
+						present[p] = true
+					}
+
+					if step%20 != 0 {
+						continue
+					}
+
+					var (
+						fresh     = newTree()
+						remaining = make([]string, 0, len(present))
+					)
+
+					for p := range present {
+						remaining = append(remaining, p)
+					}
+
+					sort.Strings(remaining)
+					r.Shuffle(len(remaining), func(i, j int) { remaining[i], remaining[j] = remaining[j], remaining[i] })
+
+					for _, p := range remaining {
+						if err := fresh.Insert(p, &Route{name: p}); err != nil {
+							t.Fatalf("not expected error, but got: %v\n", err)
+						}
+					}
+
+					if expected, got := treeShape(t, fresh), treeShape(t, tree); !reflect.DeepEqual(expected, got) {
+						t.Fatalf("seed %d, step %d: expected shape:\n%v\ngot:\n%v\n", seed, step, expected, got)
+					}
+
+					for _, key := range pool.patterns {
+						if exp, got := fresh.Find(key), tree.Find(key); (exp == nil) != (got == nil) || exp != nil && exp.GetPattern() != got.GetPattern() {
+							t.Fatalf("seed %d, step %d: %s: expected: %v; got: %v\n", seed, step, key, exp, got)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTreeUpsert(t *testing.T) {
+	tree := New[*Route]()
+
+	if err := tree.Upsert("/api/users/{id}", &Route{name: "first"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	id := tree.Find("/api/users/1").RouteID()
+
+	if err := tree.Upsert("/api/users/{id}", &Route{name: "second"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	fn := tree.Find("/api/users/1")
+
+	if fn.GetValue().name != "second" {
+		t.Errorf("expected value: %s; got: %s\n", "second", fn.GetValue().name)
+	}
+
+	if fn.RouteID() != id {
+		t.Errorf("expected route id: %d; got: %d\n", id, fn.RouteID())
+	}
+
+	if err := tree.Upsert("api", getRoute()); !errors.Is(err, errMissingSlashPrefix) {
+		t.Errorf("expected error: %v; got: %v\n", errMissingSlashPrefix, err)
+	}
+}
+
+func TestNilAndZeroValueTree(t *testing.T) {
+	var (
+		nilTree  *Tree[*Route]
+		zeroTree Tree[*Route]
+	)
+
+	walkFn := func(*Node[*Route]) WalkVerdict { return Continue }
+
+	tt := []struct {
+		name        string
+		tree        *Tree[*Route]
+		expectedErr error
+		notFoundErr error
+	}{
+		{
+			name:        "nil tree",
+			tree:        nilTree,
+			expectedErr: ErrTreeIsNil,
+			notFoundErr: ErrTreeIsNil,
+		},
+		{
+			name:        "zero value tree",
+			tree:        &zeroTree,
+			notFoundErr: errKeyNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := tc.tree
+
+			if fn := tree.Find("/api/users/1"); fn != nil {
+				t.Errorf("expected no match; got: %s\n", fn.GetPattern())
+			}
+
+			if fn := tree.FindLongestMatch("/api/users/1"); fn != nil {
+				t.Errorf("expected no longest match; got: %s\n", fn.GetPattern())
+			}
+
+			if h := tree.Resolve("/api/users/1"); h != nil {
+				t.Errorf("expected no handle; got: %s\n", h.Pattern())
+			}
+
+			if leaves := tree.GetAllLeaf(); len(leaves) != 0 {
+				t.Errorf("expected no leaves; got: %d\n", len(leaves))
+			}
+
+			if n := tree.GetByPredicate(func(*Node[*Route]) bool { return true }); n != nil {
+				t.Errorf("expected no node; got: %q\n", n.key)
+			}
+
+			if dump := tree.Dump(); dump != "" {
+				t.Errorf("expected empty dump; got: %q\n", dump)
+			}
+
+			if err := tree.Walk(walkFn); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected Walk error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if err := tree.WalkPrefix("/api", walkFn); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected WalkPrefix error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if err := tree.WalkBFS(func(*Node[*Route], int) WalkVerdict { return Continue }); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected WalkBFS error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if _, err := tree.PruneExpired(time.Now()); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected PruneExpired error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if err := tree.Delete("/api/users/{id}"); !errors.Is(err, tc.notFoundErr) {
+				t.Errorf("expected Delete error: %v; got: %v\n", tc.notFoundErr, err)
+			}
+
+			if err := tree.DeleteCascade("/api/users/{id}"); !errors.Is(err, tc.notFoundErr) {
+				t.Errorf("expected DeleteCascade error: %v; got: %v\n", tc.notFoundErr, err)
+			}
+
+			if err := tree.Insert("/api/users/{id}", getRoute()); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected Insert error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if tc.expectedErr != nil {
+				return
+			}
+
+			// The zero value is usable once the first route is inserted.
+			if fn := tree.Find("/api/users/1"); fn == nil || fn.GetPattern() != "/api/users/{id}" {
+				t.Errorf("expected the inserted route to be found\n")
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package rtree
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+var (
+	errUnknownMatcher = fmt.Errorf("[rtree %s]: matcher is not registered", version)
+	errBadParamName   = fmt.Errorf("[rtree %s]: param name is refused", version)
+)
+
+// WithParamNameValidator sets the naming policy of the params – eg. lower
+// case only, or no names reserved for the query or the headers. It is called
+// with every param name of the patterns given to Insert – and the other ways
+// of storing routes –, and to ValidatePattern. A refused name fails the
+// insertion with the returned error. The names are in the stored form, so
+// the params of the topic syntaxes are named by their levels.
+func WithParamNameValidator[T storeValue](validator func(name string) error) OptionFunc[T] {
+	return func(t *Tree[T]) {
+		t.paramNameValidator = validator
+	}
+}
+
+// ValidatePattern checks the given pattern the same way as Insert does –
+// its syntax and the registration of its matchers –, without storing it.
+func (t *Tree[T]) ValidatePattern(pattern string) error {
+	_, err := t.checkPattern(pattern)
+
+	return err
+}
+
+// ValidatePatterns checks all the given patterns the same way as
+// ValidatePattern, and returns the MultiError of the refused ones –
+// each failure prefixed by its pattern –, or nil if all of them are valid.
+func (t *Tree[T]) ValidatePatterns(patterns []string) error {
+	errs := make([]error, 0)
+
+	for _, pattern := range patterns {
+		if err := t.ValidatePattern(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pattern, err))
+		}
+	}
+
+	return newMultiError(errs)
+}
+
+// checkPattern checks the given pattern, and returns its stored form.
+func (t *Tree[T]) checkPattern(key string) (string, error) {
+	if t == nil {
+		return "", ErrTreeIsNil
+	}
+
+	if key == "" {
+		return "", ErrKeyIsEmpty
+	}
+
+	key = t.normalizePattern(key)
+
+	if err := t.checkStoredPattern(key); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// checkStoredPattern checks the pattern already in its stored form,
+// eg. the patterns of the snapshots and of the store of the tree.
+func (t *Tree[T]) checkStoredPattern(pattern string) error {
+	if pattern == "" {
+		return ErrKeyIsEmpty
+	}
+
+	if err := t.checkKey(pattern); err != nil {
+		return err
+	}
+
+	if t.runeMatching && !utf8.ValidString(pattern) {
+		return errInvalidUTF8
+	}
+
+	if err := t.checkMatchers(pattern); err != nil {
+		return err
+	}
+
+	return t.checkParamNames(pattern)
+}
+
+// checkParamNames checks the param names of the
+// pattern by the validator of the tree, if it has one.
+func (t *Tree[T]) checkParamNames(pattern string) error {
+	if t.paramNameValidator == nil {
+		return nil
+	}
+
+	for _, pi := range getPathParams(pattern) {
+		if err := t.paramNameValidator(pi.key); err != nil {
+			return fmt.Errorf("%w: %s at position %d: %w", errBadParamName, pi.key, pi.pos, err)
+		}
+	}
+
+	return nil
+}
+
+// checkMatchers checks, that every matcher referenced by the
+// params of the pattern is registered in the tree. A pattern like
+// /users/{id:unknown} would never match, so it is rather refused.
+func (t *Tree[T]) checkMatchers(pattern string) error {
+	for _, pi := range getPathParams(pattern) {
+		if pi.matcher == "" {
+			continue
+		}
+
+		if _, exists := t.matchers[pi.matcher]; !exists {
+			return fmt.Errorf("%w: %s of param %s at position %d", errUnknownMatcher, pi.matcher, pi.key, pi.pos)
+		}
+	}
+
+	return nil
+}
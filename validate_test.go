@@ -0,0 +1,142 @@
+package rtree
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePattern(t *testing.T) {
+	type testCase struct {
+		name            string
+		pattern         string
+		expectedErr     error
+		expectedMessage string
+	}
+
+	tt := []testCase{
+		{
+			name:        "valid pattern",
+			pattern:     "/{lang:lang}/docs/{id}",
+			expectedErr: nil,
+		},
+		{
+			name:        "empty pattern",
+			pattern:     "",
+			expectedErr: ErrKeyIsEmpty,
+		},
+		{
+			name:        "bad syntax",
+			pattern:     "/docs/{id",
+			expectedErr: errBadPathParamSyntax,
+		},
+		{
+			name:            "unknown matcher",
+			pattern:         "/docs/{lang:lang}/{id:unknown}",
+			expectedErr:     errUnknownMatcher,
+			expectedMessage: "unknown of param id at position 3",
+		},
+	}
+
+	tree := New(WithSegmentMatcher[*Route]("lang", langMatcher))
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tree.ValidatePattern(tc.pattern)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if !strings.Contains(errMessage(err), tc.expectedMessage) {
+				t.Errorf("expected error to contain: %s; got: %v\n", tc.expectedMessage, err)
+			}
+
+			// Insert must agree with the validation.
+			if err := tree.Insert(tc.pattern, getRoute()); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected insert error: %v; got: %v\n", tc.expectedErr, err)
+			}
+		})
+	}
+
+	if got := tree.Find("/docs/en/1"); got != nil {
+		t.Errorf("expected the refused pattern not to be stored; got: %s\n", got.GetPattern())
+	}
+}
+
+func TestWithParamNameValidator(t *testing.T) {
+	type testCase struct {
+		name            string
+		pattern         string
+		expectedErr     error
+		expectedMessage string
+	}
+
+	errReserved := errors.New("reserved name")
+
+	validator := func(name string) error {
+		switch {
+		case name == "method":
+			return errReserved
+		case strings.ToLower(name) != name:
+			return errors.New("not lower case")
+		}
+
+		return nil
+	}
+
+	tt := []testCase{
+		{
+			name:    "accepted names",
+			pattern: "/users/{id}/files/{path...}",
+		},
+		{
+			name:    "static segments are not checked",
+			pattern: "/method/Users",
+		},
+		{
+			name:            "reserved name",
+			pattern:         "/users/{id}/{method}",
+			expectedErr:     errReserved,
+			expectedMessage: "method at position 3",
+		},
+		{
+			name:            "catch-all name",
+			pattern:         "/files/{Path...}",
+			expectedErr:     errBadParamName,
+			expectedMessage: "Path at position 2: not lower case",
+		},
+	}
+
+	tree := New(WithParamNameValidator[*Route](validator))
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tree.ValidatePattern(tc.pattern)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v; got: %v\n", tc.expectedErr, err)
+			}
+
+			if tc.expectedErr != nil && !errors.Is(err, errBadParamName) {
+				t.Errorf("expected error: %v; got: %v\n", errBadParamName, err)
+			}
+
+			if !strings.Contains(errMessage(err), tc.expectedMessage) {
+				t.Errorf("expected error to contain: %s; got: %v\n", tc.expectedMessage, err)
+			}
+
+			if err := tree.Insert(tc.pattern, getRoute()); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected insert error: %v; got: %v\n", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func errMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,232 @@
+package rtree
+
+import "fmt"
+
+// ErrConcurrentModification is returned by Walk, if the tree
+// was mutated while the walk was still in progress.
+var ErrConcurrentModification = fmt.Errorf("[rtree %s]: tree was modified during iteration", version)
+
+// WalkVerdict tells Walk how to go on after visiting a node.
+type WalkVerdict int
+
+const (
+	// Continue goes on with the children of the node.
+	Continue WalkVerdict = iota
+	// SkipSubtree skips the children of the node.
+	SkipSubtree
+	// Stop ends the whole walk.
+	Stop
+)
+
+// WalkFunc is called on every visited node during Walk,
+// and its verdict controls how the walk goes on.
+type WalkFunc[T storeValue] func(n *Node[T]) WalkVerdict
+
+// Generation returns the number of mutations done on the tree.
+// It could be used to detect whether the tree has changed
+// between two points in time.
+func (t *Tree[T]) Generation() uint64 {
+	if t == nil {
+		return 0
+	}
+
+	return t.generation.Load()
+}
+
+// Walk visits all the nodes of the tree in a depth-first, pre-order
+// manner, pruned by the verdicts of the callback.
+//
+// The walk is done over a snapshot of the structure, copied under the
+// read lock, so a concurrent insert or delete never shows a torn tree,
+// and the callback itself could mutate the tree without a deadlock. The
+// visited nodes are the copies, which share the stored values with the
+// tree. Since the snapshot is stale after a mutation, the walk stops
+// with ErrConcurrentModification, once it notices one.
+func (t *Tree[T]) Walk(fn WalkFunc[T]) error {
+	if err := checkTree(t); err != nil {
+		return err
+	}
+
+	root, gen := t.walkSnapshot(func(root *Node[T]) *Node[T] { return root })
+	if root == nil {
+		return nil
+	}
+
+	_, err := walkRec(root, gen, t, fn)
+
+	return err
+}
+
+// walkSnapshot returns the copy of the subtree chosen by pick – or nil –,
+// and the generation of the tree it was copied at.
+func (t *Tree[T]) walkSnapshot(pick func(root *Node[T]) *Node[T]) (*Node[T], uint64) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	n := pick(t.root)
+	if n == nil {
+		return nil, t.Generation()
+	}
+
+	return copySubtree(n), t.Generation()
+}
+
+// copySubtree returns the copy of the structure of the subtree.
+// The keys, the compiled keys and the child indexes are immutable,
+// so they are shared.
+func copySubtree[T storeValue](n *Node[T]) *Node[T] {
+	c := &Node[T]{
+		key:      n.key,
+		value:    n.value,
+		paramIdx: n.paramIdx,
+		ops:      n.ops,
+		index:    n.index,
+	}
+
+	if len(n.children) > 0 {
+		c.children = make([]*Node[T], len(n.children))
+
+		for i, ch := range n.children {
+			c.children[i] = copySubtree(ch)
+		}
+	}
+
+	return c
+}
+
+// WalkPrefix is the same as Walk, but it only visits the nodes, whose
+// full key starts with the given prefix, eg. the routes of a service under
+// /api/users. It descends directly to the subtree of the prefix, whose top
+// node may hold more than the rest of the prefix. The prefix is normalized
+// the same way as the inserted patterns, so it could end inside of a param.
+func (t *Tree[T]) WalkPrefix(prefix string, fn WalkFunc[T]) error {
+	if err := checkTree(t); err != nil {
+		return err
+	}
+
+	prefix = t.normalizePattern(prefix)
+
+	n, gen := t.walkSnapshot(func(root *Node[T]) *Node[T] {
+		return prefixSubtree(root, prefix)
+	})
+
+	if n == nil {
+		return nil
+	}
+
+	_, err := walkRec(n, gen, t, fn)
+
+	return err
+}
+
+// prefixSubtree returns the topmost node, whose full key starts with
+// the prefix, or nil. The children of a node could start with the same
+// byte – eg. the escaped \{ and \} –, so the child is chosen by the
+// longest common prefix, just like by findExactRec.
+func prefixSubtree[T storeValue](n *Node[T], prefix string) *Node[T] {
+	for n != nil {
+		lcp := longestCommonPrefix(n.key, prefix)
+
+		if lcp == len(prefix) {
+			return n
+		}
+
+		if lcp < len(n.key) {
+			return nil
+		}
+
+		prefix = prefix[lcp:]
+
+		var (
+			next    *Node[T]
+			longest = 0
+		)
+
+		for _, ch := range n.children {
+			if l := longestCommonPrefix(ch.key, prefix); l > longest {
+				next, longest = ch, l
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		n = next
+	}
+
+	return nil
+}
+
+// walkRec returns whether the walk should be stopped.
+func walkRec[T storeValue](n *Node[T], gen uint64, t *Tree[T], fn WalkFunc[T]) (bool, error) {
+	verdict := fn(n)
+
+	if t.Generation() != gen {
+		return true, ErrConcurrentModification
+	}
+
+	switch verdict {
+	case Stop:
+		return true, nil
+	case SkipSubtree:
+		return false, nil
+	}
+
+	for _, ch := range n.children {
+		if stop, err := walkRec(ch, gen, t, fn); stop {
+			return true, err
+		}
+	}
+
+	return false, nil
+}
+
+// DepthWalkFunc is similar to WalkFunc, but it also gets the depth
+// of the visited node – the depth of the root is 0.
+type DepthWalkFunc[T storeValue] func(n *Node[T], depth int) WalkVerdict
+
+type depthNode[T storeValue] struct {
+	node  *Node[T]
+	depth int
+}
+
+// WalkBFS visits all the nodes of the tree in level-order, pruned by the
+// verdicts of the callback, just like Walk. It is useful for visualizers
+// and breadth-limited dumps. It walks a snapshot as well.
+func (t *Tree[T]) WalkBFS(fn DepthWalkFunc[T]) error {
+	if err := checkTree(t); err != nil {
+		return err
+	}
+
+	root, gen := t.walkSnapshot(func(root *Node[T]) *Node[T] { return root })
+	if root == nil {
+		return nil
+	}
+
+	queue := []depthNode[T]{{node: root, depth: 0}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		verdict := fn(current.node, current.depth)
+
+		if t.Generation() != gen {
+			return ErrConcurrentModification
+		}
+
+		switch verdict {
+		case Stop:
+			return nil
+		case SkipSubtree:
+			continue
+		}
+
+		for _, ch := range current.node.children {
+			queue = append(queue, depthNode[T]{node: ch, depth: current.depth + 1})
+		}
+	}
+
+	return nil
+}
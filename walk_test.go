@@ -0,0 +1,285 @@
+package rtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGeneration(t *testing.T) {
+	tree := New[*Route]()
+
+	if got := tree.Generation(); got != 0 {
+		t.Errorf("expected generation: %d; got: %d\n", 0, got)
+	}
+
+	if err := tree.Insert("/foo", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/foo/bar", getRoute()); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	// Unsuccessful insert must not bump the generation.
+	if err := tree.Insert("/foo", getRoute()); err == nil {
+		t.Fatal("expected error, but got <nil>")
+	}
+
+	if got := tree.Generation(); got != 2 {
+		t.Errorf("expected generation: %d; got: %d\n", 2, got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	type testCase struct {
+		name          string
+		getTree       getTreeFn
+		getWalkFn     func(tree *Tree[*Route], visited *[]string) WalkFunc[*Route]
+		expectedKeys  []string
+		expectedError error
+	}
+
+	getTree := func(t *testing.T) *Tree[*Route] {
+		tree := New[*Route]()
+
+		for _, r := range []string{"/foo/bar", "/foo/baz", "/foo", "/qux"} {
+			if err := tree.Insert(r, getRoute()); err != nil {
+				t.Fatalf("not expected error, but got: %v\n", err)
+			}
+		}
+
+		return tree
+	}
+
+	withVerdict := func(verdicts map[string]WalkVerdict) func(tree *Tree[*Route], visited *[]string) WalkFunc[*Route] {
+		return func(tree *Tree[*Route], visited *[]string) WalkFunc[*Route] {
+			return func(n *Node[*Route]) WalkVerdict {
+				*visited = append(*visited, n.Key())
+				return verdicts[n.Key()]
+			}
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:          "error if tree is <nil>",
+			getTree:       func(t *testing.T) *Tree[*Route] { return nil },
+			getWalkFn:     withVerdict(nil),
+			expectedKeys:  nil,
+			expectedError: ErrTreeIsNil,
+		},
+		{
+			name:          "visits all the nodes in pre-order",
+			getTree:       getTree,
+			getWalkFn:     withVerdict(nil),
+			expectedKeys:  []string{"/", "foo", "/ba", "r", "z", "qux"},
+			expectedError: nil,
+		},
+		{
+			name:          "skips the subtree of the node",
+			getTree:       getTree,
+			getWalkFn:     withVerdict(map[string]WalkVerdict{"foo": SkipSubtree}),
+			expectedKeys:  []string{"/", "foo", "qux"},
+			expectedError: nil,
+		},
+		{
+			name:          "stops the whole walk",
+			getTree:       getTree,
+			getWalkFn:     withVerdict(map[string]WalkVerdict{"/ba": Stop}),
+			expectedKeys:  []string{"/", "foo", "/ba"},
+			expectedError: nil,
+		},
+		{
+			name:    "stops on mutation during the walk",
+			getTree: getTree,
+			getWalkFn: func(tree *Tree[*Route], visited *[]string) WalkFunc[*Route] {
+				return func(n *Node[*Route]) WalkVerdict {
+					*visited = append(*visited, n.Key())
+
+					if err := tree.Insert("/new", getRoute()); err != nil {
+						t.Fatalf("not expected error, but got: %v\n", err)
+					}
+
+					return Continue
+				}
+			},
+			expectedKeys:  []string{"/"},
+			expectedError: ErrConcurrentModification,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				tree    = tc.getTree(t)
+				visited []string
+			)
+
+			err := tree.Walk(tc.getWalkFn(tree, &visited))
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected error: %v; got: %v\n", tc.expectedError, err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedKeys, visited) {
+				t.Errorf("expected visited: %v; got: %v\n", tc.expectedKeys, visited)
+			}
+		})
+	}
+}
+
+func TestWalkPrefix(t *testing.T) {
+	type testCase struct {
+		name          string
+		prefix        string
+		verdicts      map[string]WalkVerdict
+		expectedKeys  []string
+		expectedError error
+	}
+
+	tree := New(WithCaseInsensitive[*Route]())
+
+	for _, r := range []string{"/api/users", "/api/users/{id}", "/api/users/{name}/posts", "/api/posts", "/health", `/a/\{x`, `/a/\}y`} {
+		if err := tree.Insert(r, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:         "empty prefix walks the whole tree",
+			prefix:       "",
+			expectedKeys: []string{"/", "health", "a", "pi/", "users", "/{", "name}/posts", "id}", "posts", "/", `\{x`, `\}y`},
+		},
+		{
+			name:         "prefix ending at a node",
+			prefix:       "/api/users",
+			expectedKeys: []string{"users", "/{", "name}/posts", "id}"},
+		},
+		{
+			name:         "prefix ending inside of a node",
+			prefix:       "/api/us",
+			expectedKeys: []string{"users", "/{", "name}/posts", "id}"},
+		},
+		{
+			name:         "prefix ending inside of a param",
+			prefix:       "/api/users/{",
+			expectedKeys: []string{"/{", "name}/posts", "id}"},
+		},
+		{
+			name:         "prefix of a single param",
+			prefix:       "/api/users/{name}",
+			expectedKeys: []string{"name}/posts"},
+		},
+		{
+			name:         "prefix is normalized",
+			prefix:       "/API/Posts",
+			expectedKeys: []string{"posts"},
+		},
+		{
+			name:         "stops the whole walk",
+			prefix:       "/api/users/{",
+			verdicts:     map[string]WalkVerdict{"name}/posts": Stop},
+			expectedKeys: []string{"/{", "name}/posts"},
+		},
+		{
+			name:   "no node with the prefix",
+			prefix: "/api/comments",
+		},
+		{
+			name:         "children starting with the same escape",
+			prefix:       `/a/\}`,
+			expectedKeys: []string{`\}y`},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var visited []string
+
+			err := tree.WalkPrefix(tc.prefix, func(n *Node[*Route]) WalkVerdict {
+				visited = append(visited, n.Key())
+				return tc.verdicts[n.Key()]
+			})
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected error: %v; got: %v\n", tc.expectedError, err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedKeys, visited) {
+				t.Errorf("expected visited: %v; got: %v\n", tc.expectedKeys, visited)
+			}
+		})
+	}
+
+	var nilTree *Tree[*Route]
+
+	if err := nilTree.WalkPrefix("/api", func(*Node[*Route]) WalkVerdict { return Continue }); !errors.Is(err, ErrTreeIsNil) {
+		t.Errorf("expected error: %v; got: %v\n", ErrTreeIsNil, err)
+	}
+}
+
+func TestWalkBFS(t *testing.T) {
+	type visit struct {
+		key   string
+		depth int
+	}
+
+	type testCase struct {
+		name           string
+		verdicts       map[string]WalkVerdict
+		expectedVisits []visit
+	}
+
+	tree := New[*Route]()
+
+	for _, r := range []string{"/foo/bar", "/foo/baz", "/foo", "/qux"} {
+		if err := tree.Insert(r, getRoute()); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	tt := []testCase{
+		{
+			name:     "visits all the nodes in level-order",
+			verdicts: nil,
+			expectedVisits: []visit{
+				{"/", 0}, {"foo", 1}, {"qux", 1}, {"/ba", 2}, {"r", 3}, {"z", 3},
+			},
+		},
+		{
+			name:     "skips the subtree of the node",
+			verdicts: map[string]WalkVerdict{"foo": SkipSubtree},
+			expectedVisits: []visit{
+				{"/", 0}, {"foo", 1}, {"qux", 1},
+			},
+		},
+		{
+			name:     "stops the whole walk",
+			verdicts: map[string]WalkVerdict{"qux": Stop},
+			expectedVisits: []visit{
+				{"/", 0}, {"foo", 1}, {"qux", 1},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var visits []visit
+
+			err := tree.WalkBFS(func(n *Node[*Route], depth int) WalkVerdict {
+				visits = append(visits, visit{key: n.Key(), depth: depth})
+				return tc.verdicts[n.Key()]
+			})
+
+			if err != nil {
+				t.Errorf("not expected error, but got: %v\n", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedVisits, visits) {
+				t.Errorf("expected visits: %v; got: %v\n", tc.expectedVisits, visits)
+			}
+		})
+	}
+}
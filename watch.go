@@ -0,0 +1,133 @@
+package rtree
+
+import "context"
+
+// watchBufferSize is the number of events buffered for a single watcher.
+const watchBufferSize = 64
+
+// ChangeKind is the kind of a mutation of the route table.
+type ChangeKind uint8
+
+const (
+	ChangeInsert ChangeKind = iota
+	ChangeUpdate
+	ChangeDelete
+	// ChangeExpire is the removal of a route past its sunset.
+	ChangeExpire
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeInsert:
+		return "insert"
+	case ChangeUpdate:
+		return "update"
+	case ChangeDelete:
+		return "delete"
+	case ChangeExpire:
+		return "expire"
+	default:
+		return "unknown"
+	}
+}
+
+// ChangeEvent describes a single mutation of the route table. The value
+// of an alias is the value it resolves to, while the value of a deleted
+// route is the value it had before the deletion.
+type ChangeEvent[T storeValue] struct {
+	Kind       ChangeKind
+	Key        string
+	Value      T
+	RouteID    uint64
+	Generation uint64
+}
+
+type watcher[T storeValue] struct {
+	events chan ChangeEvent[T]
+	// done is closed, when the watcher is dropped.
+	done chan struct{}
+}
+
+// Watch returns a buffered channel of the mutations of the tree, in the
+// order of their happening, and the function stopping the watch. The
+// channel is closed, when the context is cancelled or the watch is stopped
+// – the function could be called any number of times. Since the mutations
+// never wait for the watchers, a watcher whose buffer is full is dropped
+// – its channel is closed –, so it could resync from a snapshot, instead
+// of silently missing events. A watcher, that stops reading its channel,
+// must stop the watch, unless its context is cancelled.
+func (t *Tree[T]) Watch(ctx context.Context) (<-chan ChangeEvent[T], func()) {
+	w := &watcher[T]{
+		events: make(chan ChangeEvent[T], watchBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	if t == nil {
+		close(w.events)
+		return w.events, func() {}
+	}
+
+	t.mu.Lock()
+
+	if t.watchers == nil {
+		t.watchers = make(map[*watcher[T]]struct{})
+	}
+
+	t.watchers[w] = struct{}{}
+
+	t.mu.Unlock()
+
+	stop := func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.unwatch(w)
+	}
+
+	// The goroutine ends with the watcher, however it is dropped.
+	go func() {
+		select {
+		case <-ctx.Done():
+			stop()
+		case <-w.done:
+		}
+	}()
+
+	return w.events, stop
+}
+
+// notify sends the event of the mutation to every watcher.
+// The caller must hold the write lock.
+func (t *Tree[T]) notify(kind ChangeKind, nv *NodeValue[T], value T) {
+	if len(t.watchers) == 0 {
+		return
+	}
+
+	ev := ChangeEvent[T]{
+		Kind:       kind,
+		Key:        nv.Pattern(),
+		Value:      value,
+		RouteID:    nv.id,
+		Generation: t.generation.Load(),
+	}
+
+	for w := range t.watchers {
+		select {
+		case w.events <- ev:
+		default:
+			t.unwatch(w)
+		}
+	}
+}
+
+// unwatch drops the watcher, if it is not dropped yet.
+// The caller must hold the write lock.
+func (t *Tree[T]) unwatch(w *watcher[T]) {
+	if _, exists := t.watchers[w]; !exists {
+		return
+	}
+
+	delete(t.watchers, w)
+	close(w.events)
+	close(w.done)
+}
@@ -0,0 +1,155 @@
+package rtree
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type watchedEvent struct {
+	kind  ChangeKind
+	key   string
+	value string
+}
+
+func TestWatch(t *testing.T) {
+	tree := New[string]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, _ := tree.Watch(ctx)
+
+	mutations := []func() error{
+		func() error { return tree.Insert("/api/users/{id}", "users") },
+		func() error { return tree.Upsert("/api/users/{id}", "users-v2") },
+		func() error { return tree.Upsert("/api/products", "products") },
+		func() error { return tree.Alias("/api/users/{id}", "/api/people/{id}") },
+		func() error { return tree.DeleteCascade("/api/users/{id}") },
+		func() error { return tree.Delete("/api/products") },
+	}
+
+	for _, m := range mutations {
+		if err := m(); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	expected := []watchedEvent{
+		{kind: ChangeInsert, key: "/api/users/{id}", value: "users"},
+		{kind: ChangeUpdate, key: "/api/users/{id}", value: "users-v2"},
+		{kind: ChangeInsert, key: "/api/products", value: "products"},
+		{kind: ChangeInsert, key: "/api/people/{id}", value: "users-v2"},
+		{kind: ChangeDelete, key: "/api/people/{id}", value: "users-v2"},
+		{kind: ChangeDelete, key: "/api/users/{id}", value: "users-v2"},
+		{kind: ChangeDelete, key: "/api/products", value: "products"},
+	}
+
+	var lastGeneration uint64
+
+	for i, exp := range expected {
+		ev := <-events
+
+		got := watchedEvent{kind: ev.Kind, key: ev.Key, value: ev.Value}
+
+		if got != exp {
+			t.Errorf("expected event #%d: %+v; got: %+v\n", i, exp, got)
+		}
+
+		if ev.Generation <= lastGeneration {
+			t.Errorf("expected increasing generation; got: %d after %d\n", ev.Generation, lastGeneration)
+		}
+
+		lastGeneration = ev.Generation
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected no more events")
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the channel to be closed after the cancellation")
+	}
+}
+
+func TestWatchOverflow(t *testing.T) {
+	tree := New[string]()
+
+	events, stop := tree.Watch(context.Background())
+	defer stop()
+
+	for i := 0; i <= watchBufferSize; i++ {
+		if err := tree.Upsert("/api/users", "users"); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	received := 0
+
+	for range events {
+		received++
+	}
+
+	if received != watchBufferSize {
+		t.Errorf("expected events before the drop: %d; got: %d\n", watchBufferSize, received)
+	}
+}
+
+func TestWatchStop(t *testing.T) {
+	tree := New[string]()
+
+	before := runtime.NumGoroutine()
+
+	events, stop := tree.Watch(context.Background())
+
+	stop()
+	stop()
+
+	if _, ok := <-events; ok {
+		t.Errorf("expected the channel to be closed after the stop\n")
+	}
+
+	// A watcher dropped by its full buffer ends its goroutine as well.
+	dropped, _ := tree.Watch(context.Background())
+
+	for i := 0; i <= watchBufferSize; i++ {
+		if err := tree.Upsert("/api/users", "users"); err != nil {
+			t.Fatalf("not expected error, but got: %v\n", err)
+		}
+	}
+
+	for range dropped {
+	}
+
+	if len(tree.watchers) != 0 {
+		t.Errorf("expected no watchers; got: %d\n", len(tree.watchers))
+	}
+
+	deadline := time.Now().Add(time.Second)
+
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := runtime.NumGoroutine(); got > before {
+		t.Errorf("expected goroutines: %d; got: %d\n", before, got)
+	}
+}
+
+func TestChangeKindString(t *testing.T) {
+	for kind, expected := range map[ChangeKind]string{
+		ChangeInsert:   "insert",
+		ChangeUpdate:   "update",
+		ChangeDelete:   "delete",
+		ChangeExpire:   "expire",
+		ChangeKind(42): "unknown",
+	} {
+		if got := kind.String(); got != expected {
+			t.Errorf("expected: %s; got: %s\n", expected, got)
+		}
+	}
+}
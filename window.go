@@ -0,0 +1,68 @@
+package rtree
+
+import (
+	"fmt"
+	"time"
+)
+
+var errBadWindow = fmt.Errorf("[rtree %s]: bad active window", version)
+
+// activeWindow is the time window, that the route is active in.
+// A zero bound means no bound on that side.
+type activeWindow struct {
+	from time.Time
+	to   time.Time
+}
+
+// InsertWindowed stores the key-value pair as a route, that is only
+// matched by the lookups within the given time window – from inclusive,
+// to exclusive –, eg. a landing page of a campaign, or a maintenance
+// page. A zero bound means no bound on that side. Outside of its window
+// the route is skipped, as if it was not stored, so the lookups fall
+// back to the other routes. The lookup caches are bypassed by the trees
+// with windowed routes, since the routes change without mutations.
+func (t *Tree[T]) InsertWindowed(key string, value T, from, to time.Time, opts ...RouteOption) error {
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return fmt.Errorf("%w: %s is not after %s", errBadWindow, to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+
+	if t == nil {
+		return ErrTreeIsNil
+	}
+
+	// The caches are bypassed before the route could be found at all.
+	t.windowed.Store(true)
+
+	return t.insert(key, value, opts, func(nv *NodeValue[T]) {
+		nv.meta.window = &activeWindow{from: from, to: to}
+	})
+}
+
+// ActiveWindow returns the time window of the route, and
+// false, if the route is active regardless of the time.
+func (nv *NodeValue[T]) ActiveWindow() (from, to time.Time, ok bool) {
+	if nv.meta.window == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return nv.meta.window.from, nv.meta.window.to, true
+}
+
+// contains reports whether the given time is within the window.
+func (w *activeWindow) contains(at time.Time) bool {
+	if !w.from.IsZero() && at.Before(w.from) {
+		return false
+	}
+
+	return w.to.IsZero() || at.Before(w.to)
+}
+
+// inactive reports whether the lookups skip the route at the
+// moment: it is disabled, or it is out of its active window.
+func (rm *routeMeta) inactive() bool {
+	if rm.disabled {
+		return true
+	}
+
+	return rm.window != nil && !rm.window.contains(now())
+}
@@ -0,0 +1,82 @@
+package rtree
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInsertWindowed(t *testing.T) {
+	var (
+		start = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		end   = start.Add(24 * time.Hour)
+
+		current time.Time
+	)
+
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	tree := New[*Route](WithLookupCache[*Route](16), WithPrefixCache[*Route](16))
+
+	if err := tree.InsertWindowed("/promo", &Route{name: "promo"}, start, end); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.InsertWindowed("/maintenance", &Route{name: "maintenance"}, time.Time{}, end); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	if err := tree.Insert("/{page}", &Route{name: "page"}); err != nil {
+		t.Fatalf("not expected error, but got: %v\n", err)
+	}
+
+	tt := []struct {
+		name         string
+		at           time.Time
+		key          string
+		expectedName string
+	}{
+		{name: "before the window", at: start.Add(-time.Second), key: "/promo", expectedName: "page"},
+		{name: "start of the window", at: start, key: "/promo", expectedName: "promo"},
+		{name: "within the window", at: start.Add(time.Hour), key: "/promo", expectedName: "promo"},
+		{name: "end of the window", at: end, key: "/promo", expectedName: "page"},
+		{name: "without start", at: start.Add(-time.Hour), key: "/maintenance", expectedName: "maintenance"},
+		{name: "without start, after the end", at: end.Add(time.Hour), key: "/maintenance", expectedName: "page"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			current = tc.at
+
+			// Looked up twice, so a cached result would be found by the second one.
+			for i := 0; i < 2; i++ {
+				fn := tree.Find(tc.key)
+				if fn == nil || fn.GetValue().name != tc.expectedName {
+					t.Fatalf("expected: %s; got: %v\n", tc.expectedName, fn)
+				}
+			}
+		})
+	}
+
+	current = start.Add(-time.Second)
+
+	if fn := tree.FindLongestMatch("/promo/summer"); fn != nil {
+		t.Errorf("expected no longest match before the window; got: %s\n", fn.GetPattern())
+	}
+
+	current = start
+
+	if fn := tree.FindLongestMatch("/promo/summer"); fn == nil || fn.GetPattern() != "/promo" {
+		t.Errorf("expected the longest match within the window; got: %v\n", fn)
+	}
+
+	leaf, _ := tree.findNode("/promo")
+	if from, to, ok := leaf.value.ActiveWindow(); !ok || !from.Equal(start) || !to.Equal(end) {
+		t.Errorf("expected the window: %v - %v; got: %v - %v\n", start, end, from, to)
+	}
+
+	if err := tree.InsertWindowed("/sale", getRoute(), end, start); !errors.Is(err, errBadWindow) {
+		t.Errorf("expected error: %v; got: %v\n", errBadWindow, err)
+	}
+}